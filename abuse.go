@@ -0,0 +1,351 @@
+package rdap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/registrobr/rdap/protocol"
+)
+
+// List of places where the abuse contact can be found
+const (
+	// AbuseContactSourceObject the contact is an abuse entity of the queried
+	// object
+	AbuseContactSourceObject AbuseContactSource = "object"
+
+	// AbuseContactSourceLink the contact is an abuse entity of an object
+	// referenced by an "up" or "related" link
+	AbuseContactSourceLink AbuseContactSource = "link"
+
+	// AbuseContactSourceParent the contact is an abuse entity of the parent
+	// network, identified by the parentHandle member
+	AbuseContactSourceParent AbuseContactSource = "parent"
+
+	// AbuseContactSourceRegistrar the contact is the e-mail of the domain
+	// registrar, used when no abuse entity was found
+	AbuseContactSourceRegistrar AbuseContactSource = "registrar"
+)
+
+// AbuseContactSource identifies how the abuse contact was found
+type AbuseContactSource string
+
+// maxAbuseContactDepth limits the number of objects retrieved from links or
+// parent networks while looking for the abuse contact
+const maxAbuseContactDepth = 5
+
+var (
+	// ErrAbuseContactNotFound is used when none of the visited objects has an
+	// abuse contact with an e-mail address
+	ErrAbuseContactNotFound = errors.New("abuse contact not found")
+)
+
+// AbuseContact stores the e-mail address to report abuse and where it was
+// found
+type AbuseContact struct {
+	// Email is the first e-mail address of the entity jCard
+	Email string
+
+	// Source is the step of the search that found the contact
+	Source AbuseContactSource
+
+	// Object is the RDAP object that contains the entity. It is the queried
+	// object or one retrieved from a link or parent network
+	Object protocol.Object
+
+	// URI is the address used to retrieve the object, empty when it is the
+	// queried object
+	URI string
+
+	// Entity is the entity with the e-mail address
+	Entity *protocol.Entity
+
+	// Path is the location of the entity in the object as a JSON pointer
+	Path string
+}
+
+// AbuseContact will query the object (IP, IP network, ASN, domain or entity,
+// detected like in Query) and look for an abuse entity with an e-mail address
+// in the whole object tree. When the object doesn't have one, the "up" and
+// "related" links are followed, then the parent network, and for domains the
+// registrar e-mail is used as the last option. Links are retrieved with the
+// transport layer from their own servers, skipping the bootstrap. When the
+// transport layer implements ContextFetcher all the requests carry the
+// context, otherwise the context is only checked before each request. Links
+// to objects that are unavailable (not found or forbidden) are skipped, while
+// the other failures are returned. If no contact is found the error
+// ErrAbuseContactNotFound is returned
+func (c *Client) AbuseContact(ctx context.Context, object string) (*AbuseContact, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	client := &Client{
+		Transport: fetcherWithContext(c.Transport, ctx),
+		URIs:      c.URIs,
+	}
+
+	result, _, err := client.Query(object, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	r := abuseResolver{
+		client:  client,
+		ctx:     ctx,
+		visited: make(map[string]bool),
+	}
+
+	contact, err := r.resolve(result.(protocol.Object), AbuseContactSourceObject, "", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if contact == nil {
+		return nil, ErrAbuseContactNotFound
+	}
+
+	return contact, nil
+}
+
+type abuseResolver struct {
+	client  *Client
+	ctx     context.Context
+	visited map[string]bool
+}
+
+func (r *abuseResolver) resolve(object protocol.Object, source AbuseContactSource, uri string, depth int) (*AbuseContact, error) {
+	if key := objectKey(object); key != "" {
+		r.visited[key] = true
+	}
+
+	if contact := findAbuseContact(object); contact != nil {
+		contact.Source = source
+		contact.URI = uri
+		return contact, nil
+	}
+
+	if depth >= maxAbuseContactDepth {
+		return nil, nil
+	}
+
+	if source == AbuseContactSourceObject {
+		source = AbuseContactSourceLink
+	}
+
+	for _, rel := range []string{"up", "related"} {
+		for _, link := range object.GetLinks() {
+			if link.Rel != rel || (link.Type != "" && link.Type != "application/rdap+json") {
+				continue
+			}
+
+			linked, err := r.follow(link.Href)
+			if err != nil {
+				return nil, err
+			} else if linked == nil {
+				continue
+			}
+
+			contact, err := r.resolve(linked, source, link.Href, depth+1)
+			if err != nil || contact != nil {
+				return contact, err
+			}
+		}
+	}
+
+	if ipNetwork, ok := object.(*protocol.IPNetwork); ok && ipNetwork.ParentHandle != "" {
+		parent, err := r.parent(ipNetwork)
+		if err != nil {
+			return nil, err
+		}
+
+		if parent != nil {
+			contact, err := r.resolve(parent, AbuseContactSourceParent, "", depth+1)
+			if err != nil || contact != nil {
+				return contact, err
+			}
+		}
+	}
+
+	if domain, ok := object.(*protocol.Domain); ok {
+		for _, match := range domain.FindEntities(protocol.RoleRegistrar) {
+			if emails := match.Entity.Emails(); len(emails) > 0 {
+				return &AbuseContact{
+					Email:  emails[0],
+					Source: AbuseContactSourceRegistrar,
+					Object: object,
+					URI:    uri,
+					Entity: match.Entity,
+					Path:   match.Path,
+				}, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// follow retrieves the object referenced by the link. Links that aren't RDAP
+// queries, already visited objects and unavailable objects are ignored,
+// returning nil
+func (r *abuseResolver) follow(href string) (protocol.Object, error) {
+	if err := r.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if r.visited[href] {
+		return nil, nil
+	}
+	r.visited[href] = true
+
+	uri, queryType, queryValue, ok := parseQueryURI(href)
+	if !ok {
+		return nil, nil
+	}
+
+	var queryString url.Values
+	if u, err := url.Parse(href); err == nil {
+		queryString = u.Query()
+	}
+
+	resp, err := directFetcher(r.client.Transport).Fetch([]string{uri}, queryType, queryValue, nil, queryString)
+	defer func() {
+		if resp != nil && resp.Body != nil {
+			resp.Body.Close()
+		}
+	}()
+
+	if unavailable(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("link “%s”: %w", href, err)
+	}
+
+	object, err := protocol.Decode(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("link “%s”: %w", href, err)
+	}
+
+	if key := objectKey(object); key != "" && r.visited[key] {
+		return nil, nil
+	}
+
+	return object, nil
+}
+
+// parent retrieves the IP network that covers the given one (see
+// protocol.IPNetwork.ParentPrefix). Unavailable parent networks are ignored,
+// returning nil
+func (r *abuseResolver) parent(ipNetwork *protocol.IPNetwork) (protocol.Object, error) {
+	if err := r.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	prefix, ok := ipNetwork.ParentPrefix()
+	if !ok {
+		return nil, nil
+	}
+
+	parent, _, err := r.client.IPNetwork(prefix, nil, nil)
+	if unavailable(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("parent network “%s”: %w", prefix, err)
+	}
+
+	if key := objectKey(parent); key != "" && r.visited[key] {
+		return nil, nil
+	}
+
+	return parent, nil
+}
+
+// unavailable checks if the object doesn't exist or isn't available to the
+// client, so the search can continue in the other objects
+func unavailable(err error) bool {
+	return errors.Is(err, ErrNotFound) || errors.Is(err, ErrForbidden)
+}
+
+// findAbuseContact returns the first abuse entity with an e-mail address in
+// the object tree
+func findAbuseContact(object protocol.Object) *AbuseContact {
+	finder, ok := object.(interface {
+		FindEntities(protocol.Role) []protocol.EntityMatch
+	})
+	if !ok {
+		return nil
+	}
+
+	for _, match := range finder.FindEntities(protocol.RoleAbuse) {
+		if emails := match.Entity.Emails(); len(emails) > 0 {
+			return &AbuseContact{
+				Email:  emails[0],
+				Object: object,
+				Entity: match.Entity,
+				Path:   match.Path,
+			}
+		}
+	}
+
+	return nil
+}
+
+// objectKey identifies the object to avoid visiting it more than once, by the
+// handle or else by the self link. Objects without both aren't identified,
+// returning an empty key, as the links are already tracked by their URLs
+func objectKey(object protocol.Object) string {
+	if handle := object.GetHandle(); handle != "" {
+		return fmt.Sprintf("%T %s", object, handle)
+	}
+
+	for _, link := range object.GetLinks() {
+		if link.Rel == "self" && link.Href != "" {
+			return link.Href
+		}
+	}
+
+	return ""
+}
+
+// parseQueryURI splits an RDAP query URL into the server address, the query
+// type and the query value, as expected by the Fetcher interface
+func parseQueryURI(href string) (uri string, queryType QueryType, queryValue string, ok bool) {
+	u, err := url.Parse(href)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return
+	}
+
+	segments := strings.Split(strings.TrimRight(u.EscapedPath(), "/"), "/")
+	for i := len(segments) - 2; i >= 0; i-- {
+		queryType = QueryType(segments[i])
+
+		switch queryType {
+		case QueryTypeDomain, QueryTypeAutnum, QueryTypeEntity:
+			if i != len(segments)-2 {
+				continue
+			}
+		case QueryTypeIP:
+			if i < len(segments)-3 {
+				continue
+			}
+		default:
+			continue
+		}
+
+		if queryValue, err = url.PathUnescape(strings.Join(segments[i+1:], "/")); err != nil {
+			return
+		}
+
+		u.RawPath = strings.Join(segments[:i], "/")
+		if u.Path, err = url.PathUnescape(u.RawPath); err != nil {
+			return
+		}
+
+		u.RawQuery, u.Fragment = "", ""
+		return u.String(), queryType, queryValue, true
+	}
+
+	return "", "", "", false
+}
@@ -0,0 +1,441 @@
+package rdap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/registrobr/rdap/protocol"
+)
+
+func TestClientAbuseContact(t *testing.T) {
+	abuse := func(handle, email string) protocol.Entity {
+		return protocol.Entity{
+			ObjectClassName: "entity",
+			Handle:          handle,
+			Roles:           []string{"abuse"},
+			VCardArray: []any{"vcard", []any{
+				[]any{"version", map[string]any{}, "text", "4.0"},
+				[]any{"email", map[string]any{}, "text", email},
+			}},
+		}
+	}
+
+	data := []struct {
+		description    string
+		ctx            context.Context
+		object         string
+		objects        map[string]any
+		expectedEmail  string
+		expectedSource AbuseContactSource
+		expectedURI    string
+		expectedPath   string
+		expectedError  error
+	}{
+		{
+			description: "it should find the abuse contact in the queried object",
+			object:      "192.0.2.1",
+			objects: map[string]any{
+				"https://rdap.example.net/ip/192.0.2.1": protocol.IPNetwork{
+					ObjectClassName: "ip network",
+					Handle:          "192.0.2.0/24",
+					Entities: []protocol.Entity{
+						{
+							ObjectClassName: "entity",
+							Handle:          "OWNER",
+							Roles:           []string{"registrant"},
+							Entities:        []protocol.Entity{abuse("ABUSE", "abuse@example.net")},
+						},
+					},
+				},
+			},
+			expectedEmail:  "abuse@example.net",
+			expectedSource: AbuseContactSourceObject,
+			expectedPath:   "/entities/0/entities/0",
+		},
+		{
+			description: "it should follow the up link",
+			object:      "192.0.2.0/24",
+			objects: map[string]any{
+				"https://rdap.example.net/ip/192.0.2.0/24": protocol.IPNetwork{
+					ObjectClassName: "ip network",
+					Handle:          "192.0.2.0/24",
+					Links: []protocol.Link{
+						{Rel: "self", Href: "https://rdap.example.net/ip/192.0.2.0/24"},
+						{Rel: "up", Href: "https://rdap.example.net/ip/192.0.0.0/16", Type: "application/rdap+json"},
+					},
+				},
+				"https://rdap.example.net/ip/192.0.0.0/16": protocol.IPNetwork{
+					ObjectClassName: "ip network",
+					Handle:          "192.0.0.0/16",
+					Entities:        []protocol.Entity{abuse("ABUSE", "abuse@example.net")},
+				},
+			},
+			expectedEmail:  "abuse@example.net",
+			expectedSource: AbuseContactSourceLink,
+			expectedURI:    "https://rdap.example.net/ip/192.0.0.0/16",
+			expectedPath:   "/entities/0",
+		},
+		{
+			description: "it should query the parent network",
+			object:      "192.0.2.0/24",
+			objects: map[string]any{
+				"https://rdap.example.net/ip/192.0.2.0/24": protocol.IPNetwork{
+					ObjectClassName: "ip network",
+					Handle:          "192.0.2.0/24",
+					StartAddress:    "192.0.2.0",
+					EndAddress:      "192.0.2.255",
+					ParentHandle:    "192.0.0.0/16",
+				},
+				"https://rdap.example.net/ip/192.0.0.0/16": protocol.IPNetwork{
+					ObjectClassName: "ip network",
+					Handle:          "192.0.0.0/16",
+					StartAddress:    "192.0.0.0",
+					EndAddress:      "192.0.255.255",
+					Entities:        []protocol.Entity{abuse("ABUSE", "parent@example.net")},
+				},
+			},
+			expectedEmail:  "parent@example.net",
+			expectedSource: AbuseContactSourceParent,
+			expectedPath:   "/entities/0",
+		},
+		{
+			description: "it should derive the parent network from the range",
+			object:      "192.0.2.0/24",
+			objects: map[string]any{
+				"https://rdap.example.net/ip/192.0.2.0/24": protocol.IPNetwork{
+					ObjectClassName: "ip network",
+					Handle:          "NET-192-0-2-0-1",
+					StartAddress:    "192.0.2.0",
+					EndAddress:      "192.0.2.255",
+					ParentHandle:    "NET-192-0-0-0-1",
+				},
+				"https://rdap.example.net/ip/192.0.2.0/23": protocol.IPNetwork{
+					ObjectClassName: "ip network",
+					Handle:          "NET-192-0-0-0-1",
+					StartAddress:    "192.0.0.0",
+					EndAddress:      "192.0.255.255",
+					Entities:        []protocol.Entity{abuse("ABUSE", "parent@example.net")},
+				},
+			},
+			expectedEmail:  "parent@example.net",
+			expectedSource: AbuseContactSourceParent,
+			expectedPath:   "/entities/0",
+		},
+		{
+			description: "it should follow the related link of a domain",
+			object:      "example.com",
+			objects: map[string]any{
+				"https://rdap.example.net/domain/example.com": protocol.Domain{
+					ObjectClassName: "domain",
+					Handle:          "EXAMPLE-COM",
+					Links: []protocol.Link{
+						{Rel: "related", Href: "https://rdap.example.org/rdap/domain/example.com?x=1"},
+					},
+				},
+				"https://rdap.example.org/rdap/domain/example.com": protocol.Domain{
+					ObjectClassName: "domain",
+					Handle:          "EXAMPLE-COM-REGISTRAR",
+					Entities: []protocol.Entity{
+						{
+							ObjectClassName: "entity",
+							Roles:           []string{"registrar"},
+							Entities:        []protocol.Entity{abuse("ABUSE", "abuse@registrar.example")},
+						},
+					},
+				},
+			},
+			expectedEmail:  "abuse@registrar.example",
+			expectedSource: AbuseContactSourceLink,
+			expectedURI:    "https://rdap.example.org/rdap/domain/example.com?x=1",
+			expectedPath:   "/entities/0/entities/0",
+		},
+		{
+			description: "it should use the registrar e-mail",
+			object:      "example.com",
+			objects: map[string]any{
+				"https://rdap.example.net/domain/example.com": protocol.Domain{
+					ObjectClassName: "domain",
+					Handle:          "EXAMPLE-COM",
+					Links: []protocol.Link{
+						{Rel: "related", Href: "https://rdap.example.org/domain/example.com"},
+						{Rel: "related", Href: "https://www.example.org/whois", Type: "text/html"},
+					},
+					Entities: []protocol.Entity{
+						{
+							ObjectClassName: "entity",
+							Roles:           []string{"registrar"},
+							VCardArray: []any{"vcard", []any{
+								[]any{"email", map[string]any{}, "text", "registrar@example.org"},
+							}},
+						},
+					},
+				},
+			},
+			expectedEmail:  "registrar@example.org",
+			expectedSource: AbuseContactSourceRegistrar,
+			expectedPath:   "/entities/0",
+		},
+		{
+			description: "it should not loop over links",
+			object:      "64496",
+			objects: map[string]any{
+				"https://rdap.example.net/autnum/64496": protocol.AS{
+					ObjectClassName: "autnum",
+					Handle:          "AS64496",
+					Links: []protocol.Link{
+						{Rel: "up", Href: "https://rdap.example.net/autnum/64497"},
+					},
+					Entities: []protocol.Entity{
+						{ObjectClassName: "entity", Roles: []string{"abuse"}},
+					},
+				},
+				"https://rdap.example.net/autnum/64497": protocol.AS{
+					ObjectClassName: "autnum",
+					Handle:          "AS64497",
+					Links: []protocol.Link{
+						{Rel: "up", Href: "https://rdap.example.net/autnum/64496"},
+					},
+				},
+			},
+			expectedError: ErrAbuseContactNotFound,
+		},
+		{
+			description: "it should follow links between objects without handle",
+			object:      "192.0.2.0/24",
+			objects: map[string]any{
+				"https://rdap.example.net/ip/192.0.2.0/24": protocol.IPNetwork{
+					ObjectClassName: "ip network",
+					Links: []protocol.Link{
+						{Rel: "up", Href: "https://rdap.example.net/ip/192.0.0.0/16"},
+					},
+				},
+				"https://rdap.example.net/ip/192.0.0.0/16": protocol.IPNetwork{
+					ObjectClassName: "ip network",
+					Entities:        []protocol.Entity{abuse("ABUSE", "abuse@example.net")},
+				},
+			},
+			expectedEmail:  "abuse@example.net",
+			expectedSource: AbuseContactSourceLink,
+			expectedURI:    "https://rdap.example.net/ip/192.0.0.0/16",
+			expectedPath:   "/entities/0",
+		},
+		{
+			description: "it should return the failure of a link",
+			object:      "example.com",
+			objects: map[string]any{
+				"https://rdap.example.net/domain/example.com": protocol.Domain{
+					ObjectClassName: "domain",
+					Handle:          "EXAMPLE-COM",
+					Links: []protocol.Link{
+						{Rel: "related", Href: "https://rdap.example.org/domain/example.com"},
+					},
+				},
+				"https://rdap.example.org/domain/example.com": http.StatusInternalServerError,
+			},
+			expectedError: fmt.Errorf("link “https://rdap.example.org/domain/example.com”: %v", protocol.NewError(http.StatusInternalServerError)),
+		},
+		{
+			description:   "it should fail to query the object",
+			object:        "example.com",
+			expectedError: fmt.Errorf("not found"),
+		},
+		{
+			description: "it should stop when the context is canceled",
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx
+			}(),
+			object:        "example.com",
+			expectedError: context.Canceled,
+		},
+	}
+
+	for i, item := range data {
+		httpClient := httpClientFunc(func(r *http.Request) (*http.Response, error) {
+			object, ok := item.objects[r.URL.Scheme+"://"+r.URL.Host+r.URL.Path]
+			if !ok {
+				object = http.StatusNotFound
+			}
+
+			response := http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/rdap+json"}},
+			}
+
+			if status, ok := object.(int); ok {
+				response.StatusCode = status
+				object = protocol.NewError(status)
+			}
+
+			data, err := json.Marshal(object)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			response.Body = nopCloser{bytes.NewBuffer(data)}
+			return &response, nil
+		})
+
+		client := Client{
+			URIs:      []string{"https://rdap.example.net"},
+			Transport: NewDefaultFetcher(httpClient),
+		}
+
+		ctx := item.ctx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		contact, err := client.AbuseContact(ctx, item.object)
+
+		if item.expectedError != nil {
+			if fmt.Sprintf("%v", item.expectedError) != fmt.Sprintf("%v", err) {
+				t.Errorf("[%d] %s: expected error “%v”, got “%v”", i, item.description, item.expectedError, err)
+			}
+
+		} else if err != nil {
+			t.Errorf("[%d] %s: unexpected error “%s”", i, item.description, err)
+
+		} else if contact.Email != item.expectedEmail ||
+			contact.Source != item.expectedSource ||
+			contact.URI != item.expectedURI ||
+			contact.Path != item.expectedPath ||
+			contact.Entity.Emails()[0] != contact.Email {
+
+			t.Errorf("[%d] %s: unexpected contact “%s” from “%s” (%s %s)",
+				i, item.description, contact.Email, contact.Source, contact.URI, contact.Path)
+		}
+	}
+}
+
+func TestClientAbuseContactContext(t *testing.T) {
+	type contextKey struct{}
+	ctx := context.WithValue(context.Background(), contextKey{}, "abuse")
+
+	var requests []string
+	httpClient := httpClientFunc(func(r *http.Request) (*http.Response, error) {
+		requests = append(requests, r.URL.String())
+		if r.Context().Value(contextKey{}) != "abuse" {
+			t.Errorf("the context wasn't sent in the request of “%s”", r.URL)
+		}
+		return nil, context.DeadlineExceeded
+	})
+
+	client := NewClient(nil)
+	client.Transport = NewBootstrapFetcher(httpClient, IANABootstrap, nil)
+
+	if _, err := client.AbuseContact(ctx, "example.com"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("unexpected error “%v”", err)
+	}
+
+	expected := []string{fmt.Sprintf(IANABootstrap, "dns")}
+	if !reflect.DeepEqual(expected, requests) {
+		t.Errorf("expected requests “%v”, got “%v”", expected, requests)
+	}
+
+	// the links are retrieved directly, without bootstrap
+	requests = nil
+	r := abuseResolver{
+		client:  &Client{Transport: fetcherWithContext(client.Transport, ctx)},
+		ctx:     ctx,
+		visited: make(map[string]bool),
+	}
+
+	href := "https://rdap.example.org/domain/example.com"
+	expectedErr := fmt.Errorf("link “%s”: %w", href, context.DeadlineExceeded)
+
+	object, err := r.follow(href)
+	if object != nil || fmt.Sprintf("%v", expectedErr) != fmt.Sprintf("%v", err) {
+		t.Errorf("expected error “%v”, got “%v”", expectedErr, err)
+	}
+
+	if !reflect.DeepEqual([]string{href}, requests) {
+		t.Errorf("expected the link to be retrieved directly, got “%v”", requests)
+	}
+}
+
+func TestAbuseResolverFollowFetcher(t *testing.T) {
+	var fetched []string
+	r := abuseResolver{
+		client: &Client{
+			Transport: fetcherFunc(func(uris []string, queryType QueryType, queryValue string, header http.Header, queryString url.Values) (*http.Response, error) {
+				fetched = append(fetched, fmt.Sprintf("%v %s %s %s", uris, queryType, queryValue, queryString.Encode()))
+				return nil, ErrNotFound
+			}),
+		},
+		ctx:     context.Background(),
+		visited: make(map[string]bool),
+	}
+
+	object, err := r.follow("https://rdap.example.org/rdap/domain/example.com?x=1")
+	if object != nil || err != nil {
+		t.Errorf("unexpected result “%v” (%v)", object, err)
+	}
+
+	expected := []string{"[https://rdap.example.org/rdap] domain example.com x=1"}
+	if !reflect.DeepEqual(expected, fetched) {
+		t.Errorf("expected the link to be retrieved with the transport layer “%v”, got “%v”", expected, fetched)
+	}
+}
+
+func TestParseQueryURI(t *testing.T) {
+	data := []struct {
+		description        string
+		href               string
+		expectedURI        string
+		expectedQueryType  QueryType
+		expectedQueryValue string
+		expectedOK         bool
+	}{
+		{
+			description:        "it should parse a domain query",
+			href:               "https://rdap.example.net/rdap/domain/example.com",
+			expectedURI:        "https://rdap.example.net/rdap",
+			expectedQueryType:  QueryTypeDomain,
+			expectedQueryValue: "example.com",
+			expectedOK:         true,
+		},
+		{
+			description:        "it should parse an IP network query",
+			href:               "https://rdap.example.net/ip/2001:db8::/32/?a=b",
+			expectedURI:        "https://rdap.example.net",
+			expectedQueryType:  QueryTypeIP,
+			expectedQueryValue: "2001:db8::/32",
+			expectedOK:         true,
+		},
+		{
+			description:        "it should unescape the query value",
+			href:               "https://rdap.example.net/entity/ABC%2FDEF",
+			expectedURI:        "https://rdap.example.net",
+			expectedQueryType:  QueryTypeEntity,
+			expectedQueryValue: "ABC/DEF",
+			expectedOK:         true,
+		},
+		{
+			description: "it should ignore unknown query types",
+			href:        "https://rdap.example.net/nameserver/a.dns.br",
+		},
+		{
+			description: "it should ignore relative addresses",
+			href:        "/domain/example.com",
+		},
+	}
+
+	for i, item := range data {
+		uri, queryType, queryValue, ok := parseQueryURI(item.href)
+		if uri != item.expectedURI || queryType != item.expectedQueryType ||
+			queryValue != item.expectedQueryValue || ok != item.expectedOK {
+
+			t.Errorf("[%d] %s: unexpected result “%s”, “%s”, “%s”, “%t”",
+				i, item.description, uri, queryType, queryValue, ok)
+		}
+	}
+}
@@ -3,8 +3,8 @@ package rdap
 import (
 	"encoding/json"
 	"fmt"
-	"net"
 	"net/http"
+	"net/netip"
 	"net/url"
 	"regexp"
 	"strconv"
@@ -179,12 +179,12 @@ func (c *Client) Entity(identifier string, header http.Header, queryString url.V
 // If something goes wrong an error will be returned, and if nothing is found
 // the error ErrNotFound will be returned. The HTTP header of the RDAP
 // response is also returned to analyze any specific flag
-func (c *Client) IPNetwork(ipnet *net.IPNet, header http.Header, queryString url.Values) (*protocol.IPNetwork, http.Header, error) {
-	if ipnet == nil {
+func (c *Client) IPNetwork(ipnet netip.Prefix, header http.Header, queryString url.Values) (*protocol.IPNetwork, http.Header, error) {
+	if !ipnet.IsValid() {
 		return nil, nil, fmt.Errorf("undefined IP network")
 	}
 
-	resp, err := c.Transport.Fetch(c.URIs, QueryTypeIP, ipnet.String(), header, queryString)
+	resp, err := c.Transport.Fetch(c.URIs, QueryTypeIP, ipnet.Masked().String(), header, queryString)
 	defer func() {
 		if resp != nil && resp.Body != nil {
 			resp.Body.Close()
@@ -212,12 +212,12 @@ func (c *Client) IPNetwork(ipnet *net.IPNet, header http.Header, queryString url
 // If something goes wrong an error will be returned, and if nothing is found
 // the error ErrNotFound will be returned. The HTTP header of the RDAP
 // response is also returned to analyze any specific flag
-func (c *Client) IP(ip net.IP, header http.Header, queryString url.Values) (*protocol.IPNetwork, http.Header, error) {
-	if ip == nil {
+func (c *Client) IP(ip netip.Addr, header http.Header, queryString url.Values) (*protocol.IPNetwork, http.Header, error) {
+	if !ip.IsValid() {
 		return nil, nil, fmt.Errorf("undefined IP")
 	}
 
-	resp, err := c.Transport.Fetch(c.URIs, QueryTypeIP, ip.String(), header, queryString)
+	resp, err := c.Transport.Fetch(c.URIs, QueryTypeIP, ip.Unmap().String(), header, queryString)
 	defer func() {
 		if resp != nil && resp.Body != nil {
 			resp.Body.Close()
@@ -248,11 +248,11 @@ func (c *Client) Query(object string, header http.Header, queryString url.Values
 		return c.ASN(uint32(asn), header, queryString)
 	}
 
-	if ip := net.ParseIP(object); ip != nil {
+	if ip, err := netip.ParseAddr(object); err == nil && ip.Zone() == "" {
 		return c.IP(ip, header, queryString)
 	}
 
-	if _, ipnetwork, err := net.ParseCIDR(object); err == nil {
+	if ipnetwork, err := netip.ParsePrefix(object); err == nil {
 		return c.IPNetwork(ipnetwork, header, queryString)
 	}
 
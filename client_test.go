@@ -4,8 +4,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"net"
 	"net/http"
+	"net/netip"
 	"net/url"
 	"reflect"
 	"strconv"
@@ -576,7 +576,7 @@ func TestClientEntity(t *testing.T) {
 func TestClientIPNetwork(t *testing.T) {
 	data := []struct {
 		description    string
-		ipNetwork      *net.IPNet
+		ipNetwork      netip.Prefix
 		header         http.Header
 		queryString    url.Values
 		client         func() (*http.Response, error)
@@ -586,14 +586,7 @@ func TestClientIPNetwork(t *testing.T) {
 	}{
 		{
 			description: "it should return a valid IP network",
-			ipNetwork: func() *net.IPNet {
-				_, ipNetwork, err := net.ParseCIDR("200.160.0.0/20")
-				if err != nil {
-					t.Fatal(err)
-				}
-
-				return ipNetwork
-			}(),
+			ipNetwork:   netip.MustParsePrefix("200.160.0.0/20"),
 			header: http.Header{
 				"X-Forwarded-For": []string{"127.0.0.1"},
 			},
@@ -627,19 +620,25 @@ func TestClientIPNetwork(t *testing.T) {
 			},
 		},
 		{
-			description:   "it should fail for a nil input",
+			description: "it should query the network address of the prefix",
+			ipNetwork:   netip.MustParsePrefix("200.160.2.3/20"),
+			client: func() (*http.Response, error) {
+				var response http.Response
+				response.Body = nopCloser{bytes.NewBufferString(`{"objectClassName": "ip network", "handle": "200.160.0.0/20"}`)}
+				return &response, nil
+			},
+			expected: &protocol.IPNetwork{
+				ObjectClassName: "ip network",
+				Handle:          "200.160.0.0/20",
+			},
+		},
+		{
+			description:   "it should fail for an invalid input",
 			expectedError: fmt.Errorf("undefined IP network"),
 		},
 		{
 			description: "it should fail to query an IP network",
-			ipNetwork: func() *net.IPNet {
-				_, ipNetwork, err := net.ParseCIDR("200.160.0.0/20")
-				if err != nil {
-					t.Fatal(err)
-				}
-
-				return ipNetwork
-			}(),
+			ipNetwork:   netip.MustParsePrefix("200.160.0.0/20"),
 			client: func() (*http.Response, error) {
 				var response http.Response
 				response.Header = http.Header{
@@ -654,14 +653,7 @@ func TestClientIPNetwork(t *testing.T) {
 		},
 		{
 			description: "it should fail to query an IP network with no response",
-			ipNetwork: func() *net.IPNet {
-				_, ipNetwork, err := net.ParseCIDR("200.160.0.0/20")
-				if err != nil {
-					t.Fatal(err)
-				}
-
-				return ipNetwork
-			}(),
+			ipNetwork:   netip.MustParsePrefix("200.160.0.0/20"),
 			client: func() (*http.Response, error) {
 				return nil, fmt.Errorf("I'm a crazy error!")
 			},
@@ -669,14 +661,7 @@ func TestClientIPNetwork(t *testing.T) {
 		},
 		{
 			description: "it should fail to decode the IP network response",
-			ipNetwork: func() *net.IPNet {
-				_, ipNetwork, err := net.ParseCIDR("200.160.0.0/20")
-				if err != nil {
-					t.Fatal(err)
-				}
-
-				return ipNetwork
-			}(),
+			ipNetwork:   netip.MustParsePrefix("200.160.0.0/20"),
 			client: func() (*http.Response, error) {
 				var response http.Response
 				response.Body = nopCloser{bytes.NewBufferString(`{{{{`)}
@@ -707,7 +692,7 @@ func TestClientIPNetwork(t *testing.T) {
 					return nil, fmt.Errorf("expected query type “%s” and got “%s”", QueryTypeIP, queryType)
 				}
 
-				if queryValue != item.ipNetwork.String() {
+				if queryValue != item.ipNetwork.Masked().String() {
 					return nil, fmt.Errorf("expected IP network “%s” and got “%s”", item.ipNetwork, queryValue)
 				}
 
@@ -740,7 +725,7 @@ func TestClientIPNetwork(t *testing.T) {
 func TestClientIP(t *testing.T) {
 	data := []struct {
 		description    string
-		ip             net.IP
+		ip             netip.Addr
 		header         http.Header
 		queryString    url.Values
 		client         func() (*http.Response, error)
@@ -750,7 +735,7 @@ func TestClientIP(t *testing.T) {
 	}{
 		{
 			description: "it should return a valid IP network",
-			ip:          net.ParseIP("200.160.2.3"),
+			ip:          netip.MustParseAddr("200.160.2.3"),
 			header: http.Header{
 				"X-Forwarded-For": []string{"127.0.0.1"},
 			},
@@ -784,12 +769,12 @@ func TestClientIP(t *testing.T) {
 			},
 		},
 		{
-			description:   "it should fail for a nil input",
+			description:   "it should fail for an invalid input",
 			expectedError: fmt.Errorf("undefined IP"),
 		},
 		{
 			description: "it should fail to query an IP",
-			ip:          net.ParseIP("200.160.2.3"),
+			ip:          netip.MustParseAddr("200.160.2.3"),
 			client: func() (*http.Response, error) {
 				var response http.Response
 				response.Header = http.Header{
@@ -804,7 +789,7 @@ func TestClientIP(t *testing.T) {
 		},
 		{
 			description: "it should fail to query an IP with no response",
-			ip:          net.ParseIP("200.160.2.3"),
+			ip:          netip.MustParseAddr("200.160.2.3"),
 			client: func() (*http.Response, error) {
 				return nil, fmt.Errorf("I'm a crazy error!")
 			},
@@ -812,7 +797,7 @@ func TestClientIP(t *testing.T) {
 		},
 		{
 			description: "it should fail to decode the IP network response",
-			ip:          net.ParseIP("200.160.2.3"),
+			ip:          netip.MustParseAddr("200.160.2.3"),
 			client: func() (*http.Response, error) {
 				var response http.Response
 				response.Body = nopCloser{bytes.NewBufferString(`{{{{`)}
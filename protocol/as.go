@@ -1,25 +1,99 @@
 package protocol
 
+import "encoding/json"
+
 // AS describes the Autonomous System Number Entity Object Class as it is in
-// RFC 7483, section 5.5
+// RFC 9083, section 5.5
 type AS struct {
-	ObjectClassName string          `json:"objectClassName"`
-	Handle          string          `json:"handle,omitempty"`
-	StartAutnum     uint32          `json:"startAutnum"`
-	EndAutnum       uint32          `json:"endAutnum"`
-	Name            string          `json:"name,omitempty"`
-	Type            string          `json:"type"`
-	Country         string          `json:"country"`
-	Links           []Link          `json:"links,omitempty"`
-	Entities        []Entity        `json:"entities,omitempty"`
-	RoutingPolicy   []RoutingPolicy `json:"nicbr_routingPolicy,omitempty"`
-	Events          []Event         `json:"events,omitempty"`
-	Notices         []Notice        `json:"notices,omitempty"`
-	Remarks         []Remark        `json:"remarks,omitempty"`
+	ObjectClassName string   `json:"objectClassName"`
+	Handle          string   `json:"handle,omitempty"`
+	StartAutnum     uint32   `json:"startAutnum"`
+	EndAutnum       uint32   `json:"endAutnum"`
+	Name            string   `json:"name,omitempty"`
+	Type            string   `json:"type"`
+	Country         string   `json:"country"`
+	ParentHandle    string   `json:"parentHandle,omitempty"`
+	Status          []Status `json:"status,omitempty"`
+	Links           []Link   `json:"links,omitempty"`
+	Entities        []Entity `json:"entities,omitempty"`
+	Events          []Event  `json:"events,omitempty"`
+	Notices         []Notice `json:"notices,omitempty"`
+	Remarks         []Remark `json:"remarks,omitempty"`
 	Conformance
 	Port43
+
+	// RoutingPolicy is a NIC.br extension member.
+	//
+	// Deprecated: use the NICBRAS type returned by Extension(NICBRPrefix)
+	RoutingPolicy []RoutingPolicy `json:"-"`
+
+	// Extensions stores the members unknown to this library
+	Extensions map[string]json.RawMessage `json:"-"`
 }
 
+// asJSON avoids the recursion of the AS JSON methods
+type asJSON AS
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Members unknown to
+// the AS type are stored in the Extensions attribute
+func (a *AS) UnmarshalJSON(data []byte) error {
+	if err := unmarshalObject(data, (*asJSON)(a), &a.Extensions); err != nil {
+		return err
+	}
+
+	return a.loadNICBR()
+}
+
+// MarshalJSON implements the json.Marshaler interface. The members from the
+// Extensions attribute are written after the known members in alphabetical
+// order
+func (a AS) MarshalJSON() ([]byte, error) {
+	extensions, err := a.storeNICBR()
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalObject(asJSON(a), extensions)
+}
+
+// Extension decodes the members of the registered extension identified by the
+// prefix into the type that the extension defines for the autnum object
+// class. If the object doesn't have any member of the extension, nil is
+// returned
+func (a *AS) Extension(prefix string) (any, error) {
+	return decodeExtension(ObjectClassAutnum, prefix, a.Extensions)
+}
+
+// SetExtension replaces the members of the registered extension identified by
+// the prefix with the ones encoded from value
+func (a *AS) SetExtension(prefix string, value any) error {
+	extensions, err := encodeExtension(prefix, a.Extensions, value)
+	if err != nil {
+		return err
+	}
+
+	a.Extensions = extensions
+	return a.loadNICBR()
+}
+
+// GetHandle implements the Object interface
+func (a *AS) GetHandle() string { return a.Handle }
+
+// GetLinks implements the Object interface
+func (a *AS) GetLinks() []Link { return a.Links }
+
+// GetEvents implements the Object interface
+func (a *AS) GetEvents() []Event { return a.Events }
+
+// GetEntities implements the Object interface
+func (a *AS) GetEntities() []Entity { return a.Entities }
+
+// GetRemarks implements the Object interface
+func (a *AS) GetRemarks() []Remark { return a.Remarks }
+
+// GetNotices implements the Object interface
+func (a *AS) GetNotices() []Notice { return a.Notices }
+
 // RoutingPolicy is a NIC.br extension that stores the information of network
 // announces
 type RoutingPolicy struct {
@@ -0,0 +1,83 @@
+package protocol
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestASUnmarshalJSON(t *testing.T) {
+	// example from RFC 9083, section 5.5 (without remarks and entities to keep
+	// it short) with a parent handle
+	input := `{
+  "objectClassName" : "autnum",
+  "handle" : "XXXX-RIR",
+  "startAutnum" : 65536,
+  "endAutnum" : 65541,
+  "name": "AS-RTR-1",
+  "type" : "DIRECT ALLOCATION",
+  "status" : [ "active" ],
+  "country": "AU",
+  "parentHandle": "YYYY-RIR",
+  "links" :
+  [
+    {
+      "value" : "https://example.net/autnum/65537",
+      "rel" : "self",
+      "href" : "https://example.net/autnum/65537",
+      "type" : "application/rdap+json"
+    }
+  ],
+  "events" :
+  [
+    {
+      "eventAction" : "registration",
+      "eventDate" : "1990-12-31T23:59:59Z"
+    },
+    {
+      "eventAction" : "last changed",
+      "eventDate" : "1991-12-31T23:59:59Z"
+    }
+  ]
+}`
+
+	expected := AS{
+		ObjectClassName: "autnum",
+		Handle:          "XXXX-RIR",
+		StartAutnum:     65536,
+		EndAutnum:       65541,
+		Name:            "AS-RTR-1",
+		Type:            "DIRECT ALLOCATION",
+		Status:          []Status{StatusActive},
+		Country:         "AU",
+		ParentHandle:    "YYYY-RIR",
+		Links: []Link{
+			{
+				Value: "https://example.net/autnum/65537",
+				Rel:   "self",
+				Href:  "https://example.net/autnum/65537",
+				Type:  "application/rdap+json",
+			},
+		},
+		Events: []Event{
+			{
+				Action: EventActionRegistration,
+				Date:   Date(1990, 12, 31, 23, 59, 59, 0, time.UTC),
+			},
+			{
+				Action: EventActionLastChanged,
+				Date:   Date(1991, 12, 31, 23, 59, 59, 0, time.UTC),
+			},
+		},
+	}
+
+	var as AS
+	if err := json.Unmarshal([]byte(input), &as); err != nil {
+		t.Fatalf("Unexpected error “%s”", err)
+	}
+
+	if !reflect.DeepEqual(expected, as) {
+		t.Errorf("Unexpected AS. Expected “%#v” and got “%#v”", expected, as)
+	}
+}
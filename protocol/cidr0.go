@@ -0,0 +1,163 @@
+package protocol
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+)
+
+const (
+	// CIDR0Prefix is the prefix of the cidr0 extension members
+	CIDR0Prefix = "cidr0"
+
+	// CIDR0Identifier is the conformance value of the cidr0 extension
+	CIDR0Identifier = "cidr0"
+)
+
+// CIDR0 describes one item of the cidr0_cidrs member, that lists the
+// prefixes of an IP network. Only one of V4Prefix or V6Prefix is filled
+type CIDR0 struct {
+	V4Prefix string `json:"v4prefix,omitempty"`
+	V6Prefix string `json:"v6prefix,omitempty"`
+	Length   int    `json:"length"`
+}
+
+// Prefix parses the CIDR0 item into a prefix
+func (c CIDR0) Prefix() (netip.Prefix, error) {
+	var address string
+	switch {
+	case c.V4Prefix != "" && c.V6Prefix != "":
+		return netip.Prefix{}, fmt.Errorf("cidr0 item with both v4prefix and v6prefix")
+	case c.V4Prefix != "":
+		address = c.V4Prefix
+	case c.V6Prefix != "":
+		address = c.V6Prefix
+	default:
+		return netip.Prefix{}, fmt.Errorf("cidr0 item without prefix")
+	}
+
+	addr, err := netip.ParseAddr(address)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+
+	if (c.V4Prefix != "" && !addr.Is4()) || (c.V6Prefix != "" && !addr.Is6()) {
+		return netip.Prefix{}, fmt.Errorf("cidr0 prefix “%s” with the wrong IP version", address)
+	}
+
+	prefix, err := addr.Prefix(c.Length)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+
+	if prefix.Addr() != addr {
+		return netip.Prefix{}, fmt.Errorf("cidr0 prefix “%s/%d” has host bits set", address, c.Length)
+	}
+
+	return prefix, nil
+}
+
+// NewCIDR0 converts a prefix into a CIDR0 item
+func NewCIDR0(prefix netip.Prefix) CIDR0 {
+	prefix = prefix.Masked()
+
+	c := CIDR0{Length: prefix.Bits()}
+	if prefix.Addr().Is4() {
+		c.V4Prefix = prefix.Addr().String()
+	} else {
+		c.V6Prefix = prefix.Addr().String()
+	}
+	return c
+}
+
+// CIDR0IPNetwork stores the cidr0 extension members of an IP network
+type CIDR0IPNetwork struct {
+	CIDRs []CIDR0 `json:"cidr0_cidrs,omitempty"`
+}
+
+func init() {
+	RegisterExtension(Extension{
+		Identifier: CIDR0Identifier,
+		Prefix:     CIDR0Prefix,
+		Members: map[string]func() any{
+			ObjectClassIPNetwork: func() any { return &CIDR0IPNetwork{} },
+		},
+	})
+}
+
+// CIDR0 returns the prefixes listed in the cidr0 extension members. If the
+// IP network doesn't have the extension members nil is returned
+func (i *IPNetwork) CIDR0() ([]netip.Prefix, error) {
+	extension, err := i.Extension(CIDR0Prefix)
+	if err != nil || extension == nil {
+		return nil, err
+	}
+
+	var prefixes []netip.Prefix
+	for _, item := range extension.(*CIDR0IPNetwork).CIDRs {
+		prefix, err := item.Prefix()
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix)
+	}
+
+	return prefixes, nil
+}
+
+// SetCIDR0 stores the prefixes in the cidr0 extension members. Use the
+// Prefixes method to compute them from the IP network range
+func (i *IPNetwork) SetCIDR0(prefixes []netip.Prefix) error {
+	var extension CIDR0IPNetwork
+	for _, prefix := range prefixes {
+		extension.CIDRs = append(extension.CIDRs, NewCIDR0(prefix))
+	}
+
+	return i.SetExtension(CIDR0Prefix, &extension)
+}
+
+// ValidateCIDR0 checks if the cidr0 prefixes cover exactly the range from
+// StartAddress to EndAddress, without gaps or overlaps. An IP network without
+// the cidr0 extension members is considered valid
+func (i *IPNetwork) ValidateCIDR0() error {
+	prefixes, err := i.CIDR0()
+	if err != nil || prefixes == nil {
+		return err
+	}
+
+	start, end, err := parseRange(i.StartAddress, i.EndAddress)
+	if err != nil {
+		return err
+	}
+
+	slices.SortFunc(prefixes, func(a, b netip.Prefix) int {
+		return a.Addr().Compare(b.Addr())
+	})
+
+	next := start
+	for _, prefix := range prefixes {
+		if prefix.Addr().BitLen() != start.BitLen() {
+			return fmt.Errorf("cidr0 prefix %s doesn't match the IP version of the range %s - %s",
+				prefix, i.StartAddress, i.EndAddress)
+		}
+
+		if !next.IsValid() || prefix.Addr() != next {
+			return fmt.Errorf("cidr0 prefixes don't cover the range %s - %s: unexpected prefix %s",
+				i.StartAddress, i.EndAddress, prefix)
+		}
+
+		last := LastAddr(prefix)
+		if last.Compare(end) > 0 {
+			return fmt.Errorf("cidr0 prefix %s exceeds the range %s - %s",
+				prefix, i.StartAddress, i.EndAddress)
+		}
+		next = last.Next()
+	}
+
+	if last := LastAddr(prefixes[len(prefixes)-1]); last != end {
+		return fmt.Errorf("cidr0 prefixes don't cover the range %s - %s: missing addresses after %s",
+			i.StartAddress, i.EndAddress, last)
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+func TestIPNetworkCIDR0(t *testing.T) {
+	data := []struct {
+		description           string
+		data                  string
+		expected              []netip.Prefix
+		expectedError         error
+		expectedValidateError error
+	}{
+		{
+			description: "it should decode the IPv4 prefixes",
+			data:        `{"objectClassName":"ip network","startAddress":"192.0.2.0","endAddress":"192.0.3.127","ipVersion":"v4","cidr0_cidrs":[{"v4prefix":"192.0.2.0","length":24},{"v4prefix":"192.0.3.0","length":25}]}`,
+			expected: []netip.Prefix{
+				netip.MustParsePrefix("192.0.2.0/24"),
+				netip.MustParsePrefix("192.0.3.0/25"),
+			},
+		},
+		{
+			description: "it should decode the IPv6 prefixes",
+			data:        `{"objectClassName":"ip network","startAddress":"2001:db8::","endAddress":"2001:db8:ffff:ffff:ffff:ffff:ffff:ffff","ipVersion":"v6","cidr0_cidrs":[{"v6prefix":"2001:db8::","length":32}]}`,
+			expected: []netip.Prefix{
+				netip.MustParsePrefix("2001:db8::/32"),
+			},
+		},
+		{
+			description: "it should accept an IP network without cidr0 members",
+			data:        `{"objectClassName":"ip network","startAddress":"192.0.2.0","endAddress":"192.0.2.255","ipVersion":"v4"}`,
+		},
+		{
+			description:           "it should detect a gap in the prefixes",
+			data:                  `{"objectClassName":"ip network","startAddress":"192.0.2.0","endAddress":"192.0.3.255","ipVersion":"v4","cidr0_cidrs":[{"v4prefix":"192.0.3.0","length":24}]}`,
+			expected:              []netip.Prefix{netip.MustParsePrefix("192.0.3.0/24")},
+			expectedValidateError: fmt.Errorf("cidr0 prefixes don't cover the range 192.0.2.0 - 192.0.3.255: unexpected prefix 192.0.3.0/24"),
+		},
+		{
+			description:           "it should detect missing addresses at the end",
+			data:                  `{"objectClassName":"ip network","startAddress":"192.0.2.0","endAddress":"192.0.3.255","ipVersion":"v4","cidr0_cidrs":[{"v4prefix":"192.0.2.0","length":24}]}`,
+			expected:              []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24")},
+			expectedValidateError: fmt.Errorf("cidr0 prefixes don't cover the range 192.0.2.0 - 192.0.3.255: missing addresses after 192.0.2.255"),
+		},
+		{
+			description:           "it should detect a prefix larger than the range",
+			data:                  `{"objectClassName":"ip network","startAddress":"192.0.2.0","endAddress":"192.0.2.127","ipVersion":"v4","cidr0_cidrs":[{"v4prefix":"192.0.2.0","length":24}]}`,
+			expected:              []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24")},
+			expectedValidateError: fmt.Errorf("cidr0 prefix 192.0.2.0/24 exceeds the range 192.0.2.0 - 192.0.2.127"),
+		},
+		{
+			description:           "it should detect overlapping prefixes",
+			data:                  `{"objectClassName":"ip network","startAddress":"192.0.2.0","endAddress":"192.0.2.255","ipVersion":"v4","cidr0_cidrs":[{"v4prefix":"192.0.2.0","length":24},{"v4prefix":"192.0.2.0","length":25}]}`,
+			expected:              []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24"), netip.MustParsePrefix("192.0.2.0/25")},
+			expectedValidateError: fmt.Errorf("cidr0 prefixes don't cover the range 192.0.2.0 - 192.0.2.255: unexpected prefix 192.0.2.0/25"),
+		},
+		{
+			description:           "it should detect a prefix from other IP version",
+			data:                  `{"objectClassName":"ip network","startAddress":"192.0.2.0","endAddress":"192.0.2.255","ipVersion":"v4","cidr0_cidrs":[{"v6prefix":"2001:db8::","length":32}]}`,
+			expected:              []netip.Prefix{netip.MustParsePrefix("2001:db8::/32")},
+			expectedValidateError: fmt.Errorf("cidr0 prefix 2001:db8::/32 doesn't match the IP version of the range 192.0.2.0 - 192.0.2.255"),
+		},
+		{
+			description:   "it should fail for an item without prefix",
+			data:          `{"objectClassName":"ip network","cidr0_cidrs":[{"length":24}]}`,
+			expectedError: fmt.Errorf("cidr0 item without prefix"),
+		},
+		{
+			description:   "it should fail for an item with both prefixes",
+			data:          `{"objectClassName":"ip network","cidr0_cidrs":[{"v4prefix":"192.0.2.0","v6prefix":"2001:db8::","length":24}]}`,
+			expectedError: fmt.Errorf("cidr0 item with both v4prefix and v6prefix"),
+		},
+		{
+			description:   "it should fail for a prefix with the wrong IP version",
+			data:          `{"objectClassName":"ip network","cidr0_cidrs":[{"v4prefix":"2001:db8::","length":32}]}`,
+			expectedError: fmt.Errorf("cidr0 prefix “2001:db8::” with the wrong IP version"),
+		},
+		{
+			description:   "it should fail for a prefix with host bits",
+			data:          `{"objectClassName":"ip network","cidr0_cidrs":[{"v4prefix":"192.0.2.1","length":24}]}`,
+			expectedError: fmt.Errorf("cidr0 prefix “192.0.2.1/24” has host bits set"),
+		},
+		{
+			description:   "it should fail for an invalid length",
+			data:          `{"objectClassName":"ip network","cidr0_cidrs":[{"v4prefix":"192.0.2.0","length":33}]}`,
+			expectedError: fmt.Errorf("prefix length 33 too large for IPv4"),
+		},
+	}
+
+	for i, item := range data {
+		var ipNetwork IPNetwork
+		if err := json.Unmarshal([]byte(item.data), &ipNetwork); err != nil {
+			t.Fatalf("[%d] %s: unexpected error “%s”", i, item.description, err)
+		}
+
+		prefixes, err := ipNetwork.CIDR0()
+
+		if item.expectedError != nil {
+			if fmt.Sprintf("%v", item.expectedError) != fmt.Sprintf("%v", err) {
+				t.Errorf("[%d] %s: expected error “%s”, got “%s”", i, item.description, item.expectedError, err)
+			}
+			continue
+
+		} else if err != nil {
+			t.Errorf("[%d] %s: unexpected error “%s”", i, item.description, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(item.expected, prefixes) {
+			t.Errorf("[%d] %s: unexpected prefixes. Expected “%v” and got “%v”", i, item.description, item.expected, prefixes)
+		}
+
+		err = ipNetwork.ValidateCIDR0()
+		if fmt.Sprintf("%v", item.expectedValidateError) != fmt.Sprintf("%v", err) {
+			t.Errorf("[%d] %s: expected validation error “%v”, got “%v”", i, item.description, item.expectedValidateError, err)
+		}
+	}
+}
+
+func TestIPNetworkSetCIDR0(t *testing.T) {
+	entity := Entity{
+		ObjectClassName: "entity",
+		Handle:          "EXAMPLE",
+		Networks: []IPNetwork{
+			{
+				ObjectClassName: "ip network",
+				StartAddress:    "192.0.2.0",
+				EndAddress:      "192.0.3.127",
+				IPVersion:       "v4",
+			},
+		},
+	}
+
+	prefixes, err := entity.Networks[0].Prefixes()
+	if err != nil {
+		t.Fatalf("Unexpected error “%s”", err)
+	}
+
+	if err := entity.Networks[0].SetCIDR0(prefixes); err != nil {
+		t.Fatalf("Unexpected error “%s”", err)
+	}
+
+	if err := entity.Networks[0].ValidateCIDR0(); err != nil {
+		t.Errorf("Unexpected validation error “%s”", err)
+	}
+
+	output, err := json.Marshal(entity)
+	if err != nil {
+		t.Fatalf("Unexpected error “%s”", err)
+	}
+
+	expected := `{"objectClassName":"entity","handle":"EXAMPLE","networks":[{"objectClassName":"ip network","handle":"","startAddress":"192.0.2.0","endAddress":"192.0.3.127","ipVersion":"v4","type":"","country":"","status":null,"links":null,"events":null,"entities":null,"cidr0_cidrs":[{"v4prefix":"192.0.2.0","length":24},{"v4prefix":"192.0.3.0","length":25}]}]}`
+	if string(output) != expected {
+		t.Errorf("Unexpected JSON. Expected “%s” and got “%s”", expected, string(output))
+	}
+
+	var decoded Entity
+	if err := json.Unmarshal(output, &decoded); err != nil {
+		t.Fatalf("Unexpected error “%s”", err)
+	}
+
+	decodedPrefixes, err := decoded.Networks[0].CIDR0()
+	if err != nil {
+		t.Fatalf("Unexpected error “%s”", err)
+	}
+
+	if !reflect.DeepEqual(prefixes, decodedPrefixes) {
+		t.Errorf("Unexpected prefixes. Expected “%v” and got “%v”", prefixes, decodedPrefixes)
+	}
+}
@@ -1,6 +1,6 @@
 package protocol
 
-// Conformance describes the RDAP conformance as it is in RFC 7483, section
+// Conformance describes the RDAP conformance as it is in RFC 9083, section
 // 4.1. The conformance is usually inserted in all responses to identify the
 // extensions that the response includes
 type Conformance struct {
@@ -18,3 +18,9 @@ type ConformanceSetter interface {
 func (l *Conformance) SetConformance(levels []string) {
 	l.Levels = levels
 }
+
+// GetConformance returns the conformance levels of the response object. It is
+// part of the Object interface
+func (l Conformance) GetConformance() []string {
+	return l.Levels
+}
@@ -0,0 +1,156 @@
+package protocol
+
+import "time"
+
+// List of possible verdicts of a delegation report
+const (
+	// DelegationVerdictOK all checked nameservers are well configured
+	DelegationVerdictOK DelegationVerdict = "ok"
+
+	// DelegationVerdictDegraded at least one nameserver is well configured,
+	// but others have problems
+	DelegationVerdictDegraded DelegationVerdict = "degraded"
+
+	// DelegationVerdictBroken none of the checked nameservers is well
+	// configured
+	DelegationVerdictBroken DelegationVerdict = "broken"
+
+	// DelegationVerdictUnchecked there's no delegation check event with a
+	// result in the nameservers
+	DelegationVerdictUnchecked DelegationVerdict = "unchecked"
+)
+
+// DelegationVerdict stores the overall result of the domain delegation checks
+type DelegationVerdict string
+
+// nsStatusExplanations describes the problems found by the NIC.br delegation
+// checks
+var nsStatusExplanations = map[Status]string{
+	StatusNSTimeout:           "the nameserver didn't answer the DNS query",
+	StatusNSNoAA:              "the nameserver doesn't have authority for the domain",
+	StatusNSUDN:               "the nameserver answered that the domain doesn't exist",
+	StatusNSUH:                "the nameserver name couldn't be resolved",
+	StatusNSFail:              "the nameserver answered with an internal server error",
+	StatusNSQueryRefused:      "the nameserver refused to answer the DNS query",
+	StatusNSConnectionRefused: "the connection to the nameserver was refused, probably by a firewall",
+	StatusNSError:             "a generic error occurred while checking the nameserver",
+	StatusNSCNAME:             "a CNAME record was found in the zone apex (RFC 2181, section 10.1)",
+	StatusNSSOAVersion:        "the nameservers answered with different SOA serial numbers",
+	StatusNone:                "a DNS error occurred while checking the nameserver",
+}
+
+// DelegationProblem is a status of the last delegation check that indicates a
+// misconfiguration
+type DelegationProblem struct {
+	Status      Status
+	Explanation string
+}
+
+// NameserverHealth stores the result of the delegation checks of a nameserver
+type NameserverHealth struct {
+	// LDHName identifies the nameserver
+	LDHName string
+
+	// Checked is true when the latest delegation check event of the
+	// nameserver has a result. Without status the result is unknown
+	Checked bool
+
+	// LastCheck is the date of the latest delegation check, even when its
+	// result is unknown
+	LastCheck time.Time
+
+	// LastCorrect is the date of the last time that the nameserver was well
+	// configured. It is zero when there's no record of a correct check
+	LastCorrect time.Time
+
+	// Status are the values of the latest delegation check
+	Status []Status
+
+	// Problems are the status of the latest delegation check that indicate a
+	// misconfiguration
+	Problems []DelegationProblem
+}
+
+// OK returns true when the nameserver was checked and no problem was found
+func (n NameserverHealth) OK() bool {
+	return n.Checked && len(n.Problems) == 0
+}
+
+// DelegationReport summarizes the NIC.br delegation check events of the
+// domain nameservers
+type DelegationReport struct {
+	Nameservers []NameserverHealth
+	Verdict     DelegationVerdict
+}
+
+// NewDelegationReport builds the delegation report from the "delegation
+// check" and "last correct delegation check" events of the domain nameservers
+func NewDelegationReport(domain *Domain) DelegationReport {
+	report := DelegationReport{
+		Verdict: DelegationVerdictUnchecked,
+	}
+
+	var ok, problems int
+	for i := range domain.Nameservers {
+		health := newNameserverHealth(&domain.Nameservers[i])
+		report.Nameservers = append(report.Nameservers, health)
+
+		if health.OK() {
+			ok++
+		} else if health.Checked {
+			problems++
+		}
+	}
+
+	switch {
+	case ok > 0 && problems > 0:
+		report.Verdict = DelegationVerdictDegraded
+	case ok > 0:
+		report.Verdict = DelegationVerdictOK
+	case problems > 0:
+		report.Verdict = DelegationVerdictBroken
+	}
+
+	return report
+}
+
+func newNameserverHealth(nameserver *Nameserver) NameserverHealth {
+	health := NameserverHealth{
+		LDHName: nameserver.LDHName,
+	}
+
+	if event, ok := lastEvent(nameserver.Events, EventLastCorrectDelegationCheck); ok {
+		health.LastCorrect = event.Date.Time
+	}
+
+	check, ok := lastEvent(nameserver.Events, EventDelegationCheck)
+	if !ok {
+		return health
+	}
+
+	health.LastCheck = check.Date.Time
+	if len(check.Status) == 0 {
+		return health
+	}
+
+	health.Checked = true
+	health.Status = check.Status
+
+	for _, status := range check.Status {
+		if status == StatusNSAA {
+			continue
+		}
+
+		explanation, ok := nsStatusExplanations[status]
+		if !ok {
+			explanation = "unknown delegation check result"
+		}
+
+		health.Problems = append(health.Problems, DelegationProblem{
+			Status:      status,
+			Explanation: explanation,
+		})
+	}
+
+	return health
+}
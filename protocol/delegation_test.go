@@ -0,0 +1,156 @@
+package protocol
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewDelegationReport(t *testing.T) {
+	day := func(d int) EventDate {
+		return Date(2024, time.May, d, 12, 0, 0, 0, time.UTC)
+	}
+
+	healthy := Nameserver{
+		LDHName: "a.dns.br",
+		Events: []Event{
+			{Action: EventDelegationCheck, Date: day(9), Status: []Status{StatusNSTimeout}},
+			{Action: EventDelegationCheck, Date: day(10), Status: []Status{StatusNSAA}},
+			{Action: EventLastCorrectDelegationCheck, Date: day(10)},
+		},
+	}
+
+	broken := Nameserver{
+		LDHName: "b.dns.br",
+		Events: []Event{
+			{Action: EventDelegationCheck, Date: day(10), Status: []Status{StatusNSNoAA, StatusNSSOAVersion}},
+			{Action: EventLastCorrectDelegationCheck, Date: day(1)},
+		},
+	}
+
+	unknown := Nameserver{
+		LDHName: "c.dns.br",
+		Events: []Event{
+			{Action: EventDelegationCheck, Date: day(10), Status: []Status{"ns something new"}},
+		},
+	}
+
+	unchecked := Nameserver{
+		LDHName: "d.dns.br",
+	}
+
+	withoutStatus := Nameserver{
+		LDHName: "e.dns.br",
+		Events: []Event{
+			{Action: EventDelegationCheck, Date: day(10)},
+		},
+	}
+
+	healthyReport := NameserverHealth{
+		LDHName:     "a.dns.br",
+		Checked:     true,
+		LastCheck:   day(10).Time,
+		LastCorrect: day(10).Time,
+		Status:      []Status{StatusNSAA},
+	}
+
+	brokenReport := NameserverHealth{
+		LDHName:     "b.dns.br",
+		Checked:     true,
+		LastCheck:   day(10).Time,
+		LastCorrect: day(1).Time,
+		Status:      []Status{StatusNSNoAA, StatusNSSOAVersion},
+		Problems: []DelegationProblem{
+			{
+				Status:      StatusNSNoAA,
+				Explanation: "the nameserver doesn't have authority for the domain",
+			},
+			{
+				Status:      StatusNSSOAVersion,
+				Explanation: "the nameservers answered with different SOA serial numbers",
+			},
+		},
+	}
+
+	data := []struct {
+		description string
+		domain      Domain
+		expected    DelegationReport
+	}{
+		{
+			description: "it should report a healthy delegation",
+			domain: Domain{
+				Nameservers: []Nameserver{healthy, unchecked},
+			},
+			expected: DelegationReport{
+				Nameservers: []NameserverHealth{
+					healthyReport,
+					{LDHName: "d.dns.br"},
+				},
+				Verdict: DelegationVerdictOK,
+			},
+		},
+		{
+			description: "it should report a degraded delegation",
+			domain: Domain{
+				Nameservers: []Nameserver{healthy, broken},
+			},
+			expected: DelegationReport{
+				Nameservers: []NameserverHealth{healthyReport, brokenReport},
+				Verdict:     DelegationVerdictDegraded,
+			},
+		},
+		{
+			description: "it should report a broken delegation",
+			domain: Domain{
+				Nameservers: []Nameserver{broken, unknown},
+			},
+			expected: DelegationReport{
+				Nameservers: []NameserverHealth{
+					brokenReport,
+					{
+						LDHName:   "c.dns.br",
+						Checked:   true,
+						LastCheck: day(10).Time,
+						Status:    []Status{"ns something new"},
+						Problems: []DelegationProblem{
+							{
+								Status:      "ns something new",
+								Explanation: "unknown delegation check result",
+							},
+						},
+					},
+				},
+				Verdict: DelegationVerdictBroken,
+			},
+		},
+		{
+			description: "it should not consider healthy a check without status",
+			domain: Domain{
+				Nameservers: []Nameserver{withoutStatus},
+			},
+			expected: DelegationReport{
+				Nameservers: []NameserverHealth{
+					{
+						LDHName:   "e.dns.br",
+						LastCheck: day(10).Time,
+					},
+				},
+				Verdict: DelegationVerdictUnchecked,
+			},
+		},
+		{
+			description: "it should report an unchecked delegation",
+			domain:      Domain{},
+			expected: DelegationReport{
+				Verdict: DelegationVerdictUnchecked,
+			},
+		},
+	}
+
+	for i, item := range data {
+		if report := NewDelegationReport(&item.domain); !reflect.DeepEqual(item.expected, report) {
+			t.Errorf("[%d] %s: unexpected report. Expected “%#v” and got “%#v”", i, item.description, item.expected, report)
+		}
+	}
+}
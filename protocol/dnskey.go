@@ -0,0 +1,234 @@
+package protocol
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+)
+
+// DNSKEY stores the data of a DNSKEY resource record as described in RFC
+// 4034, section 2
+type DNSKEY struct {
+	Flags     uint16
+	Protocol  uint8
+	Algorithm DNSSECAlgorithm
+
+	// PublicKey is the public key encoded in base64, like in the presentation
+	// format
+	PublicKey string
+}
+
+// ParseDNSKEY parses the DNSKEY in presentation format. It accepts the whole
+// resource record ("example.br. 3600 IN DNSKEY 257 3 13 ...") or only the
+// RDATA ("257 3 13 ..."). The owner name, TTL and class are ignored, and the
+// public key can be split in many fields or enclosed in parentheses
+func ParseDNSKEY(record string) (DNSKEY, error) {
+	record = strings.NewReplacer("(", " ", ")", " ").Replace(record)
+	fields := strings.Fields(record)
+
+	for i, field := range fields {
+		if strings.EqualFold(field, "DNSKEY") {
+			fields = fields[i+1:]
+			break
+		}
+	}
+
+	if len(fields) < 4 {
+		return DNSKEY{}, fmt.Errorf("invalid DNSKEY “%s”", record)
+	}
+
+	flags, err := strconv.ParseUint(fields[0], 10, 16)
+	if err != nil {
+		return DNSKEY{}, fmt.Errorf("invalid DNSKEY flags “%s”", fields[0])
+	}
+
+	protocol, err := strconv.ParseUint(fields[1], 10, 8)
+	if err != nil {
+		return DNSKEY{}, fmt.Errorf("invalid DNSKEY protocol “%s”", fields[1])
+	}
+
+	algorithm, err := strconv.ParseUint(fields[2], 10, 8)
+	if err != nil {
+		return DNSKEY{}, fmt.Errorf("invalid DNSKEY algorithm “%s”", fields[2])
+	}
+
+	key := DNSKEY{
+		Flags:     uint16(flags),
+		Protocol:  uint8(protocol),
+		Algorithm: DNSSECAlgorithm(algorithm),
+		PublicKey: strings.Join(fields[3:], ""),
+	}
+
+	if _, err := key.rdata(); err != nil {
+		return DNSKEY{}, err
+	}
+
+	return key, nil
+}
+
+// KeyTag calculates the key tag of the DNSKEY as described in RFC 4034,
+// appendix B. If the public key isn't valid base64 it returns 0
+func (k DNSKEY) KeyTag() int {
+	rdata, err := k.rdata()
+	if err != nil {
+		return 0
+	}
+
+	if k.Algorithm == AlgorithmRSAMD5 {
+		// the key tag is the most significant 16 bits of the least
+		// significant 24 bits of the public key modulus
+		if len(rdata) < 3 {
+			return 0
+		}
+		return int(binary.BigEndian.Uint16(rdata[len(rdata)-3:]))
+	}
+
+	var ac uint32
+	for i, b := range rdata {
+		if i&1 == 1 {
+			ac += uint32(b)
+		} else {
+			ac += uint32(b) << 8
+		}
+	}
+	ac += ac >> 16 & 0xffff
+
+	return int(ac & 0xffff)
+}
+
+// Digest calculates the DS digest of the DNSKEY for the owner name, as
+// described in RFC 4034, section 5.1.4. The digest is returned in upper case
+// hexadecimal, like in the presentation format
+func (k DNSKEY) Digest(owner string, digestType DigestType) (string, error) {
+	var h hash.Hash
+	switch digestType {
+	case DigestTypeSHA1:
+		h = sha1.New()
+	case DigestTypeSHA256:
+		h = sha256.New()
+	case DigestTypeSHA384:
+		h = sha512.New384()
+	default:
+		return "", fmt.Errorf("unsupported digest type “%s”", digestType)
+	}
+
+	name, err := canonicalName(owner)
+	if err != nil {
+		return "", err
+	}
+
+	rdata, err := k.rdata()
+	if err != nil {
+		return "", err
+	}
+
+	h.Write(name)
+	h.Write(rdata)
+	return strings.ToUpper(hex.EncodeToString(h.Sum(nil))), nil
+}
+
+// DS builds the DS record that points to the DNSKEY
+func (k DNSKEY) DS(owner string, digestType DigestType) (DS, error) {
+	digest, err := k.Digest(owner, digestType)
+	if err != nil {
+		return DS{}, err
+	}
+
+	return DS{
+		KeyTag:     k.KeyTag(),
+		Algorithm:  int(k.Algorithm),
+		Digest:     digest,
+		DigestType: int(digestType),
+	}, nil
+}
+
+// rdata returns the DNSKEY RDATA in wire format
+func (k DNSKEY) rdata() ([]byte, error) {
+	publicKey, err := base64.StdEncoding.DecodeString(k.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DNSKEY public key: %w", err)
+	}
+
+	rdata := make([]byte, 4, 4+len(publicKey))
+	binary.BigEndian.PutUint16(rdata, k.Flags)
+	rdata[2] = k.Protocol
+	rdata[3] = uint8(k.Algorithm)
+	return append(rdata, publicKey...), nil
+}
+
+// canonicalName converts the domain name to the canonical wire format (RFC
+// 4034, section 6.2)
+func canonicalName(name string) ([]byte, error) {
+	name = strings.TrimSuffix(strings.ToLower(name), ".")
+
+	var wire []byte
+	if name != "" {
+		for _, label := range strings.Split(name, ".") {
+			if len(label) == 0 || len(label) > 63 {
+				return nil, fmt.Errorf("invalid label “%s” in domain name", label)
+			}
+			wire = append(wire, byte(len(label)))
+			wire = append(wire, label...)
+		}
+	}
+
+	if wire = append(wire, 0); len(wire) > 255 {
+		return nil, fmt.Errorf("domain name “%s” is too long", name)
+	}
+
+	return wire, nil
+}
+
+// fqdn adds the final dot to the domain name
+func fqdn(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
+
+// PresentationFormat returns the DS record in the zone file format (RFC 4034,
+// section 5.3) for the owner name, like "example.br. IN DS 12345 13 2 ABCD"
+func (d DS) PresentationFormat(owner string) string {
+	return fmt.Sprintf("%s IN DS %d %d %d %s", fqdn(owner), d.KeyTag, d.Algorithm, d.DigestType,
+		strings.ToUpper(strings.Join(strings.Fields(d.Digest), "")))
+}
+
+// Matches checks if the DS record points to the DNSKEY of the owner name,
+// comparing the key tag, the algorithm and the digest
+func (d DS) Matches(owner string, key DNSKEY) (bool, error) {
+	return matchDS(owner, d.KeyTag, d.Algorithm, d.DigestType, d.Digest, key)
+}
+
+// PresentationFormat returns the DS record in the zone file format (RFC 4034,
+// section 5.3) using the reverse zone as owner name
+func (r ReverseDS) PresentationFormat() string {
+	ds := DS{KeyTag: r.KeyTag, Algorithm: r.Algorithm, Digest: r.Digest, DigestType: r.DigestType}
+	return ds.PresentationFormat(r.Zone)
+}
+
+// Matches checks if the DS record points to the DNSKEY of the reverse zone,
+// comparing the key tag, the algorithm and the digest
+func (r ReverseDS) Matches(key DNSKEY) (bool, error) {
+	return matchDS(r.Zone, r.KeyTag, r.Algorithm, r.DigestType, r.Digest, key)
+}
+
+func matchDS(owner string, keyTag, algorithm, digestType int, digest string, key DNSKEY) (bool, error) {
+	if keyTag != key.KeyTag() || algorithm != int(key.Algorithm) {
+		return false, nil
+	}
+
+	keyDigest, err := key.Digest(owner, DigestType(digestType))
+	if err != nil {
+		return false, err
+	}
+
+	return strings.EqualFold(strings.Join(strings.Fields(digest), ""), keyDigest), nil
+}
@@ -0,0 +1,206 @@
+package protocol
+
+import (
+	"fmt"
+	"testing"
+)
+
+// dskey.example.com DNSKEY from RFC 4034, section 5.4
+const rfc4034DNSKEY = `dskey.example.com. 86400 IN DNSKEY 256 3 5 ( AQOeiiR0GOMYkDshWoSKz9Xz
+                                          fwJr1AYtsmx3TGkJaNXVbfi/
+                                          2pHm822aJ5iI9BMzNXxeYCmZ
+                                          DRD99WYwYqUSdjMmmAphXdvx
+                                          egXd/M5+X7OrzKBaMbCVdFLU
+                                          Uh6DhweJBjEVv5f2wwjM9Xzc
+                                          nOf+EPbtG9DMBmADjFDc2w/r
+                                          ljwvFw==
+                                          ) ;  key id = 60485`
+
+func TestParseDNSKEY(t *testing.T) {
+	data := []struct {
+		description   string
+		record        string
+		expectedTag   int
+		expectedError error
+	}{
+		{
+			description: "it should parse the whole resource record",
+			record:      rfc4034DNSKEY[:len(rfc4034DNSKEY)-len(";  key id = 60485")],
+			expectedTag: 60485,
+		},
+		{
+			description: "it should parse only the RDATA",
+			record:      "257 3 13 mdsswUyr3DPW132mOi8V9xESWE8jTo0dxCjjnopKl+GqJxpVXckHAeF+KkxLbxILfDLUT0rAK9iUzy1L53eKGQ==",
+			expectedTag: 2371,
+		},
+		{
+			description:   "it should fail for missing fields",
+			record:        "example.br. IN DNSKEY 257 3 13",
+			expectedError: fmt.Errorf("invalid DNSKEY “example.br. IN DNSKEY 257 3 13”"),
+		},
+		{
+			description:   "it should fail for invalid flags",
+			record:        "X 3 13 AAAA",
+			expectedError: fmt.Errorf("invalid DNSKEY flags “X”"),
+		},
+		{
+			description:   "it should fail for an invalid protocol",
+			record:        "257 256 13 AAAA",
+			expectedError: fmt.Errorf("invalid DNSKEY protocol “256”"),
+		},
+		{
+			description:   "it should fail for an invalid algorithm",
+			record:        "257 3 -1 AAAA",
+			expectedError: fmt.Errorf("invalid DNSKEY algorithm “-1”"),
+		},
+		{
+			description:   "it should fail for an invalid public key",
+			record:        "257 3 13 AA!A",
+			expectedError: fmt.Errorf("invalid DNSKEY public key: illegal base64 data at input byte 2"),
+		},
+	}
+
+	for i, item := range data {
+		key, err := ParseDNSKEY(item.record)
+
+		if item.expectedError != nil {
+			if fmt.Sprintf("%v", item.expectedError) != fmt.Sprintf("%v", err) {
+				t.Errorf("[%d] %s: expected error “%v”, got “%v”", i, item.description, item.expectedError, err)
+			}
+
+		} else if err != nil {
+			t.Errorf("[%d] %s: unexpected error “%s”", i, item.description, err)
+
+		} else if tag := key.KeyTag(); tag != item.expectedTag {
+			t.Errorf("[%d] %s: expected key tag “%d” and got “%d”", i, item.description, item.expectedTag, tag)
+		}
+	}
+}
+
+func TestDSMatches(t *testing.T) {
+	key, err := ParseDNSKEY(rfc4034DNSKEY[:len(rfc4034DNSKEY)-len(";  key id = 60485")])
+	if err != nil {
+		t.Fatalf("Unexpected error “%s”", err)
+	}
+
+	data := []struct {
+		description   string
+		owner         string
+		ds            DS
+		expected      bool
+		expectedError error
+	}{
+		{
+			description: "it should match the SHA-1 digest from RFC 4034, section 5.4",
+			owner:       "dskey.example.com.",
+			ds: DS{
+				KeyTag:     60485,
+				Algorithm:  5,
+				DigestType: 1,
+				Digest:     "2BB183AF5F22588179A53B0A98631FAD1A292118",
+			},
+			expected: true,
+		},
+		{
+			description: "it should match the SHA-256 digest from RFC 4509, section 2.3",
+			owner:       "DSKEY.example.com",
+			ds: DS{
+				KeyTag:     60485,
+				Algorithm:  5,
+				DigestType: 2,
+				Digest:     "d4b7d520e7bb5f0f67674a0cceb1e3e0 614b93c4f9e99b8383f6a1e4469da50a",
+			},
+			expected: true,
+		},
+		{
+			description: "it should not match other owner name",
+			owner:       "example.com",
+			ds: DS{
+				KeyTag:     60485,
+				Algorithm:  5,
+				DigestType: 1,
+				Digest:     "2BB183AF5F22588179A53B0A98631FAD1A292118",
+			},
+		},
+		{
+			description: "it should not match other key tag",
+			owner:       "dskey.example.com",
+			ds: DS{
+				KeyTag:     60486,
+				Algorithm:  5,
+				DigestType: 1,
+				Digest:     "2BB183AF5F22588179A53B0A98631FAD1A292118",
+			},
+		},
+		{
+			description: "it should fail for an unsupported digest type",
+			owner:       "dskey.example.com",
+			ds: DS{
+				KeyTag:     60485,
+				Algorithm:  5,
+				DigestType: 3,
+			},
+			expectedError: fmt.Errorf("unsupported digest type “GOST R 34.11-94”"),
+		},
+		{
+			description: "it should fail for an invalid owner name",
+			owner:       "dskey..example.com",
+			ds: DS{
+				KeyTag:     60485,
+				Algorithm:  5,
+				DigestType: 2,
+			},
+			expectedError: fmt.Errorf("invalid label “” in domain name"),
+		},
+	}
+
+	for i, item := range data {
+		matches, err := item.ds.Matches(item.owner, key)
+
+		if item.expectedError != nil {
+			if fmt.Sprintf("%v", item.expectedError) != fmt.Sprintf("%v", err) {
+				t.Errorf("[%d] %s: expected error “%v”, got “%v”", i, item.description, item.expectedError, err)
+			}
+
+		} else if err != nil {
+			t.Errorf("[%d] %s: unexpected error “%s”", i, item.description, err)
+
+		} else if matches != item.expected {
+			t.Errorf("[%d] %s: expected “%t” and got “%t”", i, item.description, item.expected, matches)
+		}
+	}
+}
+
+func TestDNSKEYDS(t *testing.T) {
+	key, err := ParseDNSKEY(rfc4034DNSKEY[:len(rfc4034DNSKEY)-len(";  key id = 60485")])
+	if err != nil {
+		t.Fatalf("Unexpected error “%s”", err)
+	}
+
+	ds, err := key.DS("dskey.example.com", DigestTypeSHA256)
+	if err != nil {
+		t.Fatalf("Unexpected error “%s”", err)
+	}
+
+	expected := "dskey.example.com. IN DS 60485 5 2 D4B7D520E7BB5F0F67674A0CCEB1E3E0614B93C4F9E99B8383F6A1E4469DA50A"
+	if output := ds.PresentationFormat("dskey.example.com"); output != expected {
+		t.Errorf("Unexpected DS. Expected “%s” and got “%s”", expected, output)
+	}
+
+	reverseDS := ReverseDS{
+		Zone:       "0.160.200.in-addr.arpa.",
+		KeyTag:     ds.KeyTag,
+		Algorithm:  ds.Algorithm,
+		DigestType: ds.DigestType,
+		Digest:     "abcdef",
+	}
+
+	expected = "0.160.200.in-addr.arpa. IN DS 60485 5 2 ABCDEF"
+	if output := reverseDS.PresentationFormat(); output != expected {
+		t.Errorf("Unexpected reverse DS. Expected “%s” and got “%s”", expected, output)
+	}
+
+	if matches, err := reverseDS.Matches(key); err != nil || matches {
+		t.Errorf("Unexpected reverse DS match “%t” (%v)", matches, err)
+	}
+}
@@ -1,6 +1,8 @@
 package protocol
 
-// DS describes the dsData as it is in RFC 7483, section 5.3
+import "strconv"
+
+// DS describes the dsData as it is in RFC 9083, section 5.3
 type DS struct {
 	KeyTag     int     `json:"keyTag"`
 	Algorithm  int     `json:"algorithm"`
@@ -9,7 +11,7 @@ type DS struct {
 	Events     []Event `json:"events,omitempty"`
 }
 
-// SecureDNS describes the secureDNS as it is in RFC 7483, section 5.3
+// SecureDNS describes the secureDNS as it is in RFC 9083, section 5.3
 type SecureDNS struct {
 	// ZoneSigned does not make too much sense for us to use
 	// it, so we need to use a pointer to hide it with omitempty. Maybe the
@@ -17,5 +19,143 @@ type SecureDNS struct {
 	// without signing it, but its not clear in RFC 7483, section 5.3
 	ZoneSigned       *bool `json:"zoneSigned,omitempty"`
 	DelegationSigned bool  `json:"delegationSigned"`
-	DSData           []DS  `json:"dsData,omitempty"`
+
+	// MaxSigLife is the signature lifetime in seconds to be used when
+	// creating the RRSIG DS record in the parent zone
+	MaxSigLife int       `json:"maxSigLife,omitempty"`
+	DSData     []DS      `json:"dsData,omitempty"`
+	KeyData    []KeyData `json:"keyData,omitempty"`
+}
+
+// KeyData describes the keyData as it is in RFC 9083, section 5.3
+type KeyData struct {
+	Flags     int     `json:"flags"`
+	Protocol  int     `json:"protocol"`
+	PublicKey string  `json:"publicKey"`
+	Algorithm int     `json:"algorithm"`
+	Events    []Event `json:"events,omitempty"`
+	Links     []Link  `json:"links,omitempty"`
+}
+
+// DNSKEY converts the key data into a DNSKEY record
+func (k KeyData) DNSKEY() DNSKEY {
+	return DNSKEY{
+		Flags:     uint16(k.Flags),
+		Protocol:  uint8(k.Protocol),
+		Algorithm: DNSSECAlgorithm(k.Algorithm),
+		PublicKey: k.PublicKey,
+	}
+}
+
+// KeyTag calculates the key tag from the public key as described in RFC 4034,
+// appendix B, so it can be compared with DS.KeyTag. If the public key isn't
+// valid base64 it returns 0
+func (k KeyData) KeyTag() int {
+	return k.DNSKEY().KeyTag()
+}
+
+// List of DNSSEC algorithm numbers from the IANA registry "Domain Name System
+// Security (DNSSEC) Algorithm Numbers"
+const (
+	AlgorithmRSAMD5           DNSSECAlgorithm = 1
+	AlgorithmDH               DNSSECAlgorithm = 2
+	AlgorithmDSA              DNSSECAlgorithm = 3
+	AlgorithmRSASHA1          DNSSECAlgorithm = 5
+	AlgorithmDSANSEC3SHA1     DNSSECAlgorithm = 6
+	AlgorithmRSASHA1NSEC3SHA1 DNSSECAlgorithm = 7
+	AlgorithmRSASHA256        DNSSECAlgorithm = 8
+	AlgorithmRSASHA512        DNSSECAlgorithm = 10
+	AlgorithmECCGOST          DNSSECAlgorithm = 12
+	AlgorithmECDSAP256SHA256  DNSSECAlgorithm = 13
+	AlgorithmECDSAP384SHA384  DNSSECAlgorithm = 14
+	AlgorithmED25519          DNSSECAlgorithm = 15
+	AlgorithmED448            DNSSECAlgorithm = 16
+	AlgorithmSM2SM3           DNSSECAlgorithm = 17
+	AlgorithmECCGOST12        DNSSECAlgorithm = 23
+	AlgorithmIndirect         DNSSECAlgorithm = 252
+	AlgorithmPrivateDNS       DNSSECAlgorithm = 253
+	AlgorithmPrivateOID       DNSSECAlgorithm = 254
+)
+
+// DNSSECAlgorithm identifies the algorithm of a DNSKEY or DS record
+type DNSSECAlgorithm int
+
+var algorithmNames = map[DNSSECAlgorithm]string{
+	AlgorithmRSAMD5:           "RSAMD5",
+	AlgorithmDH:               "DH",
+	AlgorithmDSA:              "DSA",
+	AlgorithmRSASHA1:          "RSASHA1",
+	AlgorithmDSANSEC3SHA1:     "DSA-NSEC3-SHA1",
+	AlgorithmRSASHA1NSEC3SHA1: "RSASHA1-NSEC3-SHA1",
+	AlgorithmRSASHA256:        "RSASHA256",
+	AlgorithmRSASHA512:        "RSASHA512",
+	AlgorithmECCGOST:          "ECC-GOST",
+	AlgorithmECDSAP256SHA256:  "ECDSAP256SHA256",
+	AlgorithmECDSAP384SHA384:  "ECDSAP384SHA384",
+	AlgorithmED25519:          "ED25519",
+	AlgorithmED448:            "ED448",
+	AlgorithmSM2SM3:           "SM2SM3",
+	AlgorithmECCGOST12:        "ECC-GOST12",
+	AlgorithmIndirect:         "INDIRECT",
+	AlgorithmPrivateDNS:       "PRIVATEDNS",
+	AlgorithmPrivateOID:       "PRIVATEOID",
+}
+
+// String returns the mnemonic of the algorithm, or the number when the
+// algorithm is unassigned
+func (a DNSSECAlgorithm) String() string {
+	if name, ok := algorithmNames[a]; ok {
+		return name
+	}
+	return strconv.Itoa(int(a))
+}
+
+// Deprecated checks if the algorithm must not or should not be used for
+// DNSSEC signing, as recommended by RFC 8624, section 3.1
+func (a DNSSECAlgorithm) Deprecated() bool {
+	switch a {
+	case AlgorithmRSAMD5, AlgorithmDSA, AlgorithmRSASHA1, AlgorithmDSANSEC3SHA1,
+		AlgorithmRSASHA1NSEC3SHA1, AlgorithmECCGOST:
+		return true
+	}
+
+	return false
+}
+
+// List of DS digest types from the IANA registry "Delegation Signer (DS)
+// Resource Record (RR) Type Digest Algorithms"
+const (
+	DigestTypeSHA1     DigestType = 1
+	DigestTypeSHA256   DigestType = 2
+	DigestTypeGOST     DigestType = 3
+	DigestTypeSHA384   DigestType = 4
+	DigestTypeGOST2012 DigestType = 5
+	DigestTypeSM3      DigestType = 6
+)
+
+// DigestType identifies the algorithm used to build the digest of a DS record
+type DigestType int
+
+var digestTypeNames = map[DigestType]string{
+	DigestTypeSHA1:     "SHA-1",
+	DigestTypeSHA256:   "SHA-256",
+	DigestTypeGOST:     "GOST R 34.11-94",
+	DigestTypeSHA384:   "SHA-384",
+	DigestTypeGOST2012: "GOST R 34.11-2012",
+	DigestTypeSM3:      "SM3",
+}
+
+// String returns the name of the digest type, or the number when the digest
+// type is unassigned
+func (d DigestType) String() string {
+	if name, ok := digestTypeNames[d]; ok {
+		return name
+	}
+	return strconv.Itoa(int(d))
+}
+
+// Deprecated checks if the digest type must not be used to create DS
+// records, as recommended by RFC 8624, section 3.3
+func (d DigestType) Deprecated() bool {
+	return d == DigestTypeSHA1 || d == DigestTypeGOST
 }
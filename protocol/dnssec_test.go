@@ -0,0 +1,69 @@
+package protocol
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestSecureDNSKeyData(t *testing.T) {
+	input := `{"zoneSigned":true,"delegationSigned":true,"maxSigLife":604800,"keyData":[{"flags":257,"protocol":3,` +
+		`"publicKey":"mdsswUyr3DPW132mOi8V9xESWE8jTo0dxCjjnopKl+GqJxpVXckHAeF+KkxLbxILfDLUT0rAK9iUzy1L53eKGQ==","algorithm":13,` +
+		`"events":[{"eventAction":"last changed","eventDate":"2024-05-10T12:00:00Z"}]}]}`
+
+	var secureDNS SecureDNS
+	if err := json.Unmarshal([]byte(input), &secureDNS); err != nil {
+		t.Fatalf("Unexpected error “%s”", err)
+	}
+
+	if secureDNS.MaxSigLife != 604800 || len(secureDNS.KeyData) != 1 {
+		t.Fatalf("Unexpected secure DNS “%#v”", secureDNS)
+	}
+
+	keyData := secureDNS.KeyData[0]
+	if tag := keyData.KeyTag(); tag != 2371 {
+		t.Errorf("Unexpected key tag “%d”", tag)
+	}
+
+	expected := DNSKEY{
+		Flags:     257,
+		Protocol:  3,
+		Algorithm: AlgorithmECDSAP256SHA256,
+		PublicKey: keyData.PublicKey,
+	}
+
+	if key := keyData.DNSKEY(); !reflect.DeepEqual(expected, key) {
+		t.Errorf("Unexpected DNSKEY “%#v”", key)
+	}
+
+	output, err := json.Marshal(secureDNS)
+	if err != nil {
+		t.Fatalf("Unexpected error “%s”", err)
+	}
+
+	if string(output) != input {
+		t.Errorf("Unexpected JSON. Expected “%s” and got “%s”", input, string(output))
+	}
+
+	if tag := (KeyData{PublicKey: "!"}).KeyTag(); tag != 0 {
+		t.Errorf("Unexpected key tag “%d” for an invalid public key", tag)
+	}
+}
+
+func TestReverseDelegationSecureDNSKeyData(t *testing.T) {
+	input := `{"delegationSigned":true,"keyData":[{"flags":256,"protocol":3,"publicKey":"AQOeiiR0GOMYkDshWoSKz9XzfwJr1AYtsmx3TGkJaNXVbfi/2pHm822aJ5iI9BMzNXxeYCmZDRD99WYwYqUSdjMmmAphXdvxegXd/M5+X7OrzKBaMbCVdFLUUh6DhweJBjEVv5f2wwjM9XzcnOf+EPbtG9DMBmADjFDc2w/rljwvFw==","algorithm":5}]}`
+
+	var secureDNS ReverseDelegationSecureDNS
+	if err := json.Unmarshal([]byte(input), &secureDNS); err != nil {
+		t.Fatalf("Unexpected error “%s”", err)
+	}
+
+	if len(secureDNS.KeyData) != 1 || secureDNS.KeyData[0].KeyTag() != 60485 {
+		t.Errorf("Unexpected secure DNS “%#v”", secureDNS)
+	}
+
+	report := NewReverseDNSSECReport(&ReverseDelegation{SecureDNS: &secureDNS})
+	if report.Verdict != DNSSECVerdictUnchecked {
+		t.Errorf("Unexpected verdict “%s” for a delegation with only key data", report.Verdict)
+	}
+}
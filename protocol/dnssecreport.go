@@ -0,0 +1,181 @@
+package protocol
+
+import "time"
+
+// List of possible verdicts of a DNSSEC report
+const (
+	// DNSSECVerdictSecure at least one DS record was validated with the keys
+	// published by the nameservers
+	DNSSECVerdictSecure DNSSECVerdict = "secure"
+
+	// DNSSECVerdictInsecure the delegation isn't signed
+	DNSSECVerdictInsecure DNSSECVerdict = "insecure"
+
+	// DNSSECVerdictBroken none of the checked DS records could be validated,
+	// so validating resolvers will fail to resolve the zone
+	DNSSECVerdictBroken DNSSECVerdict = "broken"
+
+	// DNSSECVerdictUnchecked the delegation is signed, but there's no
+	// delegation sign check event for its DS records
+	DNSSECVerdictUnchecked DNSSECVerdict = "unchecked"
+)
+
+// DNSSECVerdict stores the overall result of the DNSSEC checks
+type DNSSECVerdict string
+
+// dsStatusExplanations describes the problems found by the NIC.br delegation
+// sign checks
+var dsStatusExplanations = map[Status]string{
+	StatusDSTimeout:    "the nameservers didn't answer the DNSSEC query",
+	StatusDSNoSig:      "no signature (RRSIG) was found in the answer",
+	StatusDSExpiredSig: "the signature (RRSIG) is expired",
+	StatusDSInvalidSig: "the signature (RRSIG) doesn't match the public key (DNSKEY)",
+	StatusDSNotFound:   "the public key (DNSKEY) of the DS record wasn't found in the keyset",
+	StatusDSNoSEP:      "the public key (DNSKEY) of the DS record isn't a secure entry point",
+	StatusNone:         "a DNS error occurred while checking the DS record",
+}
+
+// DSHealth stores the result of the delegation sign checks of a DS record
+type DSHealth struct {
+	// Zone is the reverse zone of the DS record. It is empty for domains
+	Zone string
+
+	KeyTag     int
+	Algorithm  DNSSECAlgorithm
+	DigestType DigestType
+
+	// Deprecated is true when the algorithm or the digest type shouldn't be
+	// used anymore (RFC 8624)
+	Deprecated bool
+
+	// Checked is true when the DS record has a delegation sign check event
+	Checked bool
+
+	// LastCheck is the date of the latest delegation sign check
+	LastCheck time.Time
+
+	// LastCorrect is the date of the last time that the nameservers were well
+	// configured for the DS record
+	LastCorrect time.Time
+
+	// Status are the values of the latest delegation sign check
+	Status []Status
+
+	// Problems are the status of the latest delegation sign check that
+	// indicate a misconfiguration
+	Problems []DelegationProblem
+}
+
+// OK returns true when the DS record was checked and no problem was found
+func (d DSHealth) OK() bool {
+	return d.Checked && len(d.Problems) == 0
+}
+
+// DNSSECReport summarizes the DS records and the NIC.br delegation sign check
+// events of a delegation
+type DNSSECReport struct {
+	DS      []DSHealth
+	Verdict DNSSECVerdict
+}
+
+// NewDNSSECReport builds the DNSSEC report from the DS records of the domain
+func NewDNSSECReport(domain *Domain) DNSSECReport {
+	var ds []DSHealth
+	var signed bool
+
+	if domain.SecureDNS != nil {
+		for _, item := range domain.SecureDNS.DSData {
+			ds = append(ds, newDSHealth("", item.KeyTag, item.Algorithm, item.DigestType, item.Events))
+		}
+		signed = domain.SecureDNS.DelegationSigned || len(domain.SecureDNS.KeyData) > 0
+	}
+
+	return newDNSSECReport(ds, signed)
+}
+
+// NewReverseDNSSECReport builds the DNSSEC report from the DS records of the
+// IP network reverse delegation
+func NewReverseDNSSECReport(reverseDelegation *ReverseDelegation) DNSSECReport {
+	var ds []DSHealth
+	var signed bool
+
+	if reverseDelegation.SecureDNS != nil {
+		for _, item := range reverseDelegation.SecureDNS.DSSet {
+			ds = append(ds, newDSHealth(item.Zone, item.KeyTag, item.Algorithm, item.DigestType, item.Events))
+		}
+		signed = reverseDelegation.SecureDNS.DelegationSigned || len(reverseDelegation.SecureDNS.KeyData) > 0
+	}
+
+	return newDNSSECReport(ds, signed)
+}
+
+// newDNSSECReport builds the report from the DS records health. A signed
+// delegation without DS records, like the ones that publish only keyData, is
+// considered unchecked
+func newDNSSECReport(ds []DSHealth, signed bool) DNSSECReport {
+	report := DNSSECReport{
+		DS:      ds,
+		Verdict: DNSSECVerdictInsecure,
+	}
+
+	if len(ds) == 0 {
+		if signed {
+			report.Verdict = DNSSECVerdictUnchecked
+		}
+		return report
+	}
+
+	report.Verdict = DNSSECVerdictUnchecked
+	for _, item := range ds {
+		if item.OK() {
+			report.Verdict = DNSSECVerdictSecure
+			break
+
+		} else if item.Checked {
+			report.Verdict = DNSSECVerdictBroken
+		}
+	}
+
+	return report
+}
+
+func newDSHealth(zone string, keyTag, algorithm, digestType int, events []Event) DSHealth {
+	health := DSHealth{
+		Zone:       zone,
+		KeyTag:     keyTag,
+		Algorithm:  DNSSECAlgorithm(algorithm),
+		DigestType: DigestType(digestType),
+	}
+	health.Deprecated = health.Algorithm.Deprecated() || health.DigestType.Deprecated()
+
+	if event, ok := lastEvent(events, EventLastCorrectDelegationSignCheck); ok {
+		health.LastCorrect = event.Date.Time
+	}
+
+	check, ok := lastEvent(events, EventDelegationSignCheck)
+	if !ok {
+		return health
+	}
+
+	health.Checked = true
+	health.LastCheck = check.Date.Time
+	health.Status = check.Status
+
+	for _, status := range check.Status {
+		if status == StatusDSOK {
+			continue
+		}
+
+		explanation, ok := dsStatusExplanations[status]
+		if !ok {
+			explanation = "unknown delegation sign check result"
+		}
+
+		health.Problems = append(health.Problems, DelegationProblem{
+			Status:      status,
+			Explanation: explanation,
+		})
+	}
+
+	return health
+}
@@ -0,0 +1,201 @@
+package protocol
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewDNSSECReport(t *testing.T) {
+	day := func(d int) EventDate {
+		return Date(2024, time.May, d, 12, 0, 0, 0, time.UTC)
+	}
+
+	data := []struct {
+		description string
+		domain      Domain
+		expected    DNSSECReport
+	}{
+		{
+			description: "it should report a secure delegation",
+			domain: Domain{
+				SecureDNS: &SecureDNS{
+					DelegationSigned: true,
+					DSData: []DS{
+						{
+							KeyTag:     12345,
+							Algorithm:  5,
+							DigestType: 1,
+							Events: []Event{
+								{Action: EventDelegationSignCheck, Date: day(10), Status: []Status{StatusDSNotFound}},
+							},
+						},
+						{
+							KeyTag:     47216,
+							Algorithm:  13,
+							DigestType: 2,
+							Events: []Event{
+								{Action: EventDelegationSignCheck, Date: day(9), Status: []Status{StatusDSNoSig}},
+								{Action: EventDelegationSignCheck, Date: day(10), Status: []Status{StatusDSOK}},
+								{Action: EventLastCorrectDelegationSignCheck, Date: day(10)},
+							},
+						},
+					},
+				},
+			},
+			expected: DNSSECReport{
+				DS: []DSHealth{
+					{
+						KeyTag:     12345,
+						Algorithm:  AlgorithmRSASHA1,
+						DigestType: DigestTypeSHA1,
+						Deprecated: true,
+						Checked:    true,
+						LastCheck:  day(10).Time,
+						Status:     []Status{StatusDSNotFound},
+						Problems: []DelegationProblem{
+							{
+								Status:      StatusDSNotFound,
+								Explanation: "the public key (DNSKEY) of the DS record wasn't found in the keyset",
+							},
+						},
+					},
+					{
+						KeyTag:      47216,
+						Algorithm:   AlgorithmECDSAP256SHA256,
+						DigestType:  DigestTypeSHA256,
+						Checked:     true,
+						LastCheck:   day(10).Time,
+						LastCorrect: day(10).Time,
+						Status:      []Status{StatusDSOK},
+					},
+				},
+				Verdict: DNSSECVerdictSecure,
+			},
+		},
+		{
+			description: "it should report a broken delegation",
+			domain: Domain{
+				SecureDNS: &SecureDNS{
+					DSData: []DS{
+						{
+							KeyTag:     47216,
+							Algorithm:  8,
+							DigestType: 2,
+							Events: []Event{
+								{Action: EventDelegationSignCheck, Date: day(10), Status: []Status{StatusDSExpiredSig, "ds something new"}},
+							},
+						},
+					},
+				},
+			},
+			expected: DNSSECReport{
+				DS: []DSHealth{
+					{
+						KeyTag:     47216,
+						Algorithm:  AlgorithmRSASHA256,
+						DigestType: DigestTypeSHA256,
+						Checked:    true,
+						LastCheck:  day(10).Time,
+						Status:     []Status{StatusDSExpiredSig, "ds something new"},
+						Problems: []DelegationProblem{
+							{
+								Status:      StatusDSExpiredSig,
+								Explanation: "the signature (RRSIG) is expired",
+							},
+							{
+								Status:      "ds something new",
+								Explanation: "unknown delegation sign check result",
+							},
+						},
+					},
+				},
+				Verdict: DNSSECVerdictBroken,
+			},
+		},
+		{
+			description: "it should report an unchecked delegation",
+			domain: Domain{
+				SecureDNS: &SecureDNS{
+					DSData: []DS{{KeyTag: 1, Algorithm: 15, DigestType: 4}},
+				},
+			},
+			expected: DNSSECReport{
+				DS: []DSHealth{
+					{KeyTag: 1, Algorithm: AlgorithmED25519, DigestType: DigestTypeSHA384},
+				},
+				Verdict: DNSSECVerdictUnchecked,
+			},
+		},
+		{
+			description: "it should report an insecure delegation",
+			domain:      Domain{},
+			expected: DNSSECReport{
+				Verdict: DNSSECVerdictInsecure,
+			},
+		},
+	}
+
+	for i, item := range data {
+		if report := NewDNSSECReport(&item.domain); !reflect.DeepEqual(item.expected, report) {
+			t.Errorf("[%d] %s: unexpected report. Expected “%#v” and got “%#v”", i, item.description, item.expected, report)
+		}
+	}
+}
+
+func TestNewReverseDNSSECReport(t *testing.T) {
+	reverseDelegation := ReverseDelegation{
+		StartAddress: "200.160.0.0",
+		EndAddress:   "200.160.0.255",
+		SecureDNS: &ReverseDelegationSecureDNS{
+			DelegationSigned: true,
+			DSSet: []ReverseDS{
+				{
+					Zone:       "0.160.200.in-addr.arpa",
+					KeyTag:     4321,
+					Algorithm:  3,
+					DigestType: 2,
+					Events: []Event{
+						{
+							Action: EventDelegationSignCheck,
+							Date:   Date(2024, time.May, 10, 0, 0, 0, 0, time.UTC),
+							Status: []Status{StatusDSOK},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	report := NewReverseDNSSECReport(&reverseDelegation)
+	if report.Verdict != DNSSECVerdictSecure {
+		t.Errorf("Unexpected verdict “%s”", report.Verdict)
+	}
+
+	if len(report.DS) != 1 || report.DS[0].Zone != "0.160.200.in-addr.arpa" || !report.DS[0].Deprecated {
+		t.Errorf("Unexpected DS records “%#v”", report.DS)
+	}
+
+	if NewReverseDNSSECReport(&ReverseDelegation{}).Verdict != DNSSECVerdictInsecure {
+		t.Error("Reverse delegation without DS records should be insecure")
+	}
+}
+
+func TestDNSSECNames(t *testing.T) {
+	data := []struct {
+		value    interface{ String() string }
+		expected string
+	}{
+		{value: AlgorithmECDSAP256SHA256, expected: "ECDSAP256SHA256"},
+		{value: AlgorithmRSASHA1NSEC3SHA1, expected: "RSASHA1-NSEC3-SHA1"},
+		{value: DNSSECAlgorithm(99), expected: "99"},
+		{value: DigestTypeSHA384, expected: "SHA-384"},
+		{value: DigestType(0), expected: "0"},
+	}
+
+	for i, item := range data {
+		if name := item.value.String(); name != item.expected {
+			t.Errorf("[%d] expected name “%s” and got “%s”", i, item.expected, name)
+		}
+	}
+}
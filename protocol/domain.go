@@ -1,14 +1,58 @@
 package protocol
 
-// Domain describes Domain Object Class as it is in RFC 7483, section 5.3
+import "encoding/json"
+
+// List of variant relations as described in RFC 9083, section 10.2.5
+const (
+	// VariantRelationRegistered the variant names are registered in the
+	// registry
+	VariantRelationRegistered VariantRelation = "registered"
+
+	// VariantRelationUnregistered the variant names are not found in the
+	// registry
+	VariantRelationUnregistered VariantRelation = "unregistered"
+
+	// VariantRelationRegistrationRestricted registration of the variant names
+	// is restricted to certain parties or within certain rules
+	VariantRelationRegistrationRestricted VariantRelation = "registration restricted"
+
+	// VariantRelationOpenRegistration registration of the variant names is
+	// available to generally qualified registrants
+	VariantRelationOpenRegistration VariantRelation = "open registration"
+
+	// VariantRelationConjoined registration of the variant names occurs
+	// automatically with the registration of the containing domain
+	// registration
+	VariantRelationConjoined VariantRelation = "conjoined"
+)
+
+// VariantRelation stores one of the possible variant relations as listed in
+// RFC 9083, section 10.2.5
+type VariantRelation string
+
+// VariantName describes a variant of an internationalized domain name
+type VariantName struct {
+	LDHName     string `json:"ldhName,omitempty"`
+	UnicodeName string `json:"unicodeName,omitempty"`
+}
+
+// Variant describes the variants of a domain name as it is in RFC 9083,
+// section 5.3
+type Variant struct {
+	Relation     []VariantRelation `json:"relation,omitempty"`
+	IDNTable     string            `json:"idnTable,omitempty"`
+	VariantNames []VariantName     `json:"variantNames,omitempty"`
+}
+
+// Domain describes Domain Object Class as it is in RFC 9083, section 5.3
 type Domain struct {
 	ObjectClassName string       `json:"objectClassName"`
 	Handle          string       `json:"handle,omitempty"`
 	LDHName         string       `json:"ldhName,omitempty"`
 	UnicodeName     string       `json:"unicodeName,omitempty"`
+	Variants        []Variant    `json:"variants,omitempty"`
 	Nameservers     []Nameserver `json:"nameservers,omitempty"`
 	SecureDNS       *SecureDNS   `json:"secureDNS,omitempty"`
-	Arbitration     bool         `json:"nicbr_arbitration,omitempty"`
 	Links           []Link       `json:"links,omitempty"`
 	Entities        []Entity     `json:"entities,omitempty"`
 	Events          []Event      `json:"events,omitempty"`
@@ -21,4 +65,75 @@ type Domain struct {
 	Lang            string       `json:"lang,omitempty"`
 	Conformance
 	Port43
+
+	// Arbitration is a NIC.br extension member.
+	//
+	// Deprecated: use the NICBRDomain type returned by Extension(NICBRPrefix)
+	Arbitration bool `json:"-"`
+
+	// Extensions stores the members unknown to this library
+	Extensions map[string]json.RawMessage `json:"-"`
 }
+
+// domainJSON avoids the recursion of the Domain JSON methods
+type domainJSON Domain
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Members unknown to
+// the Domain type are stored in the Extensions attribute
+func (d *Domain) UnmarshalJSON(data []byte) error {
+	if err := unmarshalObject(data, (*domainJSON)(d), &d.Extensions); err != nil {
+		return err
+	}
+
+	return d.loadNICBR()
+}
+
+// MarshalJSON implements the json.Marshaler interface. The members from the
+// Extensions attribute are written after the known members in alphabetical
+// order
+func (d Domain) MarshalJSON() ([]byte, error) {
+	extensions, err := d.storeNICBR()
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalObject(domainJSON(d), extensions)
+}
+
+// Extension decodes the members of the registered extension identified by the
+// prefix into the type that the extension defines for the domain object
+// class. If the object doesn't have any member of the extension, nil is
+// returned
+func (d *Domain) Extension(prefix string) (any, error) {
+	return decodeExtension(ObjectClassDomain, prefix, d.Extensions)
+}
+
+// SetExtension replaces the members of the registered extension identified by
+// the prefix with the ones encoded from value
+func (d *Domain) SetExtension(prefix string, value any) error {
+	extensions, err := encodeExtension(prefix, d.Extensions, value)
+	if err != nil {
+		return err
+	}
+
+	d.Extensions = extensions
+	return d.loadNICBR()
+}
+
+// GetHandle implements the Object interface
+func (d *Domain) GetHandle() string { return d.Handle }
+
+// GetLinks implements the Object interface
+func (d *Domain) GetLinks() []Link { return d.Links }
+
+// GetEvents implements the Object interface
+func (d *Domain) GetEvents() []Event { return d.Events }
+
+// GetEntities implements the Object interface
+func (d *Domain) GetEntities() []Entity { return d.Entities }
+
+// GetRemarks implements the Object interface
+func (d *Domain) GetRemarks() []Remark { return d.Remarks }
+
+// GetNotices implements the Object interface
+func (d *Domain) GetNotices() []Notice { return d.Notices }
@@ -0,0 +1,158 @@
+package protocol
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDomainUnmarshalJSON(t *testing.T) {
+	// example from RFC 9083, section 5.3 (without remarks, links and DNSSEC
+	// data to keep it short)
+	input := `{
+  "objectClassName" : "domain",
+  "handle" : "XXXX",
+  "ldhName" : "xn--fo-5ja.example",
+  "unicodeName" : "fóo.example",
+  "variants" :
+  [
+    {
+      "relation" : [ "registered", "conjoined" ],
+      "variantNames" :
+      [
+        {
+          "ldhName" : "xn--fo-cka.example",
+          "unicodeName" : "fõo.example"
+        },
+        {
+          "ldhName" : "xn--fo-fka.example",
+          "unicodeName" : "föo.example"
+        }
+      ]
+    },
+    {
+      "relation" : [ "unregistered", "registration restricted" ],
+      "idnTable": ".EXAMPLE Swedish",
+      "variantNames" :
+      [
+        {
+          "ldhName": "xn--fo-8ja.example",
+          "unicodeName" : "fôo.example"
+        }
+      ]
+    }
+  ],
+  "status" : [ "locked", "transfer prohibited" ],
+  "publicIds":[
+    {
+      "type":"ENS_Auth ID",
+      "identifier":"1234567890"
+    }
+  ],
+  "nameservers" :
+  [
+    {
+      "objectClassName" : "nameserver",
+      "handle" : "XXXX",
+      "ldhName" : "ns1.example.com",
+      "status" : [ "active" ],
+      "ipAddresses" :
+      {
+        "v6": [ "2001:db8::123", "2001:db8::124" ],
+        "v4": [ "192.0.2.1", "192.0.2.2" ]
+      }
+    }
+  ],
+  "port43" : "whois.example.net",
+  "events" :
+  [
+    {
+      "eventAction" : "registration",
+      "eventDate" : "1990-12-31T23:59:59Z"
+    },
+    {
+      "eventAction" : "last changed",
+      "eventDate" : "1991-12-31T23:59:59Z",
+      "eventActor" : "joe@example.com"
+    }
+  ],
+  "entities" :
+  [
+    {
+      "objectClassName" : "entity",
+      "handle" : "XXXX",
+      "status" : [ "validated", "locked" ],
+      "roles" : [ "registrant" ]
+    }
+  ]
+}`
+
+	expected := Domain{
+		ObjectClassName: "domain",
+		Handle:          "XXXX",
+		LDHName:         "xn--fo-5ja.example",
+		UnicodeName:     "fóo.example",
+		Variants: []Variant{
+			{
+				Relation: []VariantRelation{VariantRelationRegistered, VariantRelationConjoined},
+				VariantNames: []VariantName{
+					{LDHName: "xn--fo-cka.example", UnicodeName: "fõo.example"},
+					{LDHName: "xn--fo-fka.example", UnicodeName: "föo.example"},
+				},
+			},
+			{
+				Relation: []VariantRelation{VariantRelationUnregistered, VariantRelationRegistrationRestricted},
+				IDNTable: ".EXAMPLE Swedish",
+				VariantNames: []VariantName{
+					{LDHName: "xn--fo-8ja.example", UnicodeName: "fôo.example"},
+				},
+			},
+		},
+		Status: []Status{StatusLocked, StatusTransferProhibited},
+		PublicIDs: []PublicID{
+			{Type: "ENS_Auth ID", Identifier: "1234567890"},
+		},
+		Nameservers: []Nameserver{
+			{
+				ObjectClassName: "nameserver",
+				Handle:          "XXXX",
+				LDHName:         "ns1.example.com",
+				Status:          []Status{StatusActive},
+				IPAddresses: &IPAddresses{
+					V6: []string{"2001:db8::123", "2001:db8::124"},
+					V4: []string{"192.0.2.1", "192.0.2.2"},
+				},
+			},
+		},
+		Port43: Port43{Port43: "whois.example.net"},
+		Events: []Event{
+			{
+				Action: EventActionRegistration,
+				Date:   Date(1990, 12, 31, 23, 59, 59, 0, time.UTC),
+			},
+			{
+				Action: EventActionLastChanged,
+				Date:   Date(1991, 12, 31, 23, 59, 59, 0, time.UTC),
+				Actor:  "joe@example.com",
+			},
+		},
+		Entities: []Entity{
+			{
+				ObjectClassName: "entity",
+				Handle:          "XXXX",
+				Status:          []Status{StatusValidated, StatusLocked},
+				Roles:           []string{"registrant"},
+			},
+		},
+	}
+
+	var domain Domain
+	if err := json.Unmarshal([]byte(input), &domain); err != nil {
+		t.Fatalf("Unexpected error “%s”", err)
+	}
+
+	if !reflect.DeepEqual(expected, domain) {
+		t.Errorf("Unexpected domain. Expected “%#v” and got “%#v”", expected, domain)
+	}
+}
@@ -1,8 +1,64 @@
 package protocol
 
-import "slices"
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+)
 
-// PublicID describes Public IDs as it is in RFC 7483, section 4.8
+// List of entity roles as described in RFC 9083, section 10.2.4
+const (
+	// RoleRegistrant the entity object instance is the registrant of the
+	// registration. In some registries, this is known as a maintainer
+	RoleRegistrant Role = "registrant"
+
+	// RoleTechnical the entity object instance is a technical contact for
+	// the registration
+	RoleTechnical Role = "technical"
+
+	// RoleAdministrative the entity object instance is an administrative
+	// contact for the registration
+	RoleAdministrative Role = "administrative"
+
+	// RoleAbuse the entity object instance handles network abuse issues on
+	// behalf of the registrant of the registration
+	RoleAbuse Role = "abuse"
+
+	// RoleBilling the entity object instance handles payment and billing
+	// issues on behalf of the registrant of the registration
+	RoleBilling Role = "billing"
+
+	// RoleRegistrar the entity object instance represents the authority
+	// responsible for the registration in the registry
+	RoleRegistrar Role = "registrar"
+
+	// RoleReseller the entity object instance represents a third party
+	// through which the registration was conducted (i.e., not the registry or
+	// registrar)
+	RoleReseller Role = "reseller"
+
+	// RoleSponsor the entity object instance represents a domain policy
+	// sponsor, such as an ICANN-approved sponsor
+	RoleSponsor Role = "sponsor"
+
+	// RoleProxy the entity object instance represents a proxy for another
+	// entity object, such as a registrant
+	RoleProxy Role = "proxy"
+
+	// RoleNotifications an entity object instance designated to receive
+	// notifications about association object instances
+	RoleNotifications Role = "notifications"
+
+	// RoleNOC the entity object instance handles communications related to a
+	// network operations center (NOC)
+	RoleNOC Role = "noc"
+)
+
+// Role stores one of the possible entity roles as listed in RFC 9083,
+// section 10.2.4
+type Role string
+
+// PublicID describes Public IDs as it is in RFC 9083, section 4.8
 type PublicID struct {
 	Type       string `json:"type"`
 	Identifier string `json:"identifier"`
@@ -16,35 +72,71 @@ type CustomerSupportService struct {
 	Phone   string `json:"nicbr_phone,omitempty"`
 }
 
-// Entity describes the Entity Object Class as it is in RFC 7483, section 5.1
+// Entity describes the Entity Object Class as it is in RFC 9083, section 5.1
 type Entity struct {
-	ObjectClassName        string                  `json:"objectClassName"`
-	Handle                 string                  `json:"handle,omitempty"`
-	VCardArray             []any                   `json:"vcardArray,omitempty"`
-	Roles                  []string                `json:"roles,omitempty"`
-	PublicIds              []PublicID              `json:"publicIds,omitempty"`
-	Networks               []IPNetwork             `json:"networks,omitempty"`
-	Autnums                []AS                    `json:"autnums,omitempty"`
-	CustomerSupportService *CustomerSupportService `json:"nicbr_customerSupportService,omitempty"`
-	Entities               []Entity                `json:"entities,omitempty"`
-	Events                 []Event                 `json:"events,omitempty"`
-	Links                  []Link                  `json:"links,omitempty"`
-	Remarks                []Remark                `json:"remarks,omitempty"`
-	Notices                []Notice                `json:"notices,omitempty"`
-	DomainCount            int                     `json:"nicbr_domainCount,omitempty"`
-	InetCount              int                     `json:"nicbr_inetCount,omitempty"`
-	AutnumCount            int                     `json:"nicbr_autnumCount,omitempty"`
-	Lang                   string                  `json:"lang,omitempty"`
+	ObjectClassName string      `json:"objectClassName"`
+	Handle          string      `json:"handle,omitempty"`
+	VCardArray      []any       `json:"vcardArray,omitempty"`
+	Roles           []string    `json:"roles,omitempty"`
+	PublicIds       []PublicID  `json:"publicIds,omitempty"`
+	Networks        []IPNetwork `json:"networks,omitempty"`
+	Autnums         []AS        `json:"autnums,omitempty"`
+	Entities        []Entity    `json:"entities,omitempty"`
+	Events          []Event     `json:"events,omitempty"`
+	AsEventActor    []Event     `json:"asEventActor,omitempty"`
+	Status          []Status    `json:"status,omitempty"`
+	Links           []Link      `json:"links,omitempty"`
+	Remarks         []Remark    `json:"remarks,omitempty"`
+	Notices         []Notice    `json:"notices,omitempty"`
+	Lang            string      `json:"lang,omitempty"`
 	Conformance
 	Port43
 
 	// LegalRepresentative was proposed by NIC.br to store the name of the
 	// persons that is responsible for this entity
 	LegalRepresentative string `json:"legalRepresentative,omitempty"`
+
+	// CustomerSupportService, DomainCount, InetCount and AutnumCount are
+	// NIC.br extension members.
+	//
+	// Deprecated: use the NICBREntity type returned by Extension(NICBRPrefix)
+	CustomerSupportService *CustomerSupportService `json:"-"`
+	DomainCount            int                     `json:"-"`
+	InetCount              int                     `json:"-"`
+	AutnumCount            int                     `json:"-"`
+
+	// Extensions stores the members unknown to this library
+	Extensions map[string]json.RawMessage `json:"-"`
 }
 
-// GetEntity is an easy way to find an entity with a given role. If more than
-// one entity has the same role, the last one is returned
+// entityJSON avoids the recursion of the Entity JSON methods
+type entityJSON Entity
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Members unknown to
+// the Entity type are stored in the Extensions attribute
+func (e *Entity) UnmarshalJSON(data []byte) error {
+	if err := unmarshalObject(data, (*entityJSON)(e), &e.Extensions); err != nil {
+		return err
+	}
+
+	return e.loadNICBR()
+}
+
+// MarshalJSON implements the json.Marshaler interface. The members from the
+// Extensions attribute are written after the known members in alphabetical
+// order
+func (e Entity) MarshalJSON() ([]byte, error) {
+	extensions, err := e.storeNICBR()
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalObject(entityJSON(e), extensions)
+}
+
+// GetEntity is an easy way to find a direct child entity with a given role. If
+// more than one entity has the same role, the first one is returned. Use
+// FindEntities to search the whole entity tree
 func (e *Entity) GetEntity(role string) (entity Entity, found bool) {
 	for i := range e.Entities {
 		v := e.Entities[i]
@@ -57,3 +149,87 @@ func (e *Entity) GetEntity(role string) (entity Entity, found bool) {
 
 	return
 }
+
+// Emails returns the e-mail addresses of the entity jCard (RFC 7095), in the
+// order that they appear. Malformed properties are ignored
+func (e *Entity) Emails() []string {
+	return e.vcardValues("email")
+}
+
+// FullName returns the formatted name (fn property) of the entity jCard. When
+// there's no name an empty string is returned
+func (e *Entity) FullName() string {
+	if names := e.vcardValues("fn"); len(names) > 0 {
+		return names[0]
+	}
+	return ""
+}
+
+// vcardValues returns the text values of the jCard property
+func (e *Entity) vcardValues(property string) []string {
+	if len(e.VCardArray) < 2 {
+		return nil
+	}
+
+	properties, ok := e.VCardArray[1].([]any)
+	if !ok {
+		return nil
+	}
+
+	var result []string
+	for _, item := range properties {
+		values, ok := item.([]any)
+		if !ok || len(values) < 4 {
+			continue
+		}
+
+		name, _ := values[0].(string)
+		if !strings.EqualFold(name, property) {
+			continue
+		}
+
+		if value, ok := values[3].(string); ok && value != "" {
+			result = append(result, value)
+		}
+	}
+
+	return result
+}
+
+// Extension decodes the members of the registered extension identified by the
+// prefix into the type that the extension defines for the entity object
+// class. If the object doesn't have any member of the extension, nil is
+// returned
+func (e *Entity) Extension(prefix string) (any, error) {
+	return decodeExtension(ObjectClassEntity, prefix, e.Extensions)
+}
+
+// SetExtension replaces the members of the registered extension identified by
+// the prefix with the ones encoded from value
+func (e *Entity) SetExtension(prefix string, value any) error {
+	extensions, err := encodeExtension(prefix, e.Extensions, value)
+	if err != nil {
+		return err
+	}
+
+	e.Extensions = extensions
+	return e.loadNICBR()
+}
+
+// GetHandle implements the Object interface
+func (e *Entity) GetHandle() string { return e.Handle }
+
+// GetLinks implements the Object interface
+func (e *Entity) GetLinks() []Link { return e.Links }
+
+// GetEvents implements the Object interface
+func (e *Entity) GetEvents() []Event { return e.Events }
+
+// GetEntities implements the Object interface
+func (e *Entity) GetEntities() []Entity { return e.Entities }
+
+// GetRemarks implements the Object interface
+func (e *Entity) GetRemarks() []Remark { return e.Remarks }
+
+// GetNotices implements the Object interface
+func (e *Entity) GetNotices() []Notice { return e.Notices }
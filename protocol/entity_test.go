@@ -1,8 +1,10 @@
 package protocol
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestEntityGetEntity(t *testing.T) {
@@ -58,3 +60,147 @@ func TestEntityGetEntity(t *testing.T) {
 	}
 
 }
+
+func TestEntityEmails(t *testing.T) {
+	data := []struct {
+		description string
+		data        string
+		expected    []string
+	}{
+		{
+			description: "it should return all e-mails",
+			data: `{"objectClassName":"entity","vcardArray":["vcard",[["version",{},"text","4.0"],` +
+				`["email",{"type":"work"},"text","abuse@example.com"],["EMAIL",{},"text","noc@example.com"]]]}`,
+			expected: []string{"abuse@example.com", "noc@example.com"},
+		},
+		{
+			description: "it should ignore malformed properties",
+			data: `{"objectClassName":"entity","vcardArray":["vcard",[["email",{},"text"],` +
+				`["email",{},"text",1],"email",["email",{},"text","abuse@example.com"]]]}`,
+			expected: []string{"abuse@example.com"},
+		},
+		{
+			description: "it should ignore a malformed jCard",
+			data:        `{"objectClassName":"entity","vcardArray":["vcard","email"]}`,
+		},
+		{
+			description: "it should ignore an entity without jCard",
+			data:        `{"objectClassName":"entity"}`,
+		},
+	}
+
+	for i, item := range data {
+		var entity Entity
+		if err := json.Unmarshal([]byte(item.data), &entity); err != nil {
+			t.Fatalf("[%d] %s: unexpected error “%s”", i, item.description, err)
+		}
+
+		if emails := entity.Emails(); !reflect.DeepEqual(item.expected, emails) {
+			t.Errorf("[%d] %s: unexpected e-mails. Expected “%v” and got “%v”", i, item.description, item.expected, emails)
+		}
+	}
+}
+
+func TestEntityFullName(t *testing.T) {
+	data := []struct {
+		description string
+		data        string
+		expected    string
+	}{
+		{
+			description: "it should return the first formatted name",
+			data: `{"objectClassName":"entity","vcardArray":["vcard",[["version",{},"text","4.0"],` +
+				`["FN",{},"text","Joe User"],["fn",{},"text","Joe"]]]}`,
+			expected: "Joe User",
+		},
+		{
+			description: "it should ignore an entity without formatted name",
+			data:        `{"objectClassName":"entity","vcardArray":["vcard",[["version",{},"text","4.0"]]]}`,
+		},
+	}
+
+	for i, item := range data {
+		var entity Entity
+		if err := json.Unmarshal([]byte(item.data), &entity); err != nil {
+			t.Fatalf("[%d] %s: unexpected error “%s”", i, item.description, err)
+		}
+
+		if name := entity.FullName(); item.expected != name {
+			t.Errorf("[%d] %s: unexpected name. Expected “%s” and got “%s”", i, item.description, item.expected, name)
+		}
+	}
+}
+
+func TestEntityUnmarshalJSON(t *testing.T) {
+	// example from RFC 9083, section 5.1 (without vCard and remarks to keep it
+	// short)
+	input := `{
+  "objectClassName" : "entity",
+  "handle":"XXXX",
+  "roles":[ "registrar" ],
+  "publicIds":[
+    {
+      "type":"IANA Registrar ID",
+      "identifier":"1"
+    }
+  ],
+  "links":[
+    {
+      "value":"https://example.com/entity/XXXX",
+      "rel":"self",
+      "href":"https://example.com/entity/XXXX",
+      "type" : "application/rdap+json"
+    }
+  ],
+  "events":[
+    {
+      "eventAction":"registration",
+      "eventDate":"1990-12-31T23:59:59Z"
+    }
+  ],
+  "asEventActor":[
+    {
+      "eventAction":"last changed",
+      "eventDate":"1991-12-31T23:59:59Z"
+    }
+  ]
+}`
+
+	expected := Entity{
+		ObjectClassName: "entity",
+		Handle:          "XXXX",
+		Roles:           []string{"registrar"},
+		PublicIds: []PublicID{
+			{Type: "IANA Registrar ID", Identifier: "1"},
+		},
+		Links: []Link{
+			{
+				Value: "https://example.com/entity/XXXX",
+				Rel:   "self",
+				Href:  "https://example.com/entity/XXXX",
+				Type:  "application/rdap+json",
+			},
+		},
+		Events: []Event{
+			{
+				Action: EventActionRegistration,
+				Date:   Date(1990, 12, 31, 23, 59, 59, 0, time.UTC),
+			},
+		},
+		AsEventActor: []Event{
+			{
+				Action: EventActionLastChanged,
+				Date:   Date(1991, 12, 31, 23, 59, 59, 0, time.UTC),
+			},
+		},
+	}
+
+	var entity Entity
+	if err := json.Unmarshal([]byte(input), &entity); err != nil {
+		t.Fatalf("Unexpected error “%s”", err)
+	}
+
+	if !reflect.DeepEqual(expected, entity) {
+		t.Errorf("Unexpected entity. Expected “%#v” and got “%#v”", expected, entity)
+	}
+}
@@ -0,0 +1,116 @@
+package protocol
+
+import "strings"
+
+// List of EPP status codes from RFC 5731, RFC 5732, RFC 5733 and RFC 3915
+// that have an equivalent RDAP status as described in RFC 8056, section 2
+const (
+	EPPStatusAddPeriod                EPPStatus = "addPeriod"
+	EPPStatusAutoRenewPeriod          EPPStatus = "autoRenewPeriod"
+	EPPStatusClientDeleteProhibited   EPPStatus = "clientDeleteProhibited"
+	EPPStatusClientHold               EPPStatus = "clientHold"
+	EPPStatusClientRenewProhibited    EPPStatus = "clientRenewProhibited"
+	EPPStatusClientTransferProhibited EPPStatus = "clientTransferProhibited"
+	EPPStatusClientUpdateProhibited   EPPStatus = "clientUpdateProhibited"
+	EPPStatusInactive                 EPPStatus = "inactive"
+	EPPStatusLinked                   EPPStatus = "linked"
+	EPPStatusOK                       EPPStatus = "ok"
+	EPPStatusPendingCreate            EPPStatus = "pendingCreate"
+	EPPStatusPendingDelete            EPPStatus = "pendingDelete"
+	EPPStatusPendingRenew             EPPStatus = "pendingRenew"
+	EPPStatusPendingRestore           EPPStatus = "pendingRestore"
+	EPPStatusPendingTransfer          EPPStatus = "pendingTransfer"
+	EPPStatusPendingUpdate            EPPStatus = "pendingUpdate"
+	EPPStatusRedemptionPeriod         EPPStatus = "redemptionPeriod"
+	EPPStatusRenewPeriod              EPPStatus = "renewPeriod"
+	EPPStatusServerDeleteProhibited   EPPStatus = "serverDeleteProhibited"
+	EPPStatusServerHold               EPPStatus = "serverHold"
+	EPPStatusServerRenewProhibited    EPPStatus = "serverRenewProhibited"
+	EPPStatusServerTransferProhibited EPPStatus = "serverTransferProhibited"
+	EPPStatusServerUpdateProhibited   EPPStatus = "serverUpdateProhibited"
+	EPPStatusTransferPeriod           EPPStatus = "transferPeriod"
+)
+
+// EPPStatus stores a status code of the Extensible Provisioning Protocol
+type EPPStatus string
+
+// eppToStatus is the mapping table of RFC 8056, section 2
+var eppToStatus = map[EPPStatus]Status{
+	EPPStatusAddPeriod:                StatusAddPeriod,
+	EPPStatusAutoRenewPeriod:          StatusAutoRenewPeriod,
+	EPPStatusClientDeleteProhibited:   StatusClientDeleteProhibited,
+	EPPStatusClientHold:               StatusClientHold,
+	EPPStatusClientRenewProhibited:    StatusClientRenewProhibited,
+	EPPStatusClientTransferProhibited: StatusClientTransferProhibited,
+	EPPStatusClientUpdateProhibited:   StatusClientUpdateProhibited,
+	EPPStatusInactive:                 StatusInactive,
+	EPPStatusLinked:                   StatusAssociated,
+	EPPStatusOK:                       StatusActive,
+	EPPStatusPendingCreate:            StatusPendingCreate,
+	EPPStatusPendingDelete:            StatusPendingDelete,
+	EPPStatusPendingRenew:             StatusPendingRenew,
+	EPPStatusPendingRestore:           StatusPendingRestore,
+	EPPStatusPendingTransfer:          StatusPendingTransfer,
+	EPPStatusPendingUpdate:            StatusPendingUpdate,
+	EPPStatusRedemptionPeriod:         StatusRedemptionPeriod,
+	EPPStatusRenewPeriod:              StatusRenewPeriod,
+	EPPStatusServerDeleteProhibited:   StatusServerDeleteProhibited,
+	EPPStatusServerHold:               StatusServerHold,
+	EPPStatusServerRenewProhibited:    StatusServerRenewProhibited,
+	EPPStatusServerTransferProhibited: StatusServerTransferProhibited,
+	EPPStatusServerUpdateProhibited:   StatusServerUpdateProhibited,
+	EPPStatusTransferPeriod:           StatusTransferPeriod,
+}
+
+var statusToEPP = func() map[Status]EPPStatus {
+	m := make(map[Status]EPPStatus, len(eppToStatus))
+	for epp, status := range eppToStatus {
+		m[status] = epp
+	}
+	return m
+}()
+
+// StatusFromEPP converts the EPP status code to the RDAP status. The EPP code
+// is case sensitive, and if there's no equivalent RDAP status false is
+// returned
+func StatusFromEPP(code EPPStatus) (Status, bool) {
+	status, ok := eppToStatus[code]
+	return status, ok
+}
+
+// EPP converts the RDAP status to the EPP status code. If there's no
+// equivalent EPP code false is returned
+func (s Status) EPP() (EPPStatus, bool) {
+	code, ok := statusToEPP[s]
+	return code, ok
+}
+
+// IsProhibition checks if the status forbids some action over the object,
+// like "client transfer prohibited" or "update prohibited"
+func (s Status) IsProhibition() bool {
+	return strings.HasSuffix(string(s), " prohibited")
+}
+
+// IsPending checks if the status informs an action that was requested but
+// isn't complete yet, like "pending delete" or "pending restore"
+func (s Status) IsPending() bool {
+	return strings.HasPrefix(string(s), "pending ")
+}
+
+// IsGracePeriod checks if the status is one of the periods of the Domain
+// Registry Grace Period Mapping (RFC 3915), including the redemption period
+func (s Status) IsGracePeriod() bool {
+	switch s {
+	case StatusAddPeriod, StatusAutoRenewPeriod, StatusRenewPeriod,
+		StatusTransferPeriod, StatusRedemptionPeriod:
+		return true
+	}
+
+	return false
+}
+
+// IsHold checks if the DNS delegation information must not be published
+// because of a client or server hold
+func (s Status) IsHold() bool {
+	return s == StatusClientHold || s == StatusServerHold
+}
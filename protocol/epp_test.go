@@ -0,0 +1,98 @@
+package protocol
+
+import "testing"
+
+func TestStatusEPP(t *testing.T) {
+	data := []struct {
+		description   string
+		code          EPPStatus
+		status        Status
+		expectedFound bool
+	}{
+		{
+			description:   "it should map the ok code",
+			code:          EPPStatusOK,
+			status:        StatusActive,
+			expectedFound: true,
+		},
+		{
+			description:   "it should map the linked code",
+			code:          EPPStatusLinked,
+			status:        StatusAssociated,
+			expectedFound: true,
+		},
+		{
+			description:   "it should map a prohibition",
+			code:          EPPStatusClientTransferProhibited,
+			status:        StatusClientTransferProhibited,
+			expectedFound: true,
+		},
+		{
+			description:   "it should map a grace period",
+			code:          EPPStatusRedemptionPeriod,
+			status:        StatusRedemptionPeriod,
+			expectedFound: true,
+		},
+		{
+			description: "it should not map an unknown code",
+			code:        "clienttransferprohibited",
+		},
+		{
+			description: "it should not map a status without EPP code",
+			status:      StatusValidated,
+		},
+	}
+
+	for i, item := range data {
+		if item.code != "" {
+			status, found := StatusFromEPP(item.code)
+			if found != item.expectedFound || (found && status != item.status) {
+				t.Errorf("[%d] %s: unexpected status “%s” (%t)", i, item.description, status, found)
+			}
+		}
+
+		if item.status != "" {
+			code, found := item.status.EPP()
+			if found != item.expectedFound || (found && code != item.code) {
+				t.Errorf("[%d] %s: unexpected EPP code “%s” (%t)", i, item.description, code, found)
+			}
+		}
+	}
+
+	for code, status := range eppToStatus {
+		if converted, _ := status.EPP(); converted != code {
+			t.Errorf("EPP code “%s” converted to “%s” and back to “%s”", code, status, converted)
+		}
+	}
+}
+
+func TestStatusClassification(t *testing.T) {
+	data := []struct {
+		status        Status
+		isProhibition bool
+		isPending     bool
+		isGracePeriod bool
+		isHold        bool
+	}{
+		{status: StatusServerUpdateProhibited, isProhibition: true},
+		{status: StatusTransferProhibited, isProhibition: true},
+		{status: StatusPendingDelete, isPending: true},
+		{status: StatusPendingRestore, isPending: true},
+		{status: StatusAutoRenewPeriod, isGracePeriod: true},
+		{status: StatusRedemptionPeriod, isGracePeriod: true},
+		{status: StatusClientHold, isHold: true},
+		{status: StatusServerHold, isHold: true},
+		{status: StatusActive},
+		{status: StatusLocked},
+	}
+
+	for i, item := range data {
+		if item.status.IsProhibition() != item.isProhibition ||
+			item.status.IsPending() != item.isPending ||
+			item.status.IsGracePeriod() != item.isGracePeriod ||
+			item.status.IsHold() != item.isHold {
+
+			t.Errorf("[%d] unexpected classification for status “%s”", i, item.status)
+		}
+	}
+}
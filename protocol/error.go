@@ -1,12 +1,14 @@
 package protocol
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 )
 
-// Error describes an Error Response Body as it is in RFC 7483, section 6
+// Error describes an Error Response Body as it is in RFC 9083, section 6
 type Error struct {
 	Notices     []Notice `json:"notices,omitempty"`
 	Lang        string   `json:"lang,omitempty"`
@@ -15,6 +17,72 @@ type Error struct {
 	Description []string `json:"description,omitempty"`
 	Conformance
 	Port43
+
+	// RetryAfter is the period informed in the Retry-After HTTP header, usually
+	// when the queries are rate limited (RFC 7480, section 5.5)
+	RetryAfter time.Duration `json:"-"`
+
+	// Extensions stores the members unknown to this library
+	Extensions map[string]json.RawMessage `json:"-"`
+}
+
+// NewError builds the error response for the HTTP status code, using the
+// status text as title
+func NewError(status int, description ...string) *Error {
+	return &Error{
+		ErrorCode:   status,
+		Title:       http.StatusText(status),
+		Description: description,
+	}
+}
+
+// NewBadRequestError builds the error response of a malformed query (RFC
+// 7480, section 5.4)
+func NewBadRequestError(description ...string) *Error {
+	return NewError(http.StatusBadRequest, description...)
+}
+
+// NewNotFoundError builds the error response of an object that doesn't exist
+// (RFC 7480, section 5.3)
+func NewNotFoundError(description ...string) *Error {
+	return NewError(http.StatusNotFound, description...)
+}
+
+// NewUnprocessableEntityError builds the error response of a well formed query
+// that uses an unsupported value, like an unknown sort property
+func NewUnprocessableEntityError(description ...string) *Error {
+	return NewError(http.StatusUnprocessableEntity, description...)
+}
+
+// NewTooManyRequestsError builds the error response of a rate limited query
+// (RFC 7480, section 5.5). When retryAfter is positive the server also sends
+// the Retry-After HTTP header
+func NewTooManyRequestsError(retryAfter time.Duration, description ...string) *Error {
+	err := NewError(http.StatusTooManyRequests, description...)
+	err.RetryAfter = retryAfter
+	return err
+}
+
+// NewNotImplementedError builds the error response of a query type that the
+// server doesn't support (RFC 7480, section 5.4)
+func NewNotImplementedError(description ...string) *Error {
+	return NewError(http.StatusNotImplemented, description...)
+}
+
+// errorJSON avoids the recursion of the Error JSON methods
+type errorJSON Error
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Members unknown to
+// the Error type are stored in the Extensions attribute
+func (e *Error) UnmarshalJSON(data []byte) error {
+	return unmarshalObject(data, (*errorJSON)(e), &e.Extensions)
+}
+
+// MarshalJSON implements the json.Marshaler interface. The members from the
+// Extensions attribute are written after the known members in alphabetical
+// order
+func (e Error) MarshalJSON() ([]byte, error) {
+	return marshalObject(errorJSON(e), e.Extensions)
 }
 
 // Error make it easy to transport the protocol error via Go error interface
@@ -26,3 +94,39 @@ func (e Error) Error() string {
 		e.Title,
 		strings.Join(e.Description, ", "))
 }
+
+// As allows errors.As to retrieve the error both as Error and *Error, no
+// matter how it was returned
+func (e Error) As(target any) bool {
+	switch t := target.(type) {
+	case **Error:
+		*t = &e
+		return true
+	case *Error:
+		*t = e
+		return true
+	}
+	return false
+}
+
+// GetHandle implements the Object interface. Error responses don't have a
+// handle
+func (e *Error) GetHandle() string { return "" }
+
+// GetLinks implements the Object interface. Error responses don't have links
+func (e *Error) GetLinks() []Link { return nil }
+
+// GetEvents implements the Object interface. Error responses don't have
+// events
+func (e *Error) GetEvents() []Event { return nil }
+
+// GetEntities implements the Object interface. Error responses don't have
+// entities
+func (e *Error) GetEntities() []Entity { return nil }
+
+// GetRemarks implements the Object interface. Error responses don't have
+// remarks
+func (e *Error) GetRemarks() []Remark { return nil }
+
+// GetNotices implements the Object interface
+func (e *Error) GetNotices() []Notice { return e.Notices }
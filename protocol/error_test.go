@@ -1,10 +1,13 @@
 package protocol
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestErrorError(t *testing.T) {
@@ -20,3 +23,94 @@ func TestErrorError(t *testing.T) {
 		t.Errorf("Unexpected error message. Expected “%s” and got “%s”", expected, err.Error())
 	}
 }
+
+func TestNewErrors(t *testing.T) {
+	data := []struct {
+		description string
+		err         *Error
+		expected    *Error
+	}{
+		{
+			description: "it should build a malformed query error",
+			err:         NewBadRequestError("invalid IP address"),
+			expected: &Error{
+				ErrorCode:   http.StatusBadRequest,
+				Title:       "Bad Request",
+				Description: []string{"invalid IP address"},
+			},
+		},
+		{
+			description: "it should build a not found error",
+			err:         NewNotFoundError(),
+			expected: &Error{
+				ErrorCode: http.StatusNotFound,
+				Title:     "Not Found",
+			},
+		},
+		{
+			description: "it should build an unsupported value error",
+			err:         NewUnprocessableEntityError("unknown sort property"),
+			expected: &Error{
+				ErrorCode:   http.StatusUnprocessableEntity,
+				Title:       "Unprocessable Entity",
+				Description: []string{"unknown sort property"},
+			},
+		},
+		{
+			description: "it should build a rate limit error",
+			err:         NewTooManyRequestsError(time.Minute),
+			expected: &Error{
+				ErrorCode:  http.StatusTooManyRequests,
+				Title:      "Too Many Requests",
+				RetryAfter: time.Minute,
+			},
+		},
+		{
+			description: "it should build a not implemented error",
+			err:         NewNotImplementedError(),
+			expected: &Error{
+				ErrorCode: http.StatusNotImplemented,
+				Title:     "Not Implemented",
+			},
+		},
+	}
+
+	for i, item := range data {
+		if !reflect.DeepEqual(item.expected, item.err) {
+			t.Errorf("[%d] %s: expected “%#v”, got “%#v”", i, item.description, item.expected, item.err)
+		}
+	}
+}
+
+func TestErrorAs(t *testing.T) {
+	data := []struct {
+		description string
+		err         error
+	}{
+		{
+			description: "it should retrieve an error returned as value",
+			err:         fmt.Errorf("query failed: %w", Error{ErrorCode: http.StatusNotFound}),
+		},
+		{
+			description: "it should retrieve an error returned as pointer",
+			err:         fmt.Errorf("query failed: %w", NewNotFoundError()),
+		},
+	}
+
+	for i, item := range data {
+		var pointer *Error
+		if !errors.As(item.err, &pointer) || pointer.ErrorCode != http.StatusNotFound {
+			t.Errorf("[%d] %s: not retrieved as *Error", i, item.description)
+		}
+
+		var value Error
+		if !errors.As(item.err, &value) || value.ErrorCode != http.StatusNotFound {
+			t.Errorf("[%d] %s: not retrieved as Error", i, item.description)
+		}
+	}
+
+	var pointer *Error
+	if errors.As(errors.New("other"), &pointer) {
+		t.Error("unexpected protocol error")
+	}
+}
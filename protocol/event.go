@@ -3,7 +3,7 @@ package protocol
 import "time"
 
 // EventAction can store all different types of event actions described in
-// RFC 7483, section 10.2.3
+// RFC 9083, section 10.2.3
 type EventAction string
 
 // https://tools.ietf.org/html/rfc7483#section-10.2.3
@@ -61,11 +61,12 @@ const (
 	EventLastCorrectDelegationSignCheck EventAction = "last correct delegation sign check"
 )
 
-// Event describes Events as it is in RFC 7483, section 4.5
+// Event describes Events as it is in RFC 9083, section 4.5
 type Event struct {
 	Action EventAction `json:"eventAction"`
 	Actor  string      `json:"eventActor,omitempty"`
 	Date   EventDate   `json:"eventDate"`
+	Links  []Link      `json:"links,omitempty"`
 
 	// Status was proposed by NIC.br to store the status of a current event.
 	// For NIC.br specific use was useful to store the status of a delegation
@@ -1,6 +1,7 @@
 package protocol
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"testing"
@@ -25,6 +26,47 @@ func TestNewEventDate(t *testing.T) {
 	}
 }
 
+func TestEventUnmarshalJSON(t *testing.T) {
+	// event members as described in RFC 9083, section 4.5
+	input := `{
+  "eventAction" : "last changed",
+  "eventActor" : "joe@example.com",
+  "eventDate" : "1991-12-31T23:59:59Z",
+  "links" :
+  [
+    {
+      "value" : "https://example.com/entity/XXXX",
+      "rel" : "related",
+      "href" : "https://example.com/history/XXXX",
+      "type" : "application/rdap+json"
+    }
+  ]
+}`
+
+	expected := Event{
+		Action: EventActionLastChanged,
+		Actor:  "joe@example.com",
+		Date:   Date(1991, 12, 31, 23, 59, 59, 0, time.UTC),
+		Links: []Link{
+			{
+				Value: "https://example.com/entity/XXXX",
+				Rel:   "related",
+				Href:  "https://example.com/history/XXXX",
+				Type:  "application/rdap+json",
+			},
+		},
+	}
+
+	var event Event
+	if err := json.Unmarshal([]byte(input), &event); err != nil {
+		t.Fatalf("Unexpected error “%s”", err)
+	}
+
+	if !reflect.DeepEqual(expected, event) {
+		t.Errorf("Unexpected event. Expected “%#v” and got “%#v”", expected, event)
+	}
+}
+
 func TestEventDateUnmarshalJSON(t *testing.T) {
 	data := []struct {
 		description   string
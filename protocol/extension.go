@@ -0,0 +1,194 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Extension describes a RDAP extension as it is in RFC 7480, section 6. The
+// extension members are identified by the prefix followed by an underscore,
+// and are decoded into the types informed for each object class
+type Extension struct {
+	// Identifier is the value listed in the rdapConformance member when the
+	// extension is used, like "nicbr_level_0"
+	Identifier string
+
+	// Prefix is the extension prefix used in the member names, like "nicbr"
+	Prefix string
+
+	// Members returns, for each object class name (ObjectClassDomain,
+	// ObjectClassEntity, ...), a new pointer to a structure where the
+	// extension members are decoded. The structure fields must be tagged with
+	// the member names
+	Members map[string]func() any
+}
+
+var (
+	extensionsLock sync.RWMutex
+	extensions     = make(map[string]Extension)
+)
+
+// RegisterExtension makes an extension available to the Extension and
+// SetExtension methods of the object classes. It is usually called from the
+// init function of the package that defines the extension types. If the
+// prefix is empty or already registered it panics
+func RegisterExtension(extension Extension) {
+	extensionsLock.Lock()
+	defer extensionsLock.Unlock()
+
+	if extension.Prefix == "" {
+		panic("protocol: RegisterExtension with an empty prefix")
+	}
+
+	if _, ok := extensions[extension.Prefix]; ok {
+		panic("protocol: RegisterExtension called twice for extension " + extension.Prefix)
+	}
+
+	extensions[extension.Prefix] = extension
+}
+
+// LookupExtension returns the registered extension with the given prefix
+func LookupExtension(prefix string) (Extension, bool) {
+	extensionsLock.RLock()
+	defer extensionsLock.RUnlock()
+
+	extension, ok := extensions[prefix]
+	return extension, ok
+}
+
+// RegisteredExtensions returns all registered extensions sorted by prefix
+func RegisteredExtensions() []Extension {
+	extensionsLock.RLock()
+	defer extensionsLock.RUnlock()
+
+	prefixes := slices.Sorted(maps.Keys(extensions))
+
+	list := make([]Extension, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		list = append(list, extensions[prefix])
+	}
+	return list
+}
+
+// decodeExtension decodes the members of the registered extension into the
+// type defined for the object class. If the extension doesn't define a type
+// for the object class or there's no extension member, nil is returned
+func decodeExtension(objectClassName, prefix string, members map[string]json.RawMessage) (any, error) {
+	extension, ok := LookupExtension(prefix)
+	if !ok {
+		return nil, fmt.Errorf("extension “%s” not registered", prefix)
+	}
+
+	newValue := extension.Members[objectClassName]
+	if newValue == nil {
+		return nil, nil
+	}
+
+	found := false
+	for name := range members {
+		if strings.HasPrefix(name, prefix+"_") {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	value := newValue()
+	if err := decodeMembers(members, prefix, value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// encodeExtension stores the value members in a copy of the members map. All
+// encoded members must use the extension prefix
+func encodeExtension(prefix string, members map[string]json.RawMessage, value any) (map[string]json.RawMessage, error) {
+	if _, ok := LookupExtension(prefix); !ok {
+		return nil, fmt.Errorf("extension “%s” not registered", prefix)
+	}
+
+	return encodeMembers(members, prefix, value)
+}
+
+// decodeMembers decodes the members that start with the prefix into value
+func decodeMembers(members map[string]json.RawMessage, prefix string, value any) error {
+	selected := make(map[string]json.RawMessage)
+	for name, member := range members {
+		if strings.HasPrefix(name, prefix+"_") {
+			selected[name] = member
+		}
+	}
+
+	if len(selected) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(selected)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, value)
+}
+
+// encodeMembers encodes value and returns a copy of members where the ones
+// declared by the value type are replaced. Members of the prefix that the
+// type doesn't declare are kept
+func encodeMembers(members map[string]json.RawMessage, prefix string, value any) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var encoded map[string]json.RawMessage
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, err
+	}
+
+	for name := range encoded {
+		if !strings.HasPrefix(name, prefix+"_") {
+			return nil, fmt.Errorf("member “%s” doesn't belong to extension “%s”", name, prefix)
+		}
+	}
+
+	t := reflect.TypeOf(value)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	known := knownMembers(t)
+
+	result := make(map[string]json.RawMessage)
+	for name, member := range members {
+		if _, ok := known[strings.ToLower(name)]; !ok {
+			result[name] = member
+		}
+	}
+	maps.Copy(result, encoded)
+
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+// overlayMembers works like encodeMembers, but keeps the members untouched
+// when they already decode to the same value. This avoids rewriting members
+// with data that the value type doesn't store
+func overlayMembers(members map[string]json.RawMessage, prefix string, value any) (map[string]json.RawMessage, error) {
+	current := reflect.New(reflect.TypeOf(value).Elem())
+	if err := decodeMembers(members, prefix, current.Interface()); err == nil &&
+		reflect.DeepEqual(current.Interface(), value) {
+		return members, nil
+	}
+
+	return encodeMembers(members, prefix, value)
+}
@@ -0,0 +1,268 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type fredDomain struct {
+	Keyset string `json:"fred_keyset,omitempty"`
+	NSSet  string `json:"fred_nsset,omitempty"`
+}
+
+func init() {
+	RegisterExtension(Extension{
+		Identifier: "fred",
+		Prefix:     "fred",
+		Members: map[string]func() any{
+			ObjectClassDomain: func() any { return &fredDomain{} },
+		},
+	})
+}
+
+func TestRegisterExtension(t *testing.T) {
+	data := []struct {
+		description   string
+		extension     Extension
+		expectedPanic any
+	}{
+		{
+			description:   "it should not allow an empty prefix",
+			extension:     Extension{Identifier: "empty"},
+			expectedPanic: "protocol: RegisterExtension with an empty prefix",
+		},
+		{
+			description:   "it should not allow the same prefix twice",
+			extension:     Extension{Identifier: "nicbr_level_1", Prefix: NICBRPrefix},
+			expectedPanic: "protocol: RegisterExtension called twice for extension nicbr",
+		},
+	}
+
+	for i, item := range data {
+		func() {
+			defer func() {
+				if r := recover(); !reflect.DeepEqual(item.expectedPanic, r) {
+					t.Errorf("[%d] %s: expected panic “%v” and got “%v”", i, item.description, item.expectedPanic, r)
+				}
+			}()
+
+			RegisterExtension(item.extension)
+		}()
+	}
+
+	extension, ok := LookupExtension(NICBRPrefix)
+	if !ok || extension.Identifier != NICBRIdentifier {
+		t.Errorf("Unexpected NIC.br extension registered “%#v”", extension)
+	}
+
+	var prefixes []string
+	for _, extension := range RegisteredExtensions() {
+		prefixes = append(prefixes, extension.Prefix)
+	}
+
+	if expected := []string{"cidr0", "fred", "nicbr"}; !reflect.DeepEqual(expected, prefixes) {
+		t.Errorf("Unexpected registered extensions. Expected “%v” and got “%v”", expected, prefixes)
+	}
+}
+
+func TestDomainExtension(t *testing.T) {
+	data := []struct {
+		description   string
+		data          string
+		prefix        string
+		expected      any
+		expectedError error
+	}{
+		{
+			description: "it should decode the extension members",
+			data:        `{"objectClassName":"domain","fred_keyset":"KEYSET-1","fred_nsset":"NSSET-1"}`,
+			prefix:      "fred",
+			expected: &fredDomain{
+				Keyset: "KEYSET-1",
+				NSSet:  "NSSET-1",
+			},
+		},
+		{
+			description: "it should decode the NIC.br extension members",
+			data:        `{"objectClassName":"domain","nicbr_arbitration":true}`,
+			prefix:      NICBRPrefix,
+			expected: &NICBRDomain{
+				Arbitration: true,
+			},
+		},
+		{
+			description: "it should return nil when there's no extension member",
+			data:        `{"objectClassName":"domain","nicbr_arbitration":true}`,
+			prefix:      "fred",
+		},
+		{
+			description:   "it should fail for an extension not registered",
+			data:          `{"objectClassName":"domain","arin_originas0_originautnums":[1]}`,
+			prefix:        "arin_originas0",
+			expectedError: fmt.Errorf("extension “arin_originas0” not registered"),
+		},
+		{
+			description:   "it should fail for invalid extension members",
+			data:          `{"objectClassName":"domain","fred_keyset":1}`,
+			prefix:        "fred",
+			expectedError: fmt.Errorf("json: cannot unmarshal number into Go struct field fredDomain.fred_keyset of type string"),
+		},
+	}
+
+	for i, item := range data {
+		var domain Domain
+		if err := json.Unmarshal([]byte(item.data), &domain); err != nil {
+			t.Fatalf("[%d] %s: unexpected error “%s”", i, item.description, err)
+		}
+
+		extension, err := domain.Extension(item.prefix)
+
+		if item.expectedError != nil {
+			if fmt.Sprintf("%v", item.expectedError) != fmt.Sprintf("%v", err) {
+				t.Errorf("[%d] %s: expected error “%s”, got “%s”", i, item.description, item.expectedError, err)
+			}
+
+		} else if err != nil {
+			t.Errorf("[%d] %s: unexpected error “%s”", i, item.description, err)
+
+		} else if !reflect.DeepEqual(item.expected, extension) {
+			t.Errorf("[%d] %s: unexpected extension. Expected “%#v” and got “%#v”", i, item.description, item.expected, extension)
+		}
+	}
+}
+
+func TestEntityExtensionUndefinedClass(t *testing.T) {
+	entity := Entity{
+		Extensions: map[string]json.RawMessage{
+			"fred_keyset": json.RawMessage(`"KEYSET-1"`),
+		},
+	}
+
+	extension, err := entity.Extension("fred")
+	if err != nil {
+		t.Fatalf("Unexpected error “%s”", err)
+	}
+
+	if extension != nil {
+		t.Errorf("Unexpected extension “%#v” for an object class without types", extension)
+	}
+}
+
+func TestDomainSetExtension(t *testing.T) {
+	data := []struct {
+		description   string
+		domain        Domain
+		prefix        string
+		value         any
+		expected      string
+		expectedError error
+	}{
+		{
+			description: "it should add the extension members",
+			domain: Domain{
+				ObjectClassName: "domain",
+			},
+			prefix:   "fred",
+			value:    &fredDomain{Keyset: "KEYSET-1"},
+			expected: `{"objectClassName":"domain","fred_keyset":"KEYSET-1"}`,
+		},
+		{
+			description: "it should replace only the members declared by the type",
+			domain: Domain{
+				ObjectClassName: "domain",
+				Extensions: map[string]json.RawMessage{
+					"fred_keyset":  json.RawMessage(`"KEYSET-1"`),
+					"fred_contact": json.RawMessage(`"CONTACT-1"`),
+				},
+			},
+			prefix:   "fred",
+			value:    fredDomain{NSSet: "NSSET-1"},
+			expected: `{"objectClassName":"domain","fred_contact":"CONTACT-1","fred_nsset":"NSSET-1"}`,
+		},
+		{
+			description: "it should update the deprecated NIC.br attributes",
+			domain: Domain{
+				ObjectClassName: "domain",
+			},
+			prefix:   NICBRPrefix,
+			value:    &NICBRDomain{Arbitration: true},
+			expected: `{"objectClassName":"domain","nicbr_arbitration":true}`,
+		},
+		{
+			description:   "it should fail for an extension not registered",
+			prefix:        "arin_originas0",
+			value:         &fredDomain{},
+			expectedError: fmt.Errorf("extension “arin_originas0” not registered"),
+		},
+		{
+			description:   "it should fail for members of other extensions",
+			prefix:        NICBRPrefix,
+			value:         &fredDomain{Keyset: "KEYSET-1"},
+			expectedError: fmt.Errorf("member “fred_keyset” doesn't belong to extension “nicbr”"),
+		},
+	}
+
+	for i, item := range data {
+		err := item.domain.SetExtension(item.prefix, item.value)
+
+		if item.expectedError != nil {
+			if fmt.Sprintf("%v", item.expectedError) != fmt.Sprintf("%v", err) {
+				t.Errorf("[%d] %s: expected error “%s”, got “%s”", i, item.description, item.expectedError, err)
+			}
+			continue
+
+		} else if err != nil {
+			t.Errorf("[%d] %s: unexpected error “%s”", i, item.description, err)
+			continue
+		}
+
+		output, err := json.Marshal(item.domain)
+		if err != nil {
+			t.Errorf("[%d] %s: unexpected error “%s”", i, item.description, err)
+
+		} else if string(output) != item.expected {
+			t.Errorf("[%d] %s: unexpected JSON. Expected “%s” and got “%s”", i, item.description, item.expected, string(output))
+		}
+
+		if nicbr, ok := item.value.(*NICBRDomain); ok && item.domain.Arbitration != nicbr.Arbitration {
+			t.Errorf("[%d] %s: deprecated attribute not updated", i, item.description)
+		}
+	}
+}
+
+func TestNICBRCompatibility(t *testing.T) {
+	input := `{"objectClassName":"ip network","handle":"200.160.0.0/20","startAddress":"200.160.0.0","endAddress":"200.160.15.255","ipVersion":"v4","type":"","country":"BR","status":null,"links":null,"events":null,"entities":null,"nicbr_autnum":22548,"nicbr_reverseDelegations":[{"startAddress":"200.160.0.0","endAddress":"200.160.0.255","nameservers":[{"objectClassName":"nameserver","ldhName":"a.dns.br","nicbr_unknown":1}],"events":null}]}`
+
+	var ipNetwork IPNetwork
+	if err := json.Unmarshal([]byte(input), &ipNetwork); err != nil {
+		t.Fatalf("Unexpected error “%s”", err)
+	}
+
+	if ipNetwork.Autnum != 22548 || len(ipNetwork.ReverseDelegations) != 1 {
+		t.Fatalf("Deprecated NIC.br attributes not filled: “%#v”", ipNetwork)
+	}
+
+	output, err := json.Marshal(ipNetwork)
+	if err != nil {
+		t.Fatalf("Unexpected error “%s”", err)
+	}
+
+	if string(output) != input {
+		t.Errorf("Unexpected JSON. Expected “%s” and got “%s”", input, string(output))
+	}
+
+	ipNetwork.Autnum = 263076
+	ipNetwork.ReverseDelegations = nil
+
+	output, err = json.Marshal(ipNetwork)
+	if err != nil {
+		t.Fatalf("Unexpected error “%s”", err)
+	}
+
+	expected := `{"objectClassName":"ip network","handle":"200.160.0.0/20","startAddress":"200.160.0.0","endAddress":"200.160.15.255","ipVersion":"v4","type":"","country":"BR","status":null,"links":null,"events":null,"entities":null,"nicbr_autnum":263076}`
+	if string(output) != expected {
+		t.Errorf("Unexpected JSON. Expected “%s” and got “%s”", expected, string(output))
+	}
+}
@@ -1,8 +1,54 @@
 package protocol
 
-// Help describes an answer to help queries as it is in RFC 7483, section 7
+import "encoding/json"
+
+// Help describes an answer to help queries as it is in RFC 9083, section 7
 type Help struct {
 	Notices []Notice `json:"notices,omitempty"`
 	Lang    string   `json:"lang,omitempty"`
+
+	// SubsettingMetadata lists the field sets supported by the server (RFC
+	// 8982, section 3)
+	SubsettingMetadata *SubsettingMetadata `json:"subsetting_metadata,omitempty"`
 	Conformance
+
+	// Extensions stores the members unknown to this library
+	Extensions map[string]json.RawMessage `json:"-"`
+}
+
+// helpJSON avoids the recursion of the Help JSON methods
+type helpJSON Help
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Members unknown to
+// the Help type are stored in the Extensions attribute
+func (h *Help) UnmarshalJSON(data []byte) error {
+	return unmarshalObject(data, (*helpJSON)(h), &h.Extensions)
+}
+
+// MarshalJSON implements the json.Marshaler interface. The members from the
+// Extensions attribute are written after the known members in alphabetical
+// order
+func (h Help) MarshalJSON() ([]byte, error) {
+	return marshalObject(helpJSON(h), h.Extensions)
 }
+
+// GetHandle implements the Object interface. Help responses don't have a
+// handle
+func (h *Help) GetHandle() string { return "" }
+
+// GetLinks implements the Object interface. Help responses don't have links
+func (h *Help) GetLinks() []Link { return nil }
+
+// GetEvents implements the Object interface. Help responses don't have events
+func (h *Help) GetEvents() []Event { return nil }
+
+// GetEntities implements the Object interface. Help responses don't have
+// entities
+func (h *Help) GetEntities() []Entity { return nil }
+
+// GetRemarks implements the Object interface. Help responses don't have
+// remarks
+func (h *Help) GetRemarks() []Remark { return nil }
+
+// GetNotices implements the Object interface
+func (h *Help) GetNotices() []Notice { return h.Notices }
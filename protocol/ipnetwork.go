@@ -1,29 +1,102 @@
 package protocol
 
-// IPNetwork describes the IP Network Object Class as it is in RFC 7483,
+import "encoding/json"
+
+// IPNetwork describes the IP Network Object Class as it is in RFC 9083,
 // section 5.4
 type IPNetwork struct {
-	ObjectClassName    string              `json:"objectClassName"`
-	Handle             string              `json:"handle"`
-	StartAddress       string              `json:"startAddress"`
-	EndAddress         string              `json:"endAddress"`
-	IPVersion          string              `json:"ipVersion"`
-	Name               string              `json:"name,omitempty"`
-	Type               string              `json:"type"`
-	Country            string              `json:"country"`
-	ParentHandle       string              `json:"parentHandle,omitempty"`
-	Status             []string            `json:"status"`
-	Autnum             uint32              `json:"nicbr_autnum,omitempty"`
-	Links              []Link              `json:"links"`
-	Events             []Event             `json:"events"`
-	Entities           []Entity            `json:"entities"`
-	Notices            []Notice            `json:"notices,omitempty"`
-	Remarks            []Remark            `json:"remarks,omitempty"`
-	ReverseDelegations []ReverseDelegation `json:"nicbr_reverseDelegations,omitempty"`
+	ObjectClassName string   `json:"objectClassName"`
+	Handle          string   `json:"handle"`
+	StartAddress    string   `json:"startAddress"`
+	EndAddress      string   `json:"endAddress"`
+	IPVersion       string   `json:"ipVersion"`
+	Name            string   `json:"name,omitempty"`
+	Type            string   `json:"type"`
+	Country         string   `json:"country"`
+	ParentHandle    string   `json:"parentHandle,omitempty"`
+	Status          []Status `json:"status"`
+	Links           []Link   `json:"links"`
+	Events          []Event  `json:"events"`
+	Entities        []Entity `json:"entities"`
+	Notices         []Notice `json:"notices,omitempty"`
+	Remarks         []Remark `json:"remarks,omitempty"`
 	Conformance
 	Port43
+
+	// Autnum and ReverseDelegations are NIC.br extension members.
+	//
+	// Deprecated: use the NICBRIPNetwork type returned by
+	// Extension(NICBRPrefix)
+	Autnum             uint32              `json:"-"`
+	ReverseDelegations []ReverseDelegation `json:"-"`
+
+	// Extensions stores the members unknown to this library
+	Extensions map[string]json.RawMessage `json:"-"`
 }
 
+// ipNetworkJSON avoids the recursion of the IPNetwork JSON methods
+type ipNetworkJSON IPNetwork
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Members unknown to
+// the IPNetwork type are stored in the Extensions attribute
+func (i *IPNetwork) UnmarshalJSON(data []byte) error {
+	if err := unmarshalObject(data, (*ipNetworkJSON)(i), &i.Extensions); err != nil {
+		return err
+	}
+
+	return i.loadNICBR()
+}
+
+// MarshalJSON implements the json.Marshaler interface. The members from the
+// Extensions attribute are written after the known members in alphabetical
+// order
+func (i IPNetwork) MarshalJSON() ([]byte, error) {
+	extensions, err := i.storeNICBR()
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalObject(ipNetworkJSON(i), extensions)
+}
+
+// Extension decodes the members of the registered extension identified by the
+// prefix into the type that the extension defines for the IP network object
+// class. If the object doesn't have any member of the extension, nil is
+// returned
+func (i *IPNetwork) Extension(prefix string) (any, error) {
+	return decodeExtension(ObjectClassIPNetwork, prefix, i.Extensions)
+}
+
+// SetExtension replaces the members of the registered extension identified by
+// the prefix with the ones encoded from value
+func (i *IPNetwork) SetExtension(prefix string, value any) error {
+	extensions, err := encodeExtension(prefix, i.Extensions, value)
+	if err != nil {
+		return err
+	}
+
+	i.Extensions = extensions
+	return i.loadNICBR()
+}
+
+// GetHandle implements the Object interface
+func (i *IPNetwork) GetHandle() string { return i.Handle }
+
+// GetLinks implements the Object interface
+func (i *IPNetwork) GetLinks() []Link { return i.Links }
+
+// GetEvents implements the Object interface
+func (i *IPNetwork) GetEvents() []Event { return i.Events }
+
+// GetEntities implements the Object interface
+func (i *IPNetwork) GetEntities() []Entity { return i.Entities }
+
+// GetRemarks implements the Object interface
+func (i *IPNetwork) GetRemarks() []Remark { return i.Remarks }
+
+// GetNotices implements the Object interface
+func (i *IPNetwork) GetNotices() []Notice { return i.Notices }
+
 type ReverseDS struct {
 	Zone       string  `json:"zone"`
 	KeyTag     int     `json:"keyTag"`
@@ -36,7 +109,9 @@ type ReverseDS struct {
 type ReverseDelegationSecureDNS struct {
 	ZoneSigned       *bool       `json:"zoneSigned,omitempty"`
 	DelegationSigned bool        `json:"delegationSigned"`
+	MaxSigLife       int         `json:"maxSigLife,omitempty"`
 	DSSet            []ReverseDS `json:"dsData,omitempty"`
+	KeyData          []KeyData   `json:"keyData,omitempty"`
 }
 
 // ReverseDelegation is a NIC.br extension to list all the IP network
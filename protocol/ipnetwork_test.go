@@ -0,0 +1,89 @@
+package protocol
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIPNetworkUnmarshalJSON(t *testing.T) {
+	// example from RFC 9083, section 5.4 (without remarks and entities to keep
+	// it short)
+	input := `{
+  "objectClassName" : "ip network",
+  "handle" : "XXXX-RIR",
+  "startAddress" : "2001:db8::",
+  "endAddress" : "2001:db8:0:ffff:ffff:ffff:ffff:ffff",
+  "ipVersion" : "v6",
+  "name": "NET-RTR-1",
+  "type" : "DIRECT ALLOCATION",
+  "country" : "AU",
+  "parentHandle" : "YYYY-RIR",
+  "status" : [ "active" ],
+  "links" :
+  [
+    {
+      "value" : "https://example.net/ip/2001:db8::/48",
+      "rel" : "self",
+      "href" : "https://example.net/ip/2001:db8::/48",
+      "type" : "application/rdap+json"
+    },
+    {
+      "value" : "https://example.net/ip/2001:db8::/48",
+      "rel" : "up",
+      "href" : "https://example.net/ip/2001:db8::/32",
+      "type" : "application/rdap+json"
+    }
+  ],
+  "events" :
+  [
+    {
+      "eventAction" : "registration",
+      "eventDate" : "1990-12-31T23:59:59Z"
+    }
+  ]
+}`
+
+	expected := IPNetwork{
+		ObjectClassName: "ip network",
+		Handle:          "XXXX-RIR",
+		StartAddress:    "2001:db8::",
+		EndAddress:      "2001:db8:0:ffff:ffff:ffff:ffff:ffff",
+		IPVersion:       "v6",
+		Name:            "NET-RTR-1",
+		Type:            "DIRECT ALLOCATION",
+		Country:         "AU",
+		ParentHandle:    "YYYY-RIR",
+		Status:          []Status{StatusActive},
+		Links: []Link{
+			{
+				Value: "https://example.net/ip/2001:db8::/48",
+				Rel:   "self",
+				Href:  "https://example.net/ip/2001:db8::/48",
+				Type:  "application/rdap+json",
+			},
+			{
+				Value: "https://example.net/ip/2001:db8::/48",
+				Rel:   "up",
+				Href:  "https://example.net/ip/2001:db8::/32",
+				Type:  "application/rdap+json",
+			},
+		},
+		Events: []Event{
+			{
+				Action: EventActionRegistration,
+				Date:   Date(1990, 12, 31, 23, 59, 59, 0, time.UTC),
+			},
+		},
+	}
+
+	var ipNetwork IPNetwork
+	if err := json.Unmarshal([]byte(input), &ipNetwork); err != nil {
+		t.Fatalf("Unexpected error “%s”", err)
+	}
+
+	if !reflect.DeepEqual(expected, ipNetwork) {
+		t.Errorf("Unexpected IP network. Expected “%#v” and got “%#v”", expected, ipNetwork)
+	}
+}
@@ -0,0 +1,228 @@
+package protocol
+
+import (
+	"fmt"
+	"math/big"
+	"net/netip"
+)
+
+// StartAddr parses the first address of the IP network
+func (i *IPNetwork) StartAddr() (netip.Addr, error) {
+	return netip.ParseAddr(i.StartAddress)
+}
+
+// EndAddr parses the last address of the IP network
+func (i *IPNetwork) EndAddr() (netip.Addr, error) {
+	return netip.ParseAddr(i.EndAddress)
+}
+
+// Prefixes returns the minimal list of prefixes that covers exactly the range
+// from StartAddress to EndAddress
+func (i *IPNetwork) Prefixes() ([]netip.Prefix, error) {
+	start, end, err := parseRange(i.StartAddress, i.EndAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return rangePrefixes(start, end), nil
+}
+
+// ParentPrefix returns the prefix used to query the parent network. When the
+// parent handle is a CIDR prefix it is used, otherwise the prefix is the
+// smallest one that covers the whole range with at least one bit less than
+// the network, as the server answers with the most specific network. It
+// returns false when the range is invalid or is already the whole address
+// space
+func (i *IPNetwork) ParentPrefix() (netip.Prefix, bool) {
+	if prefix, err := netip.ParsePrefix(i.ParentHandle); err == nil {
+		return prefix.Masked(), true
+	}
+
+	prefixes, err := i.Prefixes()
+	if err != nil {
+		return netip.Prefix{}, false
+	}
+
+	prefix := prefixes[0]
+	if len(prefixes) == 1 {
+		if prefix.Bits() == 0 {
+			return netip.Prefix{}, false
+		}
+		return netip.PrefixFrom(prefix.Addr(), prefix.Bits()-1).Masked(), true
+	}
+
+	last := prefixes[len(prefixes)-1].Addr()
+	for !prefix.Contains(last) {
+		prefix = netip.PrefixFrom(prefix.Addr(), prefix.Bits()-1).Masked()
+	}
+	return prefix, true
+}
+
+// Contains checks if the address belongs to the IP network range. If the
+// range is invalid it returns false
+func (i *IPNetwork) Contains(addr netip.Addr) bool {
+	return rangeContains(i.StartAddress, i.EndAddress, addr)
+}
+
+// Size returns the number of addresses in the IP network range
+func (i *IPNetwork) Size() (*big.Int, error) {
+	start, end, err := parseRange(i.StartAddress, i.EndAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return rangeSize(start, end), nil
+}
+
+// Validate checks if the addresses of the IP network range are valid, from
+// the same IP version informed in IPVersion ("v4" or "v6"), and if the start
+// address is lower or equal than the end address
+func (i *IPNetwork) Validate() error {
+	start, _, err := parseRange(i.StartAddress, i.EndAddress)
+	if err != nil {
+		return err
+	}
+
+	var version string
+	if start.Is4() {
+		version = "v4"
+	} else {
+		version = "v6"
+	}
+
+	if i.IPVersion != version {
+		return fmt.Errorf("IP version “%s” doesn't match the %s range %s - %s",
+			i.IPVersion, version, i.StartAddress, i.EndAddress)
+	}
+
+	return nil
+}
+
+// StartAddr parses the first address of the reverse delegation
+func (r *ReverseDelegation) StartAddr() (netip.Addr, error) {
+	return netip.ParseAddr(r.StartAddress)
+}
+
+// EndAddr parses the last address of the reverse delegation
+func (r *ReverseDelegation) EndAddr() (netip.Addr, error) {
+	return netip.ParseAddr(r.EndAddress)
+}
+
+// Prefixes returns the minimal list of prefixes that covers exactly the range
+// from StartAddress to EndAddress
+func (r *ReverseDelegation) Prefixes() ([]netip.Prefix, error) {
+	start, end, err := parseRange(r.StartAddress, r.EndAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return rangePrefixes(start, end), nil
+}
+
+// Contains checks if the address belongs to the reverse delegation range. If
+// the range is invalid it returns false
+func (r *ReverseDelegation) Contains(addr netip.Addr) bool {
+	return rangeContains(r.StartAddress, r.EndAddress, addr)
+}
+
+// Size returns the number of addresses in the reverse delegation range
+func (r *ReverseDelegation) Size() (*big.Int, error) {
+	start, end, err := parseRange(r.StartAddress, r.EndAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return rangeSize(start, end), nil
+}
+
+// Validate checks if the addresses of the reverse delegation range are valid,
+// from the same IP version, and if the start address is lower or equal than
+// the end address
+func (r *ReverseDelegation) Validate() error {
+	_, _, err := parseRange(r.StartAddress, r.EndAddress)
+	return err
+}
+
+func parseRange(startAddress, endAddress string) (start, end netip.Addr, err error) {
+	if start, err = netip.ParseAddr(startAddress); err != nil {
+		return
+	}
+
+	if end, err = netip.ParseAddr(endAddress); err != nil {
+		return
+	}
+
+	if start.Zone() != "" || end.Zone() != "" {
+		err = fmt.Errorf("unexpected IPv6 zone in range %s - %s", startAddress, endAddress)
+
+	} else if start.BitLen() != end.BitLen() {
+		err = fmt.Errorf("mixed IP versions in range %s - %s", startAddress, endAddress)
+
+	} else if end.Less(start) {
+		err = fmt.Errorf("start address is greater than end address in range %s - %s", startAddress, endAddress)
+	}
+
+	return
+}
+
+func rangeContains(startAddress, endAddress string, addr netip.Addr) bool {
+	start, end, err := parseRange(startAddress, endAddress)
+	if err != nil || addr.BitLen() != start.BitLen() {
+		return false
+	}
+
+	return start.Compare(addr) <= 0 && addr.Compare(end) <= 0
+}
+
+// rangePrefixes splits the range in the largest aligned blocks, from the
+// start to the end address
+func rangePrefixes(start, end netip.Addr) []netip.Prefix {
+	var prefixes []netip.Prefix
+
+	for {
+		var prefix netip.Prefix
+		for bits := 0; bits <= start.BitLen(); bits++ {
+			prefix = netip.PrefixFrom(start, bits)
+			if prefix.Masked().Addr() == start && LastAddr(prefix).Compare(end) <= 0 {
+				break
+			}
+		}
+		prefixes = append(prefixes, prefix)
+
+		last := LastAddr(prefix)
+		if last == end {
+			break
+		}
+		start = last.Next()
+	}
+
+	return prefixes
+}
+
+// LastAddr returns the last address of the prefix, setting all host bits. For
+// an invalid prefix the zero address is returned
+func LastAddr(prefix netip.Prefix) netip.Addr {
+	if !prefix.IsValid() {
+		return netip.Addr{}
+	}
+
+	addr := prefix.Addr().As16()
+	offset := 128 - prefix.Addr().BitLen()
+
+	for i := offset + prefix.Bits(); i < 128; i++ {
+		addr[i/8] |= 1 << (7 - i%8)
+	}
+
+	if prefix.Addr().Is4() {
+		return netip.AddrFrom4([4]byte(addr[12:]))
+	}
+	return netip.AddrFrom16(addr)
+}
+
+func rangeSize(start, end netip.Addr) *big.Int {
+	startBytes, endBytes := start.As16(), end.As16()
+
+	size := new(big.Int).SetBytes(endBytes[:])
+	size.Sub(size, new(big.Int).SetBytes(startBytes[:]))
+	return size.Add(size, big.NewInt(1))
+}
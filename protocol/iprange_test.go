@@ -0,0 +1,388 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+func TestIPNetworkPrefixes(t *testing.T) {
+	data := []struct {
+		description   string
+		ipNetwork     IPNetwork
+		expected      []netip.Prefix
+		expectedSize  *big.Int
+		expectedError error
+	}{
+		{
+			description: "it should return a single IPv4 prefix",
+			ipNetwork: IPNetwork{
+				StartAddress: "200.160.0.0",
+				EndAddress:   "200.160.15.255",
+			},
+			expected: []netip.Prefix{
+				netip.MustParsePrefix("200.160.0.0/20"),
+			},
+			expectedSize: big.NewInt(4096),
+		},
+		{
+			description: "it should split an unaligned IPv4 range",
+			ipNetwork: IPNetwork{
+				StartAddress: "192.0.2.1",
+				EndAddress:   "192.0.2.130",
+			},
+			expected: []netip.Prefix{
+				netip.MustParsePrefix("192.0.2.1/32"),
+				netip.MustParsePrefix("192.0.2.2/31"),
+				netip.MustParsePrefix("192.0.2.4/30"),
+				netip.MustParsePrefix("192.0.2.8/29"),
+				netip.MustParsePrefix("192.0.2.16/28"),
+				netip.MustParsePrefix("192.0.2.32/27"),
+				netip.MustParsePrefix("192.0.2.64/26"),
+				netip.MustParsePrefix("192.0.2.128/31"),
+				netip.MustParsePrefix("192.0.2.130/32"),
+			},
+			expectedSize: big.NewInt(130),
+		},
+		{
+			description: "it should cover the whole IPv4 space",
+			ipNetwork: IPNetwork{
+				StartAddress: "0.0.0.0",
+				EndAddress:   "255.255.255.255",
+			},
+			expected: []netip.Prefix{
+				netip.MustParsePrefix("0.0.0.0/0"),
+			},
+			expectedSize: big.NewInt(1 << 32),
+		},
+		{
+			description: "it should return IPv6 prefixes",
+			ipNetwork: IPNetwork{
+				StartAddress: "2001:db8::",
+				EndAddress:   "2001:db9:ffff:ffff:ffff:ffff:ffff:ffff",
+			},
+			expected: []netip.Prefix{
+				netip.MustParsePrefix("2001:db8::/31"),
+			},
+			expectedSize: new(big.Int).Lsh(big.NewInt(1), 97),
+		},
+		{
+			description: "it should fail for an invalid start address",
+			ipNetwork: IPNetwork{
+				StartAddress: "200.160.0",
+				EndAddress:   "200.160.15.255",
+			},
+			expectedError: fmt.Errorf(`ParseAddr("200.160.0"): IPv4 address too short`),
+		},
+		{
+			description: "it should fail for an invalid end address",
+			ipNetwork: IPNetwork{
+				StartAddress: "200.160.0.0",
+				EndAddress:   "",
+			},
+			expectedError: fmt.Errorf(`ParseAddr(""): unable to parse IP`),
+		},
+		{
+			description: "it should fail for mixed IP versions",
+			ipNetwork: IPNetwork{
+				StartAddress: "200.160.0.0",
+				EndAddress:   "2001:db8::",
+			},
+			expectedError: fmt.Errorf("mixed IP versions in range 200.160.0.0 - 2001:db8::"),
+		},
+		{
+			description: "it should fail for an inverted range",
+			ipNetwork: IPNetwork{
+				StartAddress: "200.160.15.255",
+				EndAddress:   "200.160.0.0",
+			},
+			expectedError: fmt.Errorf("start address is greater than end address in range 200.160.15.255 - 200.160.0.0"),
+		},
+		{
+			description: "it should fail for addresses with zone",
+			ipNetwork: IPNetwork{
+				StartAddress: "fe80::%eth0",
+				EndAddress:   "fe80::ffff%eth0",
+			},
+			expectedError: errors.New("unexpected IPv6 zone in range fe80::%eth0 - fe80::ffff%eth0"),
+		},
+	}
+
+	for i, item := range data {
+		prefixes, err := item.ipNetwork.Prefixes()
+
+		if item.expectedError != nil {
+			if fmt.Sprintf("%v", item.expectedError) != fmt.Sprintf("%v", err) {
+				t.Errorf("[%d] %s: expected error “%s”, got “%s”", i, item.description, item.expectedError, err)
+			}
+			continue
+
+		} else if err != nil {
+			t.Errorf("[%d] %s: unexpected error “%s”", i, item.description, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(item.expected, prefixes) {
+			t.Errorf("[%d] %s: unexpected prefixes. Expected “%v” and got “%v”", i, item.description, item.expected, prefixes)
+		}
+
+		size, err := item.ipNetwork.Size()
+		if err != nil {
+			t.Errorf("[%d] %s: unexpected error “%s”", i, item.description, err)
+
+		} else if size.Cmp(item.expectedSize) != 0 {
+			t.Errorf("[%d] %s: unexpected size. Expected “%s” and got “%s”", i, item.description, item.expectedSize, size)
+		}
+	}
+}
+
+func TestIPNetworkContains(t *testing.T) {
+	ipNetwork := IPNetwork{
+		StartAddress: "200.160.0.0",
+		EndAddress:   "200.160.15.255",
+	}
+
+	data := []struct {
+		description string
+		addr        netip.Addr
+		expected    bool
+	}{
+		{
+			description: "it should contain the first address",
+			addr:        netip.MustParseAddr("200.160.0.0"),
+			expected:    true,
+		},
+		{
+			description: "it should contain the last address",
+			addr:        netip.MustParseAddr("200.160.15.255"),
+			expected:    true,
+		},
+		{
+			description: "it should not contain an address after the range",
+			addr:        netip.MustParseAddr("200.160.16.0"),
+		},
+		{
+			description: "it should not contain an address from other IP version",
+			addr:        netip.MustParseAddr("::ffff:200.160.2.3"),
+		},
+		{
+			description: "it should not contain an invalid address",
+			addr:        netip.Addr{},
+		},
+	}
+
+	for i, item := range data {
+		if contains := ipNetwork.Contains(item.addr); contains != item.expected {
+			t.Errorf("[%d] %s: expected “%t” and got “%t”", i, item.description, item.expected, contains)
+		}
+	}
+}
+
+func TestIPNetworkParentPrefix(t *testing.T) {
+	data := []struct {
+		description string
+		ipNetwork   IPNetwork
+		expected    netip.Prefix
+		expectedOK  bool
+	}{
+		{
+			description: "it should remove one bit of a single prefix",
+			ipNetwork: IPNetwork{
+				StartAddress: "192.0.2.0",
+				EndAddress:   "192.0.2.255",
+			},
+			expected:   netip.MustParsePrefix("192.0.2.0/23"),
+			expectedOK: true,
+		},
+		{
+			description: "it should cover an unaligned range",
+			ipNetwork: IPNetwork{
+				StartAddress: "192.0.2.1",
+				EndAddress:   "192.0.2.130",
+			},
+			expected:   netip.MustParsePrefix("192.0.2.0/24"),
+			expectedOK: true,
+		},
+		{
+			description: "it should remove one bit of an IPv6 prefix",
+			ipNetwork: IPNetwork{
+				StartAddress: "2001:db8::",
+				EndAddress:   "2001:db8:1:ffff:ffff:ffff:ffff:ffff",
+			},
+			expected:   netip.MustParsePrefix("2001:db8::/46"),
+			expectedOK: true,
+		},
+		{
+			description: "it should use a CIDR parent handle",
+			ipNetwork: IPNetwork{
+				StartAddress: "192.0.2.0",
+				EndAddress:   "192.0.2.255",
+				ParentHandle: "192.0.0.1/16",
+			},
+			expected:   netip.MustParsePrefix("192.0.0.0/16"),
+			expectedOK: true,
+		},
+		{
+			description: "it should ignore a parent handle that isn't a CIDR prefix",
+			ipNetwork: IPNetwork{
+				StartAddress: "192.0.2.0",
+				EndAddress:   "192.0.2.255",
+				ParentHandle: "NET-192-0-0-0-1",
+			},
+			expected:   netip.MustParsePrefix("192.0.2.0/23"),
+			expectedOK: true,
+		},
+		{
+			description: "it should not have a parent for the whole address space",
+			ipNetwork: IPNetwork{
+				StartAddress: "0.0.0.0",
+				EndAddress:   "255.255.255.255",
+			},
+		},
+		{
+			description: "it should not have a parent for an invalid range",
+			ipNetwork: IPNetwork{
+				StartAddress: "192.0.2.255",
+				EndAddress:   "192.0.2.0",
+			},
+		},
+	}
+
+	for i, item := range data {
+		prefix, ok := item.ipNetwork.ParentPrefix()
+		if prefix != item.expected || ok != item.expectedOK {
+			t.Errorf("[%d] %s: expected “%s” (%t) and got “%s” (%t)",
+				i, item.description, item.expected, item.expectedOK, prefix, ok)
+		}
+	}
+}
+
+func TestLastAddr(t *testing.T) {
+	data := []struct {
+		description string
+		prefix      netip.Prefix
+		expected    netip.Addr
+	}{
+		{
+			description: "it should set the host bits of an IPv4 prefix",
+			prefix:      netip.MustParsePrefix("192.0.2.0/23"),
+			expected:    netip.MustParseAddr("192.0.3.255"),
+		},
+		{
+			description: "it should set the host bits of an unmasked IPv6 prefix",
+			prefix:      netip.MustParsePrefix("2001:db8::1/64"),
+			expected:    netip.MustParseAddr("2001:db8::ffff:ffff:ffff:ffff"),
+		},
+		{
+			description: "it should keep the address of a host prefix",
+			prefix:      netip.MustParsePrefix("192.0.2.1/32"),
+			expected:    netip.MustParseAddr("192.0.2.1"),
+		},
+		{
+			description: "it should not have a last address for an invalid prefix",
+		},
+	}
+
+	for i, item := range data {
+		if last := LastAddr(item.prefix); last != item.expected {
+			t.Errorf("[%d] %s: expected “%s” and got “%s”", i, item.description, item.expected, last)
+		}
+	}
+}
+
+func TestIPNetworkValidate(t *testing.T) {
+	data := []struct {
+		description   string
+		ipNetwork     IPNetwork
+		expectedError error
+	}{
+		{
+			description: "it should accept a valid IPv4 network",
+			ipNetwork: IPNetwork{
+				StartAddress: "200.160.0.0",
+				EndAddress:   "200.160.15.255",
+				IPVersion:    "v4",
+			},
+		},
+		{
+			description: "it should accept a valid IPv6 network",
+			ipNetwork: IPNetwork{
+				StartAddress: "2001:db8::",
+				EndAddress:   "2001:db8::ffff",
+				IPVersion:    "v6",
+			},
+		},
+		{
+			description: "it should detect a wrong IP version",
+			ipNetwork: IPNetwork{
+				StartAddress: "2001:db8::",
+				EndAddress:   "2001:db8::ffff",
+				IPVersion:    "v4",
+			},
+			expectedError: fmt.Errorf("IP version “v4” doesn't match the v6 range 2001:db8:: - 2001:db8::ffff"),
+		},
+		{
+			description: "it should detect an invalid range",
+			ipNetwork: IPNetwork{
+				StartAddress: "200.160.0.0",
+				EndAddress:   "2001:db8::ffff",
+				IPVersion:    "v4",
+			},
+			expectedError: fmt.Errorf("mixed IP versions in range 200.160.0.0 - 2001:db8::ffff"),
+		},
+	}
+
+	for i, item := range data {
+		err := item.ipNetwork.Validate()
+		if fmt.Sprintf("%v", item.expectedError) != fmt.Sprintf("%v", err) {
+			t.Errorf("[%d] %s: expected error “%v”, got “%v”", i, item.description, item.expectedError, err)
+		}
+	}
+}
+
+func TestReverseDelegationRange(t *testing.T) {
+	reverseDelegation := ReverseDelegation{
+		StartAddress: "200.160.0.0",
+		EndAddress:   "200.160.1.255",
+	}
+
+	if err := reverseDelegation.Validate(); err != nil {
+		t.Fatalf("Unexpected error “%s”", err)
+	}
+
+	prefixes, err := reverseDelegation.Prefixes()
+	if err != nil {
+		t.Fatalf("Unexpected error “%s”", err)
+	}
+
+	expected := []netip.Prefix{netip.MustParsePrefix("200.160.0.0/23")}
+	if !reflect.DeepEqual(expected, prefixes) {
+		t.Errorf("Unexpected prefixes. Expected “%v” and got “%v”", expected, prefixes)
+	}
+
+	size, err := reverseDelegation.Size()
+	if err != nil {
+		t.Fatalf("Unexpected error “%s”", err)
+	}
+
+	if size.Int64() != 512 {
+		t.Errorf("Unexpected size “%s”", size)
+	}
+
+	if !reverseDelegation.Contains(netip.MustParseAddr("200.160.1.1")) {
+		t.Error("Address not found in the reverse delegation")
+	}
+
+	start, err := reverseDelegation.StartAddr()
+	if err != nil || start != netip.MustParseAddr("200.160.0.0") {
+		t.Errorf("Unexpected start address “%s” (%v)", start, err)
+	}
+
+	end, err := reverseDelegation.EndAddr()
+	if err != nil || end != netip.MustParseAddr("200.160.1.255") {
+		t.Errorf("Unexpected end address “%s” (%v)", end, err)
+	}
+}
@@ -0,0 +1,147 @@
+package protocol
+
+import (
+	"fmt"
+	"time"
+)
+
+// List of domain lifecycle phases, from the most restrictive to the least
+const (
+	// LifecyclePhaseFrozen the domain can't be published in DNS because of a
+	// court order or a decision of the "Comitê Gestor da Internet no Brasil"
+	LifecyclePhaseFrozen LifecyclePhase = "frozen"
+
+	// LifecyclePhaseRedemption the domain was deleted but can still be
+	// restored (RFC 3915)
+	LifecyclePhaseRedemption LifecyclePhase = "redemption"
+
+	// LifecyclePhasePendingDelete the domain will be purged from the registry
+	LifecyclePhasePendingDelete LifecyclePhase = "pending delete"
+
+	// LifecyclePhaseOnHold the client or the server requested that the DNS
+	// delegation information must not be published
+	LifecyclePhaseOnHold LifecyclePhase = "on hold"
+
+	// LifecyclePhaseWaitingActivation the domain will be published in DNS in
+	// the next publication cycle
+	LifecyclePhaseWaitingActivation LifecyclePhase = "waiting activation"
+
+	// LifecyclePhaseWaitingInactivation the domain will be removed from DNS in
+	// the next publication cycle
+	LifecyclePhaseWaitingInactivation LifecyclePhase = "waiting inactivation"
+
+	// LifecyclePhaseInactive the domain isn't in use
+	LifecyclePhaseInactive LifecyclePhase = "inactive"
+
+	// LifecyclePhaseActive the domain is published in DNS
+	LifecyclePhaseActive LifecyclePhase = "active"
+)
+
+// LifecyclePhase stores the main state of a domain in the registry
+type LifecyclePhase string
+
+// lifecyclePhases lists the status that determine each phase, in the order
+// that they are checked
+var lifecyclePhases = []struct {
+	phase  LifecyclePhase
+	status []Status
+}{
+	{LifecyclePhaseFrozen, []Status{StatusInactiveCourtOrder, StatusInactiveCG}},
+	{LifecyclePhaseRedemption, []Status{StatusRedemptionPeriod, StatusPendingRestore}},
+	{LifecyclePhasePendingDelete, []Status{StatusPendingDelete}},
+	{LifecyclePhaseOnHold, []Status{StatusServerHold, StatusClientHold}},
+	{LifecyclePhaseWaitingActivation, []Status{StatusWaitingActivation}},
+	{LifecyclePhaseWaitingInactivation, []Status{StatusWaitingInactivation}},
+	{LifecyclePhaseInactive, []Status{StatusInactive}},
+}
+
+// LifecycleState stores the result of the domain lifecycle analysis
+type LifecycleState struct {
+	// Phase is the most restrictive phase found in the domain status
+	Phase LifecyclePhase
+
+	// Expiration is the date of the latest expiration event. It is zero when
+	// the domain doesn't have an expiration event
+	Expiration time.Time
+
+	// Expired is true when the expiration date was reached
+	Expired bool
+
+	// Expiring is true when the domain isn't expired yet, but will be in the
+	// period defined in the analyzer
+	Expiring bool
+
+	// Reasons describes the status and events that lead to the state. All
+	// status of the lifecycle phases are listed, even the ones of phases less
+	// restrictive than Phase
+	Reasons []string
+}
+
+// LifecycleAnalyzer derives the lifecycle state of domains from their status
+// (RFC 8056 and NIC.br values) and events
+type LifecycleAnalyzer struct {
+	// Now returns the current time. When nil time.Now is used
+	Now func() time.Time
+
+	// ExpiringWithin is the period before the expiration date when the domain
+	// is considered expiring
+	ExpiringWithin time.Duration
+}
+
+// Analyze returns the lifecycle state of the domain
+func (l LifecycleAnalyzer) Analyze(domain *Domain) LifecycleState {
+	now := time.Now
+	if l.Now != nil {
+		now = l.Now
+	}
+
+	status := NewStatusSet(domain.Status...)
+	state := LifecycleState{
+		Phase: LifecyclePhaseActive,
+	}
+
+	// the phase is the most restrictive one, but all status found are reasons,
+	// as some of them usually appear together (like a hold with a court order)
+	for _, item := range lifecyclePhases {
+		for _, s := range item.status {
+			if !status.Contains(s) {
+				continue
+			}
+
+			if len(state.Reasons) == 0 {
+				state.Phase = item.phase
+			}
+			state.Reasons = append(state.Reasons, fmt.Sprintf("status “%s”", s))
+		}
+	}
+
+	if state.Phase == LifecyclePhaseActive {
+		if status.Contains(StatusActive) {
+			state.Reasons = append(state.Reasons, fmt.Sprintf("status “%s”", StatusActive))
+		} else {
+			state.Reasons = append(state.Reasons, "no status restricting the publication")
+		}
+	}
+
+	expiration, ok := domain.Expiration()
+	if !ok {
+		return state
+	}
+
+	state.Expiration = expiration.Date.Time
+	remaining := state.Expiration.Sub(now())
+
+	switch {
+	case remaining <= 0:
+		state.Expired = true
+		state.Reasons = append(state.Reasons, fmt.Sprintf("expired at %s",
+			state.Expiration.Format(time.RFC3339)))
+
+	case remaining <= l.ExpiringWithin:
+		state.Expiring = true
+		state.Reasons = append(state.Reasons, fmt.Sprintf("expires at %s, in %d days",
+			state.Expiration.Format(time.RFC3339), int(remaining.Hours()/24)))
+	}
+
+	return state
+}
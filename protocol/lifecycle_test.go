@@ -0,0 +1,161 @@
+package protocol
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLifecycleAnalyzerAnalyze(t *testing.T) {
+	analyzer := LifecycleAnalyzer{
+		Now: func() time.Time {
+			return time.Date(2024, time.May, 15, 0, 0, 0, 0, time.UTC)
+		},
+		ExpiringWithin: 30 * 24 * time.Hour,
+	}
+
+	data := []struct {
+		description string
+		fixture     string
+		expected    LifecycleState
+	}{
+		{
+			description: "it should detect an active domain",
+			fixture:     "nic.br.json",
+			expected: LifecycleState{
+				Phase:      LifecyclePhaseActive,
+				Expiration: time.Date(2033, time.February, 21, 0, 0, 0, 0, time.UTC),
+				Reasons:    []string{"status “active”"},
+			},
+		},
+		{
+			description: "it should detect an expiring domain",
+			fixture:     "expiring.br.json",
+			expected: LifecycleState{
+				Phase:      LifecyclePhaseActive,
+				Expiration: time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC),
+				Expiring:   true,
+				Reasons: []string{
+					"status “active”",
+					"expires at 2024-06-01T00:00:00Z, in 17 days",
+				},
+			},
+		},
+		{
+			description: "it should detect a domain waiting activation",
+			fixture:     "waiting-activation.br.json",
+			expected: LifecycleState{
+				Phase:      LifecyclePhaseWaitingActivation,
+				Expiration: time.Date(2025, time.May, 14, 0, 0, 0, 0, time.UTC),
+				Reasons:    []string{"status “nicbr waiting activation”"},
+			},
+		},
+		{
+			description: "it should prioritize a court order over other status",
+			fixture:     "court-order.br.json",
+			expected: LifecycleState{
+				Phase:      LifecyclePhaseFrozen,
+				Expiration: time.Date(2024, time.March, 8, 0, 0, 0, 0, time.UTC),
+				Expired:    true,
+				Reasons: []string{
+					"status “nicbr inactive court order”",
+					"status “client hold”",
+					"expired at 2024-03-08T00:00:00Z",
+				},
+			},
+		},
+		{
+			description: "it should detect a domain on hold",
+			fixture:     "server-hold.com.json",
+			expected: LifecycleState{
+				Phase:      LifecyclePhaseOnHold,
+				Expiration: time.Date(2025, time.August, 13, 4, 0, 0, 0, time.UTC),
+				Reasons:    []string{"status “server hold”"},
+			},
+		},
+		{
+			description: "it should detect a domain in redemption using the latest expiration",
+			fixture:     "redemption.com.json",
+			expected: LifecycleState{
+				Phase:      LifecyclePhaseRedemption,
+				Expiration: time.Date(2024, time.April, 30, 21, 28, 2, 0, time.UTC),
+				Expired:    true,
+				Reasons: []string{
+					"status “redemption period”",
+					"status “pending delete”",
+					"expired at 2024-04-30T21:28:02Z",
+				},
+			},
+		},
+		{
+			description: "it should list all status of a domain in many phases",
+			fixture:     "restore-on-hold.com.json",
+			expected: LifecycleState{
+				Phase:      LifecyclePhaseRedemption,
+				Expiration: time.Date(2024, time.February, 11, 10, 4, 51, 0, time.UTC),
+				Expired:    true,
+				Reasons: []string{
+					"status “redemption period”",
+					"status “pending restore”",
+					"status “pending delete”",
+					"status “server hold”",
+					"status “client hold”",
+					"expired at 2024-02-11T10:04:51Z",
+				},
+			},
+		},
+		{
+			description: "it should detect a domain pending delete",
+			fixture:     "pending-delete.com.json",
+			expected: LifecycleState{
+				Phase:      LifecyclePhasePendingDelete,
+				Expiration: time.Date(2024, time.January, 12, 15, 22, 40, 0, time.UTC),
+				Expired:    true,
+				Reasons: []string{
+					"status “pending delete”",
+					"expired at 2024-01-12T15:22:40Z",
+				},
+			},
+		},
+		{
+			description: "it should detect an inactive domain without expiration",
+			fixture:     "inactive.br.json",
+			expected: LifecycleState{
+				Phase:   LifecyclePhaseInactive,
+				Reasons: []string{"status “inactive”"},
+			},
+		},
+	}
+
+	for i, item := range data {
+		content, err := os.ReadFile(filepath.Join("testdata", "lifecycle", item.fixture))
+		if err != nil {
+			t.Fatalf("[%d] %s: unexpected error “%s”", i, item.description, err)
+		}
+
+		var domain Domain
+		if err := json.Unmarshal(content, &domain); err != nil {
+			t.Fatalf("[%d] %s: unexpected error “%s”", i, item.description, err)
+		}
+
+		if state := analyzer.Analyze(&domain); !reflect.DeepEqual(item.expected, state) {
+			t.Errorf("[%d] %s: unexpected state. Expected “%#v” and got “%#v”", i, item.description, item.expected, state)
+		}
+	}
+}
+
+func TestLifecycleAnalyzerWithoutStatus(t *testing.T) {
+	state := LifecycleAnalyzer{}.Analyze(&Domain{})
+
+	expected := LifecycleState{
+		Phase:   LifecyclePhaseActive,
+		Reasons: []string{"no status restricting the publication"},
+	}
+
+	if !reflect.DeepEqual(expected, state) {
+		t.Errorf("Unexpected state. Expected “%#v” and got “%#v”", expected, state)
+	}
+}
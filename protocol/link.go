@@ -1,6 +1,6 @@
 package protocol
 
-// Link describes Links as it is in RFC 7483, section 4.2
+// Link describes Links as it is in RFC 9083, section 4.2
 type Link struct {
 	Value string `json:"value,omitempty"`
 	Rel   string `json:"rel,omitempty"`
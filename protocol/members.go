@@ -0,0 +1,137 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Every object type stores the members that it doesn't declare, like other
+// registries' extensions, in an Extensions attribute. The helpers below decode
+// and encode them, so a response can be written again without losing data.
+
+// knownMembersCache stores the JSON member names declared by each object type,
+// so the struct tags are inspected only once
+var knownMembersCache sync.Map
+
+// knownMembers returns the lower case JSON member names declared by the
+// type, including the ones from embedded structures (like Conformance). The
+// names are lower case because the JSON decoder matches the members without
+// case sensitivity
+func knownMembers(t reflect.Type) map[string]struct{} {
+	if members, ok := knownMembersCache.Load(t); ok {
+		return members.(map[string]struct{})
+	}
+
+	members := make(map[string]struct{})
+	collectMembers(t, members)
+
+	knownMembersCache.Store(t, members)
+	return members
+}
+
+func collectMembers(t reflect.Type, members map[string]struct{}) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			collectMembers(field.Type, members)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		members[strings.ToLower(name)] = struct{}{}
+	}
+}
+
+// unmarshalObject decodes the known members into v, that must be a pointer
+// to a structure without its own UnmarshalJSON method, and stores all other
+// members in extensions
+func unmarshalObject(data []byte, v any, extensions *map[string]json.RawMessage) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	known := knownMembers(reflect.TypeOf(v).Elem())
+
+	*extensions = nil
+	for name, value := range members {
+		if _, ok := known[strings.ToLower(name)]; ok {
+			continue
+		}
+
+		if *extensions == nil {
+			*extensions = make(map[string]json.RawMessage)
+		}
+		(*extensions)[name] = value
+	}
+
+	return nil
+}
+
+// marshalObject encodes the known members of v, that must be a structure
+// without its own MarshalJSON method, followed by the extensions in
+// alphabetical order. Extensions with the same name of a known member are
+// ignored
+func marshalObject(v any, extensions map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extensions) == 0 {
+		return data, err
+	}
+
+	known := knownMembers(reflect.TypeOf(v))
+
+	names := make([]string, 0, len(extensions))
+	for name := range extensions {
+		if _, ok := known[strings.ToLower(name)]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var buffer bytes.Buffer
+	buffer.Write(data[:len(data)-1])
+
+	for _, name := range names {
+		if buffer.Len() > 1 {
+			buffer.WriteByte(',')
+		}
+
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(key)
+		buffer.WriteByte(':')
+
+		if value := extensions[name]; len(value) == 0 {
+			buffer.WriteString("null")
+		} else if err := json.Compact(&buffer, value); err != nil {
+			return nil, err
+		}
+	}
+
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
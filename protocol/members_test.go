@@ -0,0 +1,188 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestObjectExtensions(t *testing.T) {
+	data := []struct {
+		description   string
+		data          string
+		object        any
+		expected      any
+		expectedData  string
+		expectedError error
+	}{
+		{
+			description: "it should keep unknown members of a domain",
+			data:        `{"objectClassName":"domain","ldhName":"example.cz","fred_keyset":{"handle":"KEYSET"},"redacted":[{"name":{"type":"Registrant Name"}}]}`,
+			object:      &Domain{},
+			expected: &Domain{
+				ObjectClassName: "domain",
+				LDHName:         "example.cz",
+				Extensions: map[string]json.RawMessage{
+					"fred_keyset": json.RawMessage(`{"handle":"KEYSET"}`),
+					"redacted":    json.RawMessage(`[{"name":{"type":"Registrant Name"}}]`),
+				},
+			},
+			expectedData: `{"objectClassName":"domain","ldhName":"example.cz","fred_keyset":{"handle":"KEYSET"},"redacted":[{"name":{"type":"Registrant Name"}}]}`,
+		},
+		{
+			description: "it should keep unknown members of nested objects",
+			data:        `{"objectClassName":"entity","handle":"ARIN","autnums":[{"objectClassName":"autnum","startAutnum":1,"endAutnum":1,"type":"","country":"","arin_originas0_originautnums":[1]}]}`,
+			object:      &Entity{},
+			expected: &Entity{
+				ObjectClassName: "entity",
+				Handle:          "ARIN",
+				Autnums: []AS{
+					{
+						ObjectClassName: "autnum",
+						StartAutnum:     1,
+						EndAutnum:       1,
+						Extensions: map[string]json.RawMessage{
+							"arin_originas0_originautnums": json.RawMessage(`[1]`),
+						},
+					},
+				},
+			},
+			expectedData: `{"objectClassName":"entity","handle":"ARIN","autnums":[{"objectClassName":"autnum","startAutnum":1,"endAutnum":1,"type":"","country":"","arin_originas0_originautnums":[1]}]}`,
+		},
+		{
+			description: "it should write the extensions in alphabetical order",
+			data:        `{"zzz":1,"errorCode":400,"aaa":2,"mmm":3}`,
+			object:      &Error{},
+			expected: &Error{
+				ErrorCode: 400,
+				Extensions: map[string]json.RawMessage{
+					"aaa": json.RawMessage(`2`),
+					"mmm": json.RawMessage(`3`),
+					"zzz": json.RawMessage(`1`),
+				},
+			},
+			expectedData: `{"errorCode":400,"aaa":2,"mmm":3,"zzz":1}`,
+		},
+		{
+			description: "it should not store known members with different case",
+			data:        `{"ObjectClassName":"nameserver","LDHName":"a.dns.br"}`,
+			object:      &Nameserver{},
+			expected: &Nameserver{
+				ObjectClassName: "nameserver",
+				LDHName:         "a.dns.br",
+			},
+			expectedData: `{"objectClassName":"nameserver","ldhName":"a.dns.br"}`,
+		},
+		{
+			description: "it should keep embedded structure members as known",
+			data:        `{"rdapConformance":["rdap_level_0"],"notices":[{"title":"Help"}]}`,
+			object:      &Help{},
+			expected: &Help{
+				Notices: []Notice{
+					{Title: "Help"},
+				},
+				Conformance: Conformance{
+					Levels: []string{"rdap_level_0"},
+				},
+			},
+			expectedData: `{"notices":[{"title":"Help"}],"rdapConformance":["rdap_level_0"]}`,
+		},
+		{
+			description:   "it should fail to decode an invalid object",
+			data:          `{"objectClassName":"ip network","handle":1}`,
+			object:        &IPNetwork{},
+			expectedError: fmt.Errorf("json: cannot unmarshal number into Go struct field ipNetworkJSON.handle of type string"),
+		},
+	}
+
+	for i, item := range data {
+		err := json.Unmarshal([]byte(item.data), item.object)
+
+		if item.expectedError != nil {
+			if fmt.Sprintf("%v", item.expectedError) != fmt.Sprintf("%v", err) {
+				t.Errorf("[%d] %s: expected error “%s”, got “%s”", i, item.description, item.expectedError, err)
+			}
+			continue
+
+		} else if err != nil {
+			t.Errorf("[%d] %s: unexpected error “%s”", i, item.description, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(item.expected, item.object) {
+			t.Errorf("[%d] %s: unexpected object. Expected “%#v” and got “%#v”", i, item.description, item.expected, item.object)
+		}
+
+		output, err := json.Marshal(item.object)
+		if err != nil {
+			t.Errorf("[%d] %s: unexpected error “%s”", i, item.description, err)
+
+		} else if string(output) != item.expectedData {
+			t.Errorf("[%d] %s: unexpected JSON. Expected “%s” and got “%s”", i, item.description, item.expectedData, string(output))
+		}
+	}
+}
+
+func TestObjectExtensionsMarshalJSON(t *testing.T) {
+	data := []struct {
+		description   string
+		object        any
+		expected      string
+		expectedError error
+	}{
+		{
+			description: "it should ignore extensions with the name of a known member",
+			object: Domain{
+				ObjectClassName: "domain",
+				Extensions: map[string]json.RawMessage{
+					"objectClassName": json.RawMessage(`"entity"`),
+				},
+			},
+			expected: `{"objectClassName":"domain"}`,
+		},
+		{
+			description: "it should write empty extensions as null",
+			object: EntitySearchResults{
+				Extensions: map[string]json.RawMessage{
+					"example": nil,
+				},
+			},
+			expected: `{"entitySearchResults":null,"example":null}`,
+		},
+		{
+			description: "it should write extensions in an object without other members",
+			object: Help{
+				Extensions: map[string]json.RawMessage{
+					"example": json.RawMessage(` [ 1, 2 ] `),
+				},
+			},
+			expected: `{"example":[1,2]}`,
+		},
+		{
+			description: "it should fail with an invalid extension",
+			object: Help{
+				Extensions: map[string]json.RawMessage{
+					"example": json.RawMessage(`{{{`),
+				},
+			},
+			expectedError: fmt.Errorf("json: error calling MarshalJSON for type *protocol.Help: invalid character '{' looking for beginning of object key string"),
+		},
+	}
+
+	for i, item := range data {
+		output, err := json.Marshal(item.object)
+
+		if item.expectedError != nil {
+			if fmt.Sprintf("%v", item.expectedError) != fmt.Sprintf("%v", err) {
+				t.Errorf("[%d] %s: expected error “%s”, got “%s”", i, item.description, item.expectedError, err)
+			}
+
+		} else if err != nil {
+			t.Errorf("[%d] %s: unexpected error “%s”", i, item.description, err)
+
+		} else if string(output) != item.expected {
+			t.Errorf("[%d] %s: unexpected JSON. Expected “%s” and got “%s”", i, item.description, item.expected, string(output))
+		}
+	}
+}
@@ -1,13 +1,15 @@
 package protocol
 
-// IPAddresses describes the ipAddresses field as it is in RFC 7483, section
+import "encoding/json"
+
+// IPAddresses describes the ipAddresses field as it is in RFC 9083, section
 // 5.2
 type IPAddresses struct {
 	V4 []string `json:"v4,omitempty"`
 	V6 []string `json:"v6,omitempty"`
 }
 
-// Nameserver describes the Nameserver Object Class as it is in RFC 7483,
+// Nameserver describes the Nameserver Object Class as it is in RFC 9083,
 // section 5.2
 type Nameserver struct {
 	ObjectClassName string       `json:"objectClassName"`
@@ -20,6 +22,65 @@ type Nameserver struct {
 	IPAddresses     *IPAddresses `json:"ipAddresses,omitempty"`
 	Remarks         []Remark     `json:"remarks,omitempty"`
 	Links           []Link       `json:"links,omitempty"`
-	Port43          string       `json:"port43,omitempty"`
 	Events          []Event      `json:"events,omitempty"`
+	Notices         []Notice     `json:"notices,omitempty"`
+	Conformance
+	Port43
+
+	// Extensions stores the members unknown to this library
+	Extensions map[string]json.RawMessage `json:"-"`
+}
+
+// nameserverJSON avoids the recursion of the Nameserver JSON methods
+type nameserverJSON Nameserver
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Members unknown to
+// the Nameserver type are stored in the Extensions attribute
+func (n *Nameserver) UnmarshalJSON(data []byte) error {
+	return unmarshalObject(data, (*nameserverJSON)(n), &n.Extensions)
+}
+
+// MarshalJSON implements the json.Marshaler interface. The members from the
+// Extensions attribute are written after the known members in alphabetical
+// order
+func (n Nameserver) MarshalJSON() ([]byte, error) {
+	return marshalObject(nameserverJSON(n), n.Extensions)
+}
+
+// Extension decodes the members of the registered extension identified by the
+// prefix into the type that the extension defines for the nameserver object
+// class. If the object doesn't have any member of the extension, nil is
+// returned
+func (n *Nameserver) Extension(prefix string) (any, error) {
+	return decodeExtension(ObjectClassNameserver, prefix, n.Extensions)
 }
+
+// SetExtension replaces the members of the registered extension identified by
+// the prefix with the ones encoded from value
+func (n *Nameserver) SetExtension(prefix string, value any) error {
+	extensions, err := encodeExtension(prefix, n.Extensions, value)
+	if err != nil {
+		return err
+	}
+
+	n.Extensions = extensions
+	return nil
+}
+
+// GetHandle implements the Object interface
+func (n *Nameserver) GetHandle() string { return n.Handle }
+
+// GetLinks implements the Object interface
+func (n *Nameserver) GetLinks() []Link { return n.Links }
+
+// GetEvents implements the Object interface
+func (n *Nameserver) GetEvents() []Event { return n.Events }
+
+// GetEntities implements the Object interface
+func (n *Nameserver) GetEntities() []Entity { return n.Entities }
+
+// GetRemarks implements the Object interface
+func (n *Nameserver) GetRemarks() []Remark { return n.Remarks }
+
+// GetNotices implements the Object interface
+func (n *Nameserver) GetNotices() []Notice { return n.Notices }
@@ -0,0 +1,126 @@
+package protocol
+
+import "encoding/json"
+
+const (
+	// NICBRPrefix is the prefix of the NIC.br extension members
+	NICBRPrefix = "nicbr"
+
+	// NICBRIdentifier is the conformance value of the NIC.br extension
+	NICBRIdentifier = "nicbr_level_0"
+)
+
+// NICBRDomain stores the NIC.br extension members of a domain
+type NICBRDomain struct {
+	// Arbitration informs that the domain is in an administrative dispute
+	// process (SACI-Adm)
+	Arbitration bool `json:"nicbr_arbitration,omitempty"`
+}
+
+// NICBREntity stores the NIC.br extension members of an entity
+type NICBREntity struct {
+	CustomerSupportService *CustomerSupportService `json:"nicbr_customerSupportService,omitempty"`
+	DomainCount            int                     `json:"nicbr_domainCount,omitempty"`
+	InetCount              int                     `json:"nicbr_inetCount,omitempty"`
+	AutnumCount            int                     `json:"nicbr_autnumCount,omitempty"`
+}
+
+// NICBRIPNetwork stores the NIC.br extension members of an IP network
+type NICBRIPNetwork struct {
+	Autnum             uint32              `json:"nicbr_autnum,omitempty"`
+	ReverseDelegations []ReverseDelegation `json:"nicbr_reverseDelegations,omitempty"`
+}
+
+// NICBRAS stores the NIC.br extension members of an autonomous system
+type NICBRAS struct {
+	RoutingPolicy []RoutingPolicy `json:"nicbr_routingPolicy,omitempty"`
+}
+
+func init() {
+	RegisterExtension(Extension{
+		Identifier: NICBRIdentifier,
+		Prefix:     NICBRPrefix,
+		Members: map[string]func() any{
+			ObjectClassDomain:    func() any { return &NICBRDomain{} },
+			ObjectClassEntity:    func() any { return &NICBREntity{} },
+			ObjectClassIPNetwork: func() any { return &NICBRIPNetwork{} },
+			ObjectClassAutnum:    func() any { return &NICBRAS{} },
+		},
+	})
+}
+
+// The functions below keep the deprecated NIC.br attributes of the object
+// classes filled after decoding, and write them back when encoding. When the
+// attributes don't change, the original members are kept untouched.
+
+func (d *Domain) loadNICBR() error {
+	var nicbr NICBRDomain
+	if err := decodeMembers(d.Extensions, NICBRPrefix, &nicbr); err != nil {
+		return err
+	}
+
+	d.Arbitration = nicbr.Arbitration
+	return nil
+}
+
+func (d *Domain) storeNICBR() (map[string]json.RawMessage, error) {
+	return overlayMembers(d.Extensions, NICBRPrefix, &NICBRDomain{
+		Arbitration: d.Arbitration,
+	})
+}
+
+func (e *Entity) loadNICBR() error {
+	var nicbr NICBREntity
+	if err := decodeMembers(e.Extensions, NICBRPrefix, &nicbr); err != nil {
+		return err
+	}
+
+	e.CustomerSupportService = nicbr.CustomerSupportService
+	e.DomainCount = nicbr.DomainCount
+	e.InetCount = nicbr.InetCount
+	e.AutnumCount = nicbr.AutnumCount
+	return nil
+}
+
+func (e *Entity) storeNICBR() (map[string]json.RawMessage, error) {
+	return overlayMembers(e.Extensions, NICBRPrefix, &NICBREntity{
+		CustomerSupportService: e.CustomerSupportService,
+		DomainCount:            e.DomainCount,
+		InetCount:              e.InetCount,
+		AutnumCount:            e.AutnumCount,
+	})
+}
+
+func (i *IPNetwork) loadNICBR() error {
+	var nicbr NICBRIPNetwork
+	if err := decodeMembers(i.Extensions, NICBRPrefix, &nicbr); err != nil {
+		return err
+	}
+
+	i.Autnum = nicbr.Autnum
+	i.ReverseDelegations = nicbr.ReverseDelegations
+	return nil
+}
+
+func (i *IPNetwork) storeNICBR() (map[string]json.RawMessage, error) {
+	return overlayMembers(i.Extensions, NICBRPrefix, &NICBRIPNetwork{
+		Autnum:             i.Autnum,
+		ReverseDelegations: i.ReverseDelegations,
+	})
+}
+
+func (a *AS) loadNICBR() error {
+	var nicbr NICBRAS
+	if err := decodeMembers(a.Extensions, NICBRPrefix, &nicbr); err != nil {
+		return err
+	}
+
+	a.RoutingPolicy = nicbr.RoutingPolicy
+	return nil
+}
+
+func (a *AS) storeNICBR() (map[string]json.RawMessage, error) {
+	return overlayMembers(a.Extensions, NICBRPrefix, &NICBRAS{
+		RoutingPolicy: a.RoutingPolicy,
+	})
+}
@@ -0,0 +1,105 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// List of object class names as described in RFC 9083, section 5
+const (
+	// ObjectClassDomain identifies the Domain Object Class
+	ObjectClassDomain = "domain"
+
+	// ObjectClassNameserver identifies the Nameserver Object Class
+	ObjectClassNameserver = "nameserver"
+
+	// ObjectClassEntity identifies the Entity Object Class
+	ObjectClassEntity = "entity"
+
+	// ObjectClassIPNetwork identifies the IP Network Object Class
+	ObjectClassIPNetwork = "ip network"
+
+	// ObjectClassAutnum identifies the Autonomous System Number Object Class
+	ObjectClassAutnum = "autnum"
+)
+
+// Object is implemented by every RDAP response type and exposes the common
+// data structures described in RFC 9083, section 4. Types that don't have a
+// specific member return its zero value
+type Object interface {
+	GetHandle() string
+	GetLinks() []Link
+	GetEvents() []Event
+	GetEntities() []Entity
+	GetRemarks() []Remark
+	GetNotices() []Notice
+	GetConformance() []string
+}
+
+// Decode reads a RDAP JSON document and parses it into the concrete type
+// identified by the objectClassName member. Documents without an object class
+// are detected by their shape: search results, error responses (errorCode
+// member) and help responses
+func Decode(r io.Reader) (Object, error) {
+	var data json.RawMessage
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, err
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+
+	var object Object
+
+	if raw, ok := members["objectClassName"]; ok {
+		var objectClassName string
+		if err := json.Unmarshal(raw, &objectClassName); err != nil {
+			return nil, err
+		}
+
+		switch objectClassName {
+		case ObjectClassDomain:
+			object = &Domain{}
+		case ObjectClassNameserver:
+			object = &Nameserver{}
+		case ObjectClassEntity:
+			object = &Entity{}
+		case ObjectClassIPNetwork:
+			object = &IPNetwork{}
+		case ObjectClassAutnum:
+			object = &AS{}
+		default:
+			return nil, fmt.Errorf("unknown object class name “%s”", objectClassName)
+		}
+
+	} else if _, ok := members["domainSearchResults"]; ok {
+		object = &DomainSearchResults{}
+
+	} else if _, ok := members["nameserverSearchResults"]; ok {
+		object = &NameserverSearchResults{}
+
+	} else if _, ok := members["entitySearchResults"]; ok {
+		object = &EntitySearchResults{}
+
+	} else if _, ok := members["errorCode"]; ok {
+		object = &Error{}
+
+	} else if _, ok := members["notices"]; ok {
+		object = &Help{}
+
+	} else if _, ok := members["rdapConformance"]; ok {
+		object = &Help{}
+
+	} else {
+		return nil, fmt.Errorf("unknown RDAP object")
+	}
+
+	if err := json.Unmarshal(data, object); err != nil {
+		return nil, err
+	}
+
+	return object, nil
+}
@@ -0,0 +1,165 @@
+package protocol
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	data := []struct {
+		description   string
+		data          string
+		expected      Object
+		expectedError error
+	}{
+		{
+			description: "it should decode a domain",
+			data:        `{"objectClassName": "domain", "handle": "example.com", "ldhName": "example.com"}`,
+			expected: &Domain{
+				ObjectClassName: "domain",
+				Handle:          "example.com",
+				LDHName:         "example.com",
+			},
+		},
+		{
+			description: "it should decode a nameserver",
+			data:        `{"objectClassName": "nameserver", "ldhName": "a.dns.br", "rdapConformance": ["rdap_level_0"]}`,
+			expected: &Nameserver{
+				ObjectClassName: "nameserver",
+				LDHName:         "a.dns.br",
+				Conformance: Conformance{
+					Levels: []string{"rdap_level_0"},
+				},
+			},
+		},
+		{
+			description: "it should decode an entity",
+			data:        `{"objectClassName": "entity", "handle": "XXXX", "roles": ["registrant"]}`,
+			expected: &Entity{
+				ObjectClassName: "entity",
+				Handle:          "XXXX",
+				Roles:           []string{"registrant"},
+			},
+		},
+		{
+			description: "it should decode an IP network",
+			data:        `{"objectClassName": "ip network", "handle": "200.160.0.0/20", "startAddress": "200.160.0.0", "endAddress": "200.160.15.255", "ipVersion": "v4"}`,
+			expected: &IPNetwork{
+				ObjectClassName: "ip network",
+				Handle:          "200.160.0.0/20",
+				StartAddress:    "200.160.0.0",
+				EndAddress:      "200.160.15.255",
+				IPVersion:       "v4",
+			},
+		},
+		{
+			description: "it should decode an autnum",
+			data:        `{"objectClassName": "autnum", "handle": "22548", "startAutnum": 22548, "endAutnum": 22548}`,
+			expected: &AS{
+				ObjectClassName: "autnum",
+				Handle:          "22548",
+				StartAutnum:     22548,
+				EndAutnum:       22548,
+			},
+		},
+		{
+			description: "it should decode domain search results",
+			data:        `{"domainSearchResults": [{"objectClassName": "domain", "ldhName": "example.com"}]}`,
+			expected: &DomainSearchResults{
+				Domains: []Domain{
+					{
+						ObjectClassName: "domain",
+						LDHName:         "example.com",
+					},
+				},
+			},
+		},
+		{
+			description: "it should decode nameserver search results",
+			data:        `{"nameserverSearchResults": [{"objectClassName": "nameserver", "ldhName": "a.dns.br"}]}`,
+			expected: &NameserverSearchResults{
+				Nameservers: []Nameserver{
+					{
+						ObjectClassName: "nameserver",
+						LDHName:         "a.dns.br",
+					},
+				},
+			},
+		},
+		{
+			description: "it should decode entity search results",
+			data:        `{"entitySearchResults": [{"objectClassName": "entity", "handle": "XXXX"}]}`,
+			expected: &EntitySearchResults{
+				Entities: []Entity{
+					{
+						ObjectClassName: "entity",
+						Handle:          "XXXX",
+					},
+				},
+			},
+		},
+		{
+			description: "it should decode an error",
+			data:        `{"errorCode": 404, "title": "Not Found"}`,
+			expected: &Error{
+				ErrorCode: 404,
+				Title:     "Not Found",
+			},
+		},
+		{
+			description: "it should decode a help",
+			data:        `{"rdapConformance": ["rdap_level_0"], "notices": [{"title": "Help"}]}`,
+			expected: &Help{
+				Notices: []Notice{
+					{Title: "Help"},
+				},
+				Conformance: Conformance{
+					Levels: []string{"rdap_level_0"},
+				},
+			},
+		},
+		{
+			description:   "it should fail for an unknown object class name",
+			data:          `{"objectClassName": "unknown"}`,
+			expectedError: fmt.Errorf("unknown object class name “unknown”"),
+		},
+		{
+			description:   "it should fail for an invalid object class name",
+			data:          `{"objectClassName": 1}`,
+			expectedError: fmt.Errorf("json: cannot unmarshal number into Go value of type string"),
+		},
+		{
+			description:   "it should fail for an unknown document",
+			data:          `{"something": "else"}`,
+			expectedError: fmt.Errorf("unknown RDAP object"),
+		},
+		{
+			description:   "it should fail for an invalid JSON",
+			data:          `{{{`,
+			expectedError: fmt.Errorf("invalid character '{' looking for beginning of object key string"),
+		},
+		{
+			description:   "it should fail to decode the concrete type",
+			data:          `{"objectClassName": "domain", "handle": 1}`,
+			expectedError: fmt.Errorf("json: cannot unmarshal number into Go struct field domainJSON.handle of type string"),
+		},
+	}
+
+	for i, item := range data {
+		object, err := Decode(strings.NewReader(item.data))
+
+		if item.expectedError != nil {
+			if fmt.Sprintf("%v", item.expectedError) != fmt.Sprintf("%v", err) {
+				t.Errorf("[%d] %s: expected error “%s”, got “%s”", i, item.description, item.expectedError, err)
+			}
+
+		} else if err != nil {
+			t.Errorf("[%d] %s: unexpected error “%s”", i, item.description, err)
+
+		} else if !reflect.DeepEqual(item.expected, object) {
+			t.Errorf("[%d] %s: unexpected object returned. Expected “%#v” and got “%#v”", i, item.description, item.expected, object)
+		}
+	}
+}
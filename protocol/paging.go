@@ -0,0 +1,54 @@
+package protocol
+
+// List of conformance values of the sorting and paging extension (RFC 8977,
+// section 8)
+const (
+	// SortingIdentifier is listed when the response contains the
+	// sorting_metadata member
+	SortingIdentifier = "sorting"
+
+	// PagingIdentifier is listed when the response contains the
+	// paging_metadata member
+	PagingIdentifier = "paging"
+)
+
+// PagingMetadata describes the paging_metadata member of the search results
+// as it is in RFC 8977, section 2.1. The next page is referenced by the link
+// with the "next" relation
+type PagingMetadata struct {
+	TotalCount *int   `json:"totalCount,omitempty"`
+	PageSize   int    `json:"pageSize,omitempty"`
+	PageNumber int    `json:"pageNumber,omitempty"`
+	Links      []Link `json:"links,omitempty"`
+}
+
+// Next returns the URL of the next page. When there's no next page an empty
+// string is returned
+func (p *PagingMetadata) Next() string {
+	if p == nil {
+		return ""
+	}
+
+	for _, link := range p.Links {
+		if link.Rel == "next" && link.Href != "" {
+			return link.Href
+		}
+	}
+	return ""
+}
+
+// SortingMetadata describes the sorting_metadata member of the search results
+// as it is in RFC 8977, section 2.1
+type SortingMetadata struct {
+	CurrentSort    string          `json:"currentSort,omitempty"`
+	AvailableSorts []AvailableSort `json:"availableSorts,omitempty"`
+}
+
+// AvailableSort describes a sort property supported by the server, with the
+// links to the results sorted by it (RFC 8977, section 2.1)
+type AvailableSort struct {
+	Property string `json:"property"`
+	JSONPath string `json:"jsonPath,omitempty"`
+	Default  bool   `json:"default"`
+	Links    []Link `json:"links,omitempty"`
+}
@@ -0,0 +1,100 @@
+package protocol
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestSearchResultsPagingMetadata(t *testing.T) {
+	// example adapted from RFC 8977, section 2.3
+	data := `{
+  "rdapConformance": ["rdap_level_0", "paging", "sorting"],
+  "domainSearchResults": [{"objectClassName": "domain", "ldhName": "example.com"}],
+  "sorting_metadata": {
+    "currentSort": "name",
+    "availableSorts": [
+      {
+        "property": "registrationDate",
+        "jsonPath": "$.domainSearchResults[*].events[?(@.eventAction==\"registration\")].eventDate",
+        "default": false,
+        "links": [
+          {
+            "value": "https://example.com/rdap/domains?name=example*.com&sort=name",
+            "rel": "alternate",
+            "href": "https://example.com/rdap/domains?name=example*.com&sort=registrationDate",
+            "type": "application/rdap+json"
+          }
+        ]
+      }
+    ]
+  },
+  "paging_metadata": {
+    "totalCount": 43,
+    "pageSize": 50,
+    "pageNumber": 1,
+    "links": [
+      {
+        "value": "https://example.com/rdap/domains?name=example*.com",
+        "rel": "next",
+        "href": "https://example.com/rdap/domains?name=example*.com&cursor=wJlCDLIl6KTWypN7T6vc6nWEmEYe99Hjf1XY1xmqV-M=",
+        "type": "application/rdap+json"
+      }
+    ]
+  }
+}`
+
+	var results DomainSearchResults
+	if err := json.Unmarshal([]byte(data), &results); err != nil {
+		t.Fatal(err)
+	}
+
+	totalCount := 43
+	expected := DomainSearchResults{
+		Domains: []Domain{{ObjectClassName: "domain", LDHName: "example.com"}},
+		PagingMetadata: &PagingMetadata{
+			TotalCount: &totalCount,
+			PageSize:   50,
+			PageNumber: 1,
+			Links: []Link{
+				{
+					Value: "https://example.com/rdap/domains?name=example*.com",
+					Rel:   "next",
+					Href:  "https://example.com/rdap/domains?name=example*.com&cursor=wJlCDLIl6KTWypN7T6vc6nWEmEYe99Hjf1XY1xmqV-M=",
+					Type:  "application/rdap+json",
+				},
+			},
+		},
+		SortingMetadata: &SortingMetadata{
+			CurrentSort: "name",
+			AvailableSorts: []AvailableSort{
+				{
+					Property: "registrationDate",
+					JSONPath: `$.domainSearchResults[*].events[?(@.eventAction=="registration")].eventDate`,
+					Links: []Link{
+						{
+							Value: "https://example.com/rdap/domains?name=example*.com&sort=name",
+							Rel:   "alternate",
+							Href:  "https://example.com/rdap/domains?name=example*.com&sort=registrationDate",
+							Type:  "application/rdap+json",
+						},
+					},
+				},
+			},
+		},
+		Conformance: Conformance{Levels: []string{"rdap_level_0", "paging", "sorting"}},
+	}
+
+	if !reflect.DeepEqual(expected, results) {
+		t.Errorf("unexpected search results “%#v”", results)
+	}
+
+	if next := results.PagingMetadata.Next(); next != expected.PagingMetadata.Links[0].Href {
+		t.Errorf("unexpected next page “%s”", next)
+	}
+
+	var empty *PagingMetadata
+	if next := empty.Next(); next != "" {
+		t.Errorf("unexpected next page “%s” without paging metadata", next)
+	}
+}
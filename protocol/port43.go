@@ -1,6 +1,6 @@
 package protocol
 
-// Port43 described as in RFC 7483, section 4.7. The port43 is usually inserted in all responses to
+// Port43 described as in RFC 9083, section 4.7. The port43 is usually inserted in all responses to
 // identify the WHOIS server where the containing object instance may be found
 type Port43 struct {
 	Port43 string `json:"port43,omitempty"`
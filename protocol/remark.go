@@ -40,11 +40,11 @@ const (
 	RemarkTypeObjectTruncatedServerPolicy RemarkType = "object truncated due to server policy"
 )
 
-// RemarkType stores one of the possible remark types as listed in RFC 7483,
+// RemarkType stores one of the possible remark types as listed in RFC 9083,
 // section 10.2.1
 type RemarkType string
 
-// Remark describes Remarks as it is in RFC 7483, section 4.3
+// Remark describes Remarks as it is in RFC 9083, section 4.3
 type Remark struct {
 	Title       string   `json:"title,omitempty"`
 	Type        string   `json:"type,omitempty"`
@@ -0,0 +1,159 @@
+package protocol
+
+import "encoding/json"
+
+// DomainSearchResults describes the answer to a domain search as it is in
+// RFC 9083, section 8
+type DomainSearchResults struct {
+	Notices            []Notice            `json:"notices,omitempty"`
+	Remarks            []Remark            `json:"remarks,omitempty"`
+	Lang               string              `json:"lang,omitempty"`
+	Domains            []Domain            `json:"domainSearchResults"`
+	PagingMetadata     *PagingMetadata     `json:"paging_metadata,omitempty"`
+	SortingMetadata    *SortingMetadata    `json:"sorting_metadata,omitempty"`
+	SubsettingMetadata *SubsettingMetadata `json:"subsetting_metadata,omitempty"`
+	Conformance
+
+	// Extensions stores the members unknown to this library
+	Extensions map[string]json.RawMessage `json:"-"`
+}
+
+// domainSearchResultsJSON avoids the recursion of the DomainSearchResults JSON methods
+type domainSearchResultsJSON DomainSearchResults
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Members unknown to
+// the DomainSearchResults type are stored in the Extensions attribute
+func (s *DomainSearchResults) UnmarshalJSON(data []byte) error {
+	return unmarshalObject(data, (*domainSearchResultsJSON)(s), &s.Extensions)
+}
+
+// MarshalJSON implements the json.Marshaler interface. The members from the
+// Extensions attribute are written after the known members in alphabetical
+// order
+func (s DomainSearchResults) MarshalJSON() ([]byte, error) {
+	return marshalObject(domainSearchResultsJSON(s), s.Extensions)
+}
+
+// NameserverSearchResults describes the answer to a nameserver search as it
+// is in RFC 9083, section 8
+type NameserverSearchResults struct {
+	Notices            []Notice            `json:"notices,omitempty"`
+	Remarks            []Remark            `json:"remarks,omitempty"`
+	Lang               string              `json:"lang,omitempty"`
+	Nameservers        []Nameserver        `json:"nameserverSearchResults"`
+	PagingMetadata     *PagingMetadata     `json:"paging_metadata,omitempty"`
+	SortingMetadata    *SortingMetadata    `json:"sorting_metadata,omitempty"`
+	SubsettingMetadata *SubsettingMetadata `json:"subsetting_metadata,omitempty"`
+	Conformance
+
+	// Extensions stores the members unknown to this library
+	Extensions map[string]json.RawMessage `json:"-"`
+}
+
+// nameserverSearchResultsJSON avoids the recursion of the NameserverSearchResults JSON methods
+type nameserverSearchResultsJSON NameserverSearchResults
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Members unknown to
+// the NameserverSearchResults type are stored in the Extensions attribute
+func (s *NameserverSearchResults) UnmarshalJSON(data []byte) error {
+	return unmarshalObject(data, (*nameserverSearchResultsJSON)(s), &s.Extensions)
+}
+
+// MarshalJSON implements the json.Marshaler interface. The members from the
+// Extensions attribute are written after the known members in alphabetical
+// order
+func (s NameserverSearchResults) MarshalJSON() ([]byte, error) {
+	return marshalObject(nameserverSearchResultsJSON(s), s.Extensions)
+}
+
+// EntitySearchResults describes the answer to an entity search as it is in
+// RFC 9083, section 8
+type EntitySearchResults struct {
+	Notices            []Notice            `json:"notices,omitempty"`
+	Remarks            []Remark            `json:"remarks,omitempty"`
+	Lang               string              `json:"lang,omitempty"`
+	Entities           []Entity            `json:"entitySearchResults"`
+	PagingMetadata     *PagingMetadata     `json:"paging_metadata,omitempty"`
+	SortingMetadata    *SortingMetadata    `json:"sorting_metadata,omitempty"`
+	SubsettingMetadata *SubsettingMetadata `json:"subsetting_metadata,omitempty"`
+	Conformance
+
+	// Extensions stores the members unknown to this library
+	Extensions map[string]json.RawMessage `json:"-"`
+}
+
+// entitySearchResultsJSON avoids the recursion of the EntitySearchResults JSON methods
+type entitySearchResultsJSON EntitySearchResults
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Members unknown to
+// the EntitySearchResults type are stored in the Extensions attribute
+func (s *EntitySearchResults) UnmarshalJSON(data []byte) error {
+	return unmarshalObject(data, (*entitySearchResultsJSON)(s), &s.Extensions)
+}
+
+// MarshalJSON implements the json.Marshaler interface. The members from the
+// Extensions attribute are written after the known members in alphabetical
+// order
+func (s EntitySearchResults) MarshalJSON() ([]byte, error) {
+	return marshalObject(entitySearchResultsJSON(s), s.Extensions)
+}
+
+// GetHandle implements the Object interface. Search results don't have a
+// handle
+func (s *DomainSearchResults) GetHandle() string { return "" }
+
+// GetLinks implements the Object interface. Search results don't have links
+func (s *DomainSearchResults) GetLinks() []Link { return nil }
+
+// GetEvents implements the Object interface. Search results don't have events
+func (s *DomainSearchResults) GetEvents() []Event { return nil }
+
+// GetEntities implements the Object interface. Search results don't have
+// entities, the domains are available in the Domains attribute
+func (s *DomainSearchResults) GetEntities() []Entity { return nil }
+
+// GetRemarks implements the Object interface
+func (s *DomainSearchResults) GetRemarks() []Remark { return s.Remarks }
+
+// GetNotices implements the Object interface
+func (s *DomainSearchResults) GetNotices() []Notice { return s.Notices }
+
+// GetHandle implements the Object interface. Search results don't have a
+// handle
+func (s *NameserverSearchResults) GetHandle() string { return "" }
+
+// GetLinks implements the Object interface. Search results don't have links
+func (s *NameserverSearchResults) GetLinks() []Link { return nil }
+
+// GetEvents implements the Object interface. Search results don't have events
+func (s *NameserverSearchResults) GetEvents() []Event { return nil }
+
+// GetEntities implements the Object interface. Search results don't have
+// entities, the nameservers are available in the Nameservers attribute
+func (s *NameserverSearchResults) GetEntities() []Entity { return nil }
+
+// GetRemarks implements the Object interface
+func (s *NameserverSearchResults) GetRemarks() []Remark { return s.Remarks }
+
+// GetNotices implements the Object interface
+func (s *NameserverSearchResults) GetNotices() []Notice { return s.Notices }
+
+// GetHandle implements the Object interface. Search results don't have a
+// handle
+func (s *EntitySearchResults) GetHandle() string { return "" }
+
+// GetLinks implements the Object interface. Search results don't have links
+func (s *EntitySearchResults) GetLinks() []Link { return nil }
+
+// GetEvents implements the Object interface. Search results don't have events
+func (s *EntitySearchResults) GetEvents() []Event { return nil }
+
+// GetEntities implements the Object interface. Search results don't have
+// related entities, the entities found are available in the Entities attribute
+func (s *EntitySearchResults) GetEntities() []Entity { return nil }
+
+// GetRemarks implements the Object interface
+func (s *EntitySearchResults) GetRemarks() []Remark { return s.Remarks }
+
+// GetNotices implements the Object interface
+func (s *EntitySearchResults) GetNotices() []Notice { return s.Notices }
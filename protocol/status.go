@@ -210,6 +210,19 @@ const (
 	StatusTransferPeriod Status = "transfer period"
 )
 
+// Registered later in the IANA RDAP JSON Values registry, mostly used by the
+// RIRs - https://www.iana.org/assignments/rdap-json-values
+const (
+	// StatusAdministrative the object instance has been allocated
+	// administratively (i.e., not for use by the recipient in their own right
+	// in operational networks)
+	StatusAdministrative Status = "administrative"
+
+	// StatusReserved the object instance has been allocated to an IANA
+	// special-purpose address registry
+	StatusReserved Status = "reserved"
+)
+
 // Proposed by NIC.br for DNS and DNSSEC checks of delegations
 const (
 	// StatusNSAA nameserver has authority for the domain (well configured)
@@ -294,6 +307,6 @@ const (
 	StatusInactiveCG Status = "nicbr inactive CG"
 )
 
-// Status stores one of the possible status as listed in RFC 7483, section
+// Status stores one of the possible status as listed in RFC 9083, section
 // 10.2.2
 type Status string
@@ -0,0 +1,203 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// knownStatus stores the object status defined in this package, used to
+// validate the values when parsing a status set
+var knownStatus = map[Status]bool{
+	StatusValidated:           true,
+	StatusProxy:               true,
+	StatusPrivate:             true,
+	StatusObscured:            true,
+	StatusAssociated:          true,
+	StatusLocked:              true,
+	StatusActive:              true,
+	StatusInactive:            true,
+	StatusPendingCreate:       true,
+	StatusPendingRenew:        true,
+	StatusPendingTransfer:     true,
+	StatusPendingUpdate:       true,
+	StatusPendingDelete:       true,
+	StatusRenewProhibited:     true,
+	StatusTransferProhibited:  true,
+	StatusUpdateProhibited:    true,
+	StatusDeleteProhibited:    true,
+	StatusRemoved:             true,
+	StatusAddPeriod:           true,
+	StatusAutoRenewPeriod:     true,
+	StatusPendingRestore:      true,
+	StatusRedemptionPeriod:    true,
+	StatusRenewPeriod:         true,
+	StatusTransferPeriod:      true,
+	StatusAdministrative:      true,
+	StatusReserved:            true,
+	StatusClientHold:          true,
+	StatusServerHold:          true,
+	StatusWaitingActivation:   true,
+	StatusWaitingInactivation: true,
+	StatusInactiveCourtOrder:  true,
+	StatusInactiveCG:          true,
+
+	StatusClientDeleteProhibited:   true,
+	StatusClientRenewProhibited:    true,
+	StatusClientTransferProhibited: true,
+	StatusClientUpdateProhibited:   true,
+	StatusServerDeleteProhibited:   true,
+	StatusServerRenewProhibited:    true,
+	StatusServerTransferProhibited: true,
+	StatusServerUpdateProhibited:   true,
+}
+
+// pendingProhibitions lists the prohibitions that can't be combined with a
+// pending action, as described in RFC 5731, section 2.3
+var pendingProhibitions = map[Status][]Status{
+	StatusPendingDelete: {
+		StatusDeleteProhibited, StatusClientDeleteProhibited, StatusServerDeleteProhibited,
+	},
+	StatusPendingRenew: {
+		StatusRenewProhibited, StatusClientRenewProhibited, StatusServerRenewProhibited,
+	},
+	StatusPendingTransfer: {
+		StatusTransferProhibited, StatusClientTransferProhibited, StatusServerTransferProhibited,
+	},
+	StatusPendingUpdate: {
+		StatusUpdateProhibited, StatusClientUpdateProhibited, StatusServerUpdateProhibited,
+	},
+}
+
+// StatusSet stores a group of status without repetition. In JSON it is
+// represented as an array in alphabetical order
+type StatusSet map[Status]struct{}
+
+// NewStatusSet creates a set with the given status
+func NewStatusSet(status ...Status) StatusSet {
+	s := make(StatusSet, len(status))
+	s.Add(status...)
+	return s
+}
+
+// ParseStatusSet creates a set from RDAP status values or EPP status codes,
+// which are converted to the RDAP status. It fails for unknown values, and
+// for combinations that RFC 5731 forbids, like "active" with "inactive", two
+// different pending actions, or a pending action with the prohibition of
+// the same action
+func ParseStatusSet(values ...string) (StatusSet, error) {
+	s := make(StatusSet, len(values))
+	for _, value := range values {
+		status := Status(value)
+		if converted, ok := StatusFromEPP(EPPStatus(value)); ok {
+			status = converted
+		}
+
+		if !knownStatus[status] {
+			return nil, fmt.Errorf("unknown status “%s”", value)
+		}
+		s.Add(status)
+	}
+
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Validate checks if the set contains only status combinations allowed by
+// RFC 5731, section 2.3
+func (s StatusSet) Validate() error {
+	if s.Contains(StatusActive) && s.Contains(StatusInactive) {
+		return fmt.Errorf("status “%s” can't be combined with “%s”", StatusActive, StatusInactive)
+	}
+
+	var pending []Status
+	for _, status := range s.List() {
+		if status.IsPending() && status != StatusPendingRestore {
+			pending = append(pending, status)
+		}
+	}
+
+	if len(pending) > 1 {
+		return fmt.Errorf("status “%s” can't be combined with “%s”", pending[0], pending[1])
+	}
+
+	for _, status := range pending {
+		for _, prohibition := range pendingProhibitions[status] {
+			if s.Contains(prohibition) {
+				return fmt.Errorf("status “%s” can't be combined with “%s”", status, prohibition)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Add inserts the status in the set
+func (s StatusSet) Add(status ...Status) {
+	for _, item := range status {
+		s[item] = struct{}{}
+	}
+}
+
+// Remove deletes the status from the set
+func (s StatusSet) Remove(status ...Status) {
+	for _, item := range status {
+		delete(s, item)
+	}
+}
+
+// Contains checks if the status belongs to the set
+func (s StatusSet) Contains(status Status) bool {
+	_, ok := s[status]
+	return ok
+}
+
+// List returns the status of the set in alphabetical order
+func (s StatusSet) List() []Status {
+	list := make([]Status, 0, len(s))
+	for status := range s {
+		list = append(list, status)
+	}
+
+	slices.Sort(list)
+	return list
+}
+
+// EPP returns the EPP status codes of the set in alphabetical order. Status
+// without an equivalent EPP code are ignored
+func (s StatusSet) EPP() []EPPStatus {
+	var codes []EPPStatus
+	for status := range s {
+		if code, ok := status.EPP(); ok {
+			codes = append(codes, code)
+		}
+	}
+
+	slices.Sort(codes)
+	return codes
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (s StatusSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.List())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. The values are
+// validated like in ParseStatusSet
+func (s *StatusSet) UnmarshalJSON(data []byte) error {
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	set, err := ParseStatusSet(values...)
+	if err != nil {
+		return err
+	}
+
+	*s = set
+	return nil
+}
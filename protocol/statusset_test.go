@@ -0,0 +1,125 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestParseStatusSet(t *testing.T) {
+	data := []struct {
+		description   string
+		values        []string
+		expected      []Status
+		expectedEPP   []EPPStatus
+		expectedError error
+	}{
+		{
+			description: "it should parse RDAP and EPP values",
+			values:      []string{"clientTransferProhibited", "active", "ok", "nicbr waiting activation"},
+			expected: []Status{
+				StatusActive,
+				StatusClientTransferProhibited,
+				StatusWaitingActivation,
+			},
+			expectedEPP: []EPPStatus{
+				EPPStatusClientTransferProhibited,
+				EPPStatusOK,
+			},
+		},
+		{
+			description: "it should allow a pending restore with other pending action",
+			values:      []string{"pendingDelete", "pendingRestore", "redemption period"},
+			expected: []Status{
+				StatusPendingDelete,
+				StatusPendingRestore,
+				StatusRedemptionPeriod,
+			},
+			expectedEPP: []EPPStatus{
+				EPPStatusPendingDelete,
+				EPPStatusPendingRestore,
+				EPPStatusRedemptionPeriod,
+			},
+		},
+		{
+			description: "it should parse the values of the IANA registry",
+			values:      []string{"reserved", "administrative"},
+			expected: []Status{
+				StatusAdministrative,
+				StatusReserved,
+			},
+		},
+		{
+			description:   "it should fail for an unknown value",
+			values:        []string{"active", "clientTransferprohibited"},
+			expectedError: fmt.Errorf("unknown status “clientTransferprohibited”"),
+		},
+		{
+			description:   "it should fail for active and inactive",
+			values:        []string{"ok", "inactive"},
+			expectedError: fmt.Errorf("status “active” can't be combined with “inactive”"),
+		},
+		{
+			description:   "it should fail for two pending actions",
+			values:        []string{"pending update", "pendingCreate"},
+			expectedError: fmt.Errorf("status “pending create” can't be combined with “pending update”"),
+		},
+		{
+			description:   "it should fail for a pending action that is prohibited",
+			values:        []string{"pendingTransfer", "serverTransferProhibited"},
+			expectedError: fmt.Errorf("status “pending transfer” can't be combined with “server transfer prohibited”"),
+		},
+	}
+
+	for i, item := range data {
+		set, err := ParseStatusSet(item.values...)
+
+		if item.expectedError != nil {
+			if fmt.Sprintf("%v", item.expectedError) != fmt.Sprintf("%v", err) {
+				t.Errorf("[%d] %s: expected error “%v”, got “%v”", i, item.description, item.expectedError, err)
+			}
+			continue
+
+		} else if err != nil {
+			t.Errorf("[%d] %s: unexpected error “%s”", i, item.description, err)
+			continue
+		}
+
+		if list := set.List(); !reflect.DeepEqual(item.expected, list) {
+			t.Errorf("[%d] %s: unexpected status. Expected “%v” and got “%v”", i, item.description, item.expected, list)
+		}
+
+		if codes := set.EPP(); !reflect.DeepEqual(item.expectedEPP, codes) {
+			t.Errorf("[%d] %s: unexpected EPP codes. Expected “%v” and got “%v”", i, item.description, item.expectedEPP, codes)
+		}
+	}
+}
+
+func TestStatusSetJSON(t *testing.T) {
+	set := NewStatusSet(StatusServerHold, StatusActive, StatusServerHold)
+	set.Add(StatusLocked)
+	set.Remove(StatusLocked)
+
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("Unexpected error “%s”", err)
+	}
+
+	if expected := `["active","server hold"]`; string(data) != expected {
+		t.Errorf("Unexpected JSON. Expected “%s” and got “%s”", expected, string(data))
+	}
+
+	var parsed StatusSet
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("Unexpected error “%s”", err)
+	}
+
+	if !reflect.DeepEqual(set, parsed) {
+		t.Errorf("Unexpected status set “%v”", parsed)
+	}
+
+	if err := json.Unmarshal([]byte(`["ok","hold"]`), &parsed); fmt.Sprintf("%v", err) != "unknown status “hold”" {
+		t.Errorf("Unexpected error “%v”", err)
+	}
+}
@@ -0,0 +1,157 @@
+package protocol
+
+import "strings"
+
+// SubsettingIdentifier is the conformance value of the partial response
+// extension (RFC 8982, section 7), listed when the response contains the
+// subsetting_metadata member
+const SubsettingIdentifier = "subsetting"
+
+// FieldSet is the name of a subset of the object members, requested with the
+// fieldSet parameter of the searches (RFC 8982, section 2)
+type FieldSet string
+
+// List of field sets defined in RFC 8982, section 4
+const (
+	// FieldSetID contains only the identifiers of the objects: the handle of
+	// the entities and the ldhName (with the unicodeName of IDNs) of the domains
+	// and nameservers
+	FieldSetID FieldSet = "id"
+
+	// FieldSetBrief adds to the identifiers the members of a short
+	// description of the objects, like the status
+	FieldSetBrief FieldSet = "brief"
+
+	// FieldSetFull contains all the members of the objects
+	FieldSetFull FieldSet = "full"
+)
+
+// SubsettingMetadata describes the subsetting_metadata member of the
+// responses as it is in RFC 8982, section 3
+type SubsettingMetadata struct {
+	CurrentFieldSet    FieldSet            `json:"currentFieldSet"`
+	AvailableFieldSets []AvailableFieldSet `json:"availableFieldSets,omitempty"`
+}
+
+// AvailableFieldSet describes a field set supported by the server, with the
+// links to the results in this field set (RFC 8982, section 3)
+type AvailableFieldSet struct {
+	Name        FieldSet `json:"name"`
+	Default     bool     `json:"default"`
+	Description string   `json:"description,omitempty"`
+	Links       []Link   `json:"links,omitempty"`
+}
+
+// Subset returns a copy of the domain with only the members of the field set.
+// The objectClassName member and the self link are always kept (RFC 8982,
+// section 4). For the full or unknown field sets the domain itself is
+// returned
+func (d *Domain) Subset(fieldSet FieldSet) *Domain {
+	subset := Domain{
+		ObjectClassName: d.ObjectClassName,
+		LDHName:         d.LDHName,
+		UnicodeName:     d.UnicodeName,
+		Links:           selfLinks(d.Links),
+	}
+
+	switch fieldSet {
+	case FieldSetID:
+	case FieldSetBrief:
+		subset.Handle = d.Handle
+		subset.Status = d.Status
+	default:
+		return d
+	}
+
+	return &subset
+}
+
+// Subset returns a copy of the nameserver with only the members of the field
+// set, like Domain.Subset
+func (n *Nameserver) Subset(fieldSet FieldSet) *Nameserver {
+	subset := Nameserver{
+		ObjectClassName: n.ObjectClassName,
+		LDHName:         n.LDHName,
+		UnicodeName:     n.UnicodeName,
+		Links:           selfLinks(n.Links),
+	}
+
+	switch fieldSet {
+	case FieldSetID:
+	case FieldSetBrief:
+		subset.Handle = n.Handle
+		subset.Status = n.Status
+	default:
+		return n
+	}
+
+	return &subset
+}
+
+// Subset returns a copy of the entity with only the members of the field set,
+// like Domain.Subset. The brief field set keeps only the version and the
+// formatted name (fn) of the jCard
+func (e *Entity) Subset(fieldSet FieldSet) *Entity {
+	subset := Entity{
+		ObjectClassName: e.ObjectClassName,
+		Handle:          e.Handle,
+		Links:           selfLinks(e.Links),
+	}
+
+	switch fieldSet {
+	case FieldSetID:
+	case FieldSetBrief:
+		subset.VCardArray = e.vcardSubset("version", "fn")
+		subset.Roles = e.Roles
+		subset.Status = e.Status
+	default:
+		return e
+	}
+
+	return &subset
+}
+
+// vcardSubset returns a jCard with only the listed properties. When the
+// entity has none of them nil is returned
+func (e *Entity) vcardSubset(names ...string) []any {
+	if len(e.VCardArray) < 2 {
+		return nil
+	}
+
+	properties, ok := e.VCardArray[1].([]any)
+	if !ok {
+		return nil
+	}
+
+	var subset []any
+	for _, item := range properties {
+		values, ok := item.([]any)
+		if !ok || len(values) == 0 {
+			continue
+		}
+
+		name, _ := values[0].(string)
+		for _, n := range names {
+			if strings.EqualFold(name, n) {
+				subset = append(subset, item)
+				break
+			}
+		}
+	}
+
+	if len(subset) == 0 {
+		return nil
+	}
+	return []any{e.VCardArray[0], subset}
+}
+
+// selfLinks returns only the links with the "self" relation type
+func selfLinks(links []Link) []Link {
+	var self []Link
+	for _, link := range links {
+		if link.Rel == "self" {
+			self = append(self, link)
+		}
+	}
+	return self
+}
@@ -0,0 +1,194 @@
+package protocol
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestSearchResultsSubsettingMetadata(t *testing.T) {
+	// example adapted from RFC 8982, section 3
+	data := `{
+  "rdapConformance": ["rdap_level_0", "subsetting"],
+  "subsetting_metadata": {
+    "currentFieldSet": "brief",
+    "availableFieldSets": [
+      {
+        "name": "id",
+        "description": "Only the object identifiers",
+        "default": false,
+        "links": [
+          {
+            "value": "https://example.com/rdap/domains?name=example*.com&fieldSet=brief",
+            "rel": "alternate",
+            "href": "https://example.com/rdap/domains?name=example*.com&fieldSet=id",
+            "type": "application/rdap+json"
+          }
+        ]
+      }
+    ]
+  },
+  "domainSearchResults": [
+    {
+      "objectClassName": "domain",
+      "ldhName": "example1.com",
+      "status": ["active"]
+    }
+  ]
+}`
+
+	var results DomainSearchResults
+	if err := json.Unmarshal([]byte(data), &results); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := DomainSearchResults{
+		Domains: []Domain{{ObjectClassName: "domain", LDHName: "example1.com", Status: []Status{StatusActive}}},
+		SubsettingMetadata: &SubsettingMetadata{
+			CurrentFieldSet: FieldSetBrief,
+			AvailableFieldSets: []AvailableFieldSet{
+				{
+					Name:        FieldSetID,
+					Description: "Only the object identifiers",
+					Links: []Link{
+						{
+							Value: "https://example.com/rdap/domains?name=example*.com&fieldSet=brief",
+							Rel:   "alternate",
+							Href:  "https://example.com/rdap/domains?name=example*.com&fieldSet=id",
+							Type:  "application/rdap+json",
+						},
+					},
+				},
+			},
+		},
+		Conformance: Conformance{Levels: []string{"rdap_level_0", "subsetting"}},
+	}
+
+	if !reflect.DeepEqual(expected, results) {
+		t.Errorf("unexpected search results “%#v”", results)
+	}
+}
+
+func TestSubset(t *testing.T) {
+	links := []Link{
+		{Rel: "self", Href: "https://rdap.example.br/entity/XXX"},
+		{Rel: "related", Href: "https://rdap.example.com/entity/XXX"},
+	}
+
+	entity := &Entity{
+		ObjectClassName: "entity",
+		Handle:          "XXX",
+		VCardArray: []any{
+			"vcard",
+			[]any{
+				[]any{"version", map[string]any{}, "text", "4.0"},
+				[]any{"fn", map[string]any{}, "text", "Joe User"},
+				[]any{"email", map[string]any{}, "text", "joe@example.br"},
+			},
+		},
+		Roles:  []string{"registrant"},
+		Status: []Status{StatusActive},
+		Events: []Event{{Action: EventActionRegistration}},
+		Links:  links,
+	}
+
+	domain := &Domain{
+		ObjectClassName: "domain",
+		Handle:          "EXAMPLE-1",
+		LDHName:         "xn--exmpl-qqa.br",
+		UnicodeName:     "exâmpl.br",
+		Status:          []Status{StatusActive},
+		Entities:        []Entity{*entity},
+		Links:           links,
+	}
+
+	nameserver := &Nameserver{
+		ObjectClassName: "nameserver",
+		Handle:          "NS-1",
+		LDHName:         "a.dns.br",
+		Status:          []Status{StatusActive},
+		IPAddresses:     &IPAddresses{V4: []string{"192.0.2.1"}},
+	}
+
+	data := []struct {
+		description string
+		object      any
+		expected    any
+	}{
+		{
+			description: "it should keep only the identifiers of the domain",
+			object:      domain.Subset(FieldSetID),
+			expected: &Domain{
+				ObjectClassName: "domain",
+				LDHName:         "xn--exmpl-qqa.br",
+				UnicodeName:     "exâmpl.br",
+				Links:           links[:1],
+			},
+		},
+		{
+			description: "it should keep the brief members of the domain",
+			object:      domain.Subset(FieldSetBrief),
+			expected: &Domain{
+				ObjectClassName: "domain",
+				Handle:          "EXAMPLE-1",
+				LDHName:         "xn--exmpl-qqa.br",
+				UnicodeName:     "exâmpl.br",
+				Status:          []Status{StatusActive},
+				Links:           links[:1],
+			},
+		},
+		{
+			description: "it should keep the full domain",
+			object:      domain.Subset(FieldSetFull),
+			expected:    domain,
+		},
+		{
+			description: "it should keep the brief members of the nameserver",
+			object:      nameserver.Subset(FieldSetBrief),
+			expected: &Nameserver{
+				ObjectClassName: "nameserver",
+				Handle:          "NS-1",
+				LDHName:         "a.dns.br",
+				Status:          []Status{StatusActive},
+			},
+		},
+		{
+			description: "it should keep only the handle of the entity",
+			object:      entity.Subset(FieldSetID),
+			expected: &Entity{
+				ObjectClassName: "entity",
+				Handle:          "XXX",
+				Links:           links[:1],
+			},
+		},
+		{
+			description: "it should keep the name of the entity in the brief field set",
+			object:      entity.Subset(FieldSetBrief),
+			expected: &Entity{
+				ObjectClassName: "entity",
+				Handle:          "XXX",
+				VCardArray: []any{
+					"vcard",
+					[]any{
+						[]any{"version", map[string]any{}, "text", "4.0"},
+						[]any{"fn", map[string]any{}, "text", "Joe User"},
+					},
+				},
+				Roles:  []string{"registrant"},
+				Status: []Status{StatusActive},
+				Links:  links[:1],
+			},
+		},
+		{
+			description: "it should keep the entity with an unknown field set",
+			object:      entity.Subset("other"),
+			expected:    entity,
+		},
+	}
+
+	for i, item := range data {
+		if !reflect.DeepEqual(item.expected, item.object) {
+			t.Errorf("[%d] %s: expected “%#v”, got “%#v”", i, item.description, item.expected, item.object)
+		}
+	}
+}
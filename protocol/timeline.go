@@ -0,0 +1,275 @@
+package protocol
+
+import (
+	"slices"
+	"strconv"
+)
+
+// TimelineEvent is an event found in the object tree
+type TimelineEvent struct {
+	Event
+
+	// Path is the location of the event in the object, as a JSON pointer (RFC
+	// 6901) relative to the top-level object, like "/entities/0/events/1"
+	Path string
+}
+
+// Registration returns the earliest registration event of the domain
+func (d *Domain) Registration() (Event, bool) {
+	return firstEvent(d.Events, EventActionRegistration)
+}
+
+// Expiration returns the latest expiration event of the domain
+func (d *Domain) Expiration() (Event, bool) {
+	return lastEvent(d.Events, EventActionExpiration)
+}
+
+// LastChanged returns the latest last changed event of the domain
+func (d *Domain) LastChanged() (Event, bool) {
+	return lastEvent(d.Events, EventActionLastChanged)
+}
+
+// LastRDAPUpdate returns the latest event of the RDAP database update
+func (d *Domain) LastRDAPUpdate() (Event, bool) {
+	return lastEvent(d.Events, EventActionLastUpdate)
+}
+
+// EventsByAction returns the domain events with the given action sorted by
+// date. Events with the same date keep their original order
+func (d *Domain) EventsByAction(action EventAction) []Event {
+	return eventsByAction(d.Events, action)
+}
+
+// Timeline returns the events of the domain and of all nested objects,
+// including nameservers, entities, DS records and keys, sorted by date
+func (d *Domain) Timeline() []TimelineEvent {
+	return timeline(d)
+}
+
+// Registration returns the earliest registration event of the nameserver
+func (n *Nameserver) Registration() (Event, bool) {
+	return firstEvent(n.Events, EventActionRegistration)
+}
+
+// Expiration returns the latest expiration event of the nameserver
+func (n *Nameserver) Expiration() (Event, bool) {
+	return lastEvent(n.Events, EventActionExpiration)
+}
+
+// LastChanged returns the latest last changed event of the nameserver
+func (n *Nameserver) LastChanged() (Event, bool) {
+	return lastEvent(n.Events, EventActionLastChanged)
+}
+
+// LastRDAPUpdate returns the latest event of the RDAP database update
+func (n *Nameserver) LastRDAPUpdate() (Event, bool) {
+	return lastEvent(n.Events, EventActionLastUpdate)
+}
+
+// EventsByAction returns the nameserver events with the given action sorted by
+// date. Events with the same date keep their original order
+func (n *Nameserver) EventsByAction(action EventAction) []Event {
+	return eventsByAction(n.Events, action)
+}
+
+// Timeline returns the events of the nameserver and of all nested entities,
+// sorted by date
+func (n *Nameserver) Timeline() []TimelineEvent {
+	return timeline(n)
+}
+
+// Registration returns the earliest registration event of the entity
+func (e *Entity) Registration() (Event, bool) {
+	return firstEvent(e.Events, EventActionRegistration)
+}
+
+// Expiration returns the latest expiration event of the entity
+func (e *Entity) Expiration() (Event, bool) {
+	return lastEvent(e.Events, EventActionExpiration)
+}
+
+// LastChanged returns the latest last changed event of the entity
+func (e *Entity) LastChanged() (Event, bool) {
+	return lastEvent(e.Events, EventActionLastChanged)
+}
+
+// LastRDAPUpdate returns the latest event of the RDAP database update
+func (e *Entity) LastRDAPUpdate() (Event, bool) {
+	return lastEvent(e.Events, EventActionLastUpdate)
+}
+
+// EventsByAction returns the entity events with the given action sorted by
+// date. Events with the same date keep their original order
+func (e *Entity) EventsByAction(action EventAction) []Event {
+	return eventsByAction(e.Events, action)
+}
+
+// Timeline returns the events of the entity and of all nested objects, sorted
+// by date. The events listed in asEventActor have the entity handle as actor
+func (e *Entity) Timeline() []TimelineEvent {
+	return timeline(e)
+}
+
+// Registration returns the earliest registration event of the IP network
+func (i *IPNetwork) Registration() (Event, bool) {
+	return firstEvent(i.Events, EventActionRegistration)
+}
+
+// Expiration returns the latest expiration event of the IP network
+func (i *IPNetwork) Expiration() (Event, bool) {
+	return lastEvent(i.Events, EventActionExpiration)
+}
+
+// LastChanged returns the latest last changed event of the IP network
+func (i *IPNetwork) LastChanged() (Event, bool) {
+	return lastEvent(i.Events, EventActionLastChanged)
+}
+
+// LastRDAPUpdate returns the latest event of the RDAP database update
+func (i *IPNetwork) LastRDAPUpdate() (Event, bool) {
+	return lastEvent(i.Events, EventActionLastUpdate)
+}
+
+// EventsByAction returns the IP network events with the given action sorted by
+// date. Events with the same date keep their original order
+func (i *IPNetwork) EventsByAction(action EventAction) []Event {
+	return eventsByAction(i.Events, action)
+}
+
+// Timeline returns the events of the IP network and of all nested entities,
+// sorted by date
+func (i *IPNetwork) Timeline() []TimelineEvent {
+	return timeline(i)
+}
+
+// Registration returns the earliest registration event of the autonomous
+// system
+func (a *AS) Registration() (Event, bool) {
+	return firstEvent(a.Events, EventActionRegistration)
+}
+
+// Expiration returns the latest expiration event of the autonomous system
+func (a *AS) Expiration() (Event, bool) {
+	return lastEvent(a.Events, EventActionExpiration)
+}
+
+// LastChanged returns the latest last changed event of the autonomous system
+func (a *AS) LastChanged() (Event, bool) {
+	return lastEvent(a.Events, EventActionLastChanged)
+}
+
+// LastRDAPUpdate returns the latest event of the RDAP database update
+func (a *AS) LastRDAPUpdate() (Event, bool) {
+	return lastEvent(a.Events, EventActionLastUpdate)
+}
+
+// EventsByAction returns the autonomous system events with the given action
+// sorted by date. Events with the same date keep their original order
+func (a *AS) EventsByAction(action EventAction) []Event {
+	return eventsByAction(a.Events, action)
+}
+
+// Timeline returns the events of the autonomous system and of all nested
+// entities, sorted by date
+func (a *AS) Timeline() []TimelineEvent {
+	return timeline(a)
+}
+
+func eventsByAction(events []Event, action EventAction) []Event {
+	var found []Event
+	for _, event := range events {
+		if event.Action == action {
+			found = append(found, event)
+		}
+	}
+
+	slices.SortStableFunc(found, func(a, b Event) int {
+		return a.Date.Compare(b.Date.Time)
+	})
+
+	return found
+}
+
+// firstEvent returns the earliest event with the action. If more than one
+// event has the same date, the first one in the list is returned
+func firstEvent(events []Event, action EventAction) (Event, bool) {
+	found := eventsByAction(events, action)
+	if len(found) == 0 {
+		return Event{}, false
+	}
+
+	return found[0], true
+}
+
+// lastEvent returns the latest event with the action. If more than one event
+// has the same date, the last one in the list is returned
+func lastEvent(events []Event, action EventAction) (Event, bool) {
+	found := eventsByAction(events, action)
+	if len(found) == 0 {
+		return Event{}, false
+	}
+
+	return found[len(found)-1], true
+}
+
+func timeline(object Object) []TimelineEvent {
+	var events []TimelineEvent
+
+	add := func(path string, object Object) {
+		for i, event := range object.GetEvents() {
+			events = append(events, TimelineEvent{
+				Event: event,
+				Path:  path + "/events/" + strconv.Itoa(i),
+			})
+		}
+
+		switch o := object.(type) {
+		case *Entity:
+			for i, event := range o.AsEventActor {
+				if event.Actor == "" {
+					event.Actor = o.Handle
+				}
+
+				events = append(events, TimelineEvent{
+					Event: event,
+					Path:  path + "/asEventActor/" + strconv.Itoa(i),
+				})
+			}
+
+		case *Domain:
+			if o.SecureDNS == nil {
+				break
+			}
+
+			for i, ds := range o.SecureDNS.DSData {
+				for j, event := range ds.Events {
+					events = append(events, TimelineEvent{
+						Event: event,
+						Path:  path + "/secureDNS/dsData/" + strconv.Itoa(i) + "/events/" + strconv.Itoa(j),
+					})
+				}
+			}
+
+			for i, keyData := range o.SecureDNS.KeyData {
+				for j, event := range keyData.Events {
+					events = append(events, TimelineEvent{
+						Event: event,
+						Path:  path + "/secureDNS/keyData/" + strconv.Itoa(i) + "/events/" + strconv.Itoa(j),
+					})
+				}
+			}
+		}
+	}
+
+	add("", object)
+	walk(object, "", func(path string, child Object) bool {
+		add(path, child)
+		return true
+	})
+
+	slices.SortStableFunc(events, func(a, b TimelineEvent) int {
+		return a.Date.Compare(b.Date.Time)
+	})
+
+	return events
+}
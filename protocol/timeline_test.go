@@ -0,0 +1,134 @@
+package protocol
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDomainEventLookup(t *testing.T) {
+	day := func(d int) EventDate {
+		return Date(2020, time.January, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	domain := Domain{
+		Events: []Event{
+			{Action: EventActionRegistration, Date: day(5), Actor: "B"},
+			{Action: EventActionExpiration, Date: day(20)},
+			{Action: EventActionRegistration, Date: day(2)},
+			{Action: EventActionLastChanged, Date: day(10), Actor: "A"},
+			{Action: EventActionLastChanged, Date: day(10), Actor: "B"},
+			{Action: EventActionExpiration, Date: day(25)},
+			{Action: EventActionRegistration, Date: day(2), Actor: "C"},
+		},
+	}
+
+	data := []struct {
+		description   string
+		lookup        func() (Event, bool)
+		expected      Event
+		expectedFound bool
+	}{
+		{
+			description:   "it should return the earliest registration",
+			lookup:        domain.Registration,
+			expected:      Event{Action: EventActionRegistration, Date: day(2)},
+			expectedFound: true,
+		},
+		{
+			description:   "it should return the latest expiration",
+			lookup:        domain.Expiration,
+			expected:      Event{Action: EventActionExpiration, Date: day(25)},
+			expectedFound: true,
+		},
+		{
+			description:   "it should return the last event in the list for the same date",
+			lookup:        domain.LastChanged,
+			expected:      Event{Action: EventActionLastChanged, Date: day(10), Actor: "B"},
+			expectedFound: true,
+		},
+		{
+			description: "it should not find the event",
+			lookup:      domain.LastRDAPUpdate,
+		},
+	}
+
+	for i, item := range data {
+		event, found := item.lookup()
+
+		if found != item.expectedFound {
+			t.Errorf("[%d] %s: expected found “%t”", i, item.description, item.expectedFound)
+		}
+
+		if !reflect.DeepEqual(item.expected, event) {
+			t.Errorf("[%d] %s: unexpected event. Expected “%#v” and got “%#v”", i, item.description, item.expected, event)
+		}
+	}
+
+	expected := []Event{
+		{Action: EventActionRegistration, Date: day(2)},
+		{Action: EventActionRegistration, Date: day(2), Actor: "C"},
+		{Action: EventActionRegistration, Date: day(5), Actor: "B"},
+	}
+
+	if events := domain.EventsByAction(EventActionRegistration); !reflect.DeepEqual(expected, events) {
+		t.Errorf("Unexpected events. Expected “%#v” and got “%#v”", expected, events)
+	}
+}
+
+func TestDomainTimeline(t *testing.T) {
+	day := func(d int) EventDate {
+		return Date(2020, time.January, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	domain := Domain{
+		Events: []Event{
+			{Action: EventActionLastChanged, Date: day(9)},
+			{Action: EventActionRegistration, Date: day(1)},
+		},
+		Nameservers: []Nameserver{
+			{
+				LDHName: "a.dns.br",
+				Events: []Event{
+					{Action: EventDelegationCheck, Date: day(8)},
+				},
+			},
+		},
+		SecureDNS: &SecureDNS{
+			DSData: []DS{
+				{Events: []Event{{Action: EventDelegationSignCheck, Date: day(7)}}},
+			},
+		},
+		Entities: []Entity{
+			{
+				Handle: "REGISTRAR",
+				AsEventActor: []Event{
+					{Action: EventActionTransfer, Date: day(5)},
+				},
+				Entities: []Entity{
+					{
+						Handle: "CONTACT",
+						Events: []Event{{Action: EventActionRegistration, Date: day(1)}},
+					},
+				},
+			},
+		},
+	}
+
+	expected := []TimelineEvent{
+		{Event: Event{Action: EventActionRegistration, Date: day(1)}, Path: "/events/1"},
+		{Event: Event{Action: EventActionRegistration, Date: day(1)}, Path: "/entities/0/entities/0/events/0"},
+		{Event: Event{Action: EventActionTransfer, Date: day(5), Actor: "REGISTRAR"}, Path: "/entities/0/asEventActor/0"},
+		{Event: Event{Action: EventDelegationSignCheck, Date: day(7)}, Path: "/secureDNS/dsData/0/events/0"},
+		{Event: Event{Action: EventDelegationCheck, Date: day(8)}, Path: "/nameservers/0/events/0"},
+		{Event: Event{Action: EventActionLastChanged, Date: day(9)}, Path: "/events/0"},
+	}
+
+	if timeline := domain.Timeline(); !reflect.DeepEqual(expected, timeline) {
+		t.Errorf("Unexpected timeline. Expected “%#v” and got “%#v”", expected, timeline)
+	}
+
+	if actor := domain.Entities[0].AsEventActor[0].Actor; actor != "" {
+		t.Errorf("Timeline changed the original event actor to “%s”", actor)
+	}
+}
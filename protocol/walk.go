@@ -0,0 +1,162 @@
+package protocol
+
+import (
+	"iter"
+	"slices"
+	"strconv"
+)
+
+// EntityMatch stores an entity found in the object tree
+type EntityMatch struct {
+	// Entity points to the entity inside the object tree
+	Entity *Entity
+
+	// Path is the location of the entity in the object, as a JSON pointer
+	// (RFC 6901) relative to the top-level object, like "/entities/0/entities/1"
+	Path string
+}
+
+// FindEntities returns all entities with the given role in the domain object
+// tree, including the ones inside nameservers and the network, in depth-first
+// order
+func (d *Domain) FindEntities(role Role) []EntityMatch {
+	return findEntities(d, role)
+}
+
+// FindEntities returns all entities with the given role in the entity object
+// tree, in depth-first order. The entity itself is not checked
+func (e *Entity) FindEntities(role Role) []EntityMatch {
+	return findEntities(e, role)
+}
+
+// FindEntities returns all entities with the given role in the IP network
+// object tree, in depth-first order
+func (i *IPNetwork) FindEntities(role Role) []EntityMatch {
+	return findEntities(i, role)
+}
+
+// FindEntities returns all entities with the given role in the autonomous
+// system object tree, in depth-first order
+func (a *AS) FindEntities(role Role) []EntityMatch {
+	return findEntities(a, role)
+}
+
+// FindEntities returns all entities with the given role in the nameserver
+// object tree, in depth-first order
+func (n *Nameserver) FindEntities(role Role) []EntityMatch {
+	return findEntities(n, role)
+}
+
+// Nested iterates over all objects inside the domain (nameservers, entities
+// and network), in depth-first order. The domain itself is not included
+func (d *Domain) Nested() iter.Seq[Object] {
+	return nested(d)
+}
+
+// Nested iterates over all objects inside the entity (entities, networks and
+// autnums), in depth-first order. The entity itself is not included
+func (e *Entity) Nested() iter.Seq[Object] {
+	return nested(e)
+}
+
+// Nested iterates over all objects inside the IP network, in depth-first
+// order. The IP network itself is not included
+func (i *IPNetwork) Nested() iter.Seq[Object] {
+	return nested(i)
+}
+
+// Nested iterates over all objects inside the autonomous system, in
+// depth-first order. The autonomous system itself is not included
+func (a *AS) Nested() iter.Seq[Object] {
+	return nested(a)
+}
+
+// Nested iterates over all objects inside the nameserver, in depth-first
+// order. The nameserver itself is not included
+func (n *Nameserver) Nested() iter.Seq[Object] {
+	return nested(n)
+}
+
+func findEntities(object Object, role Role) []EntityMatch {
+	var matches []EntityMatch
+
+	walk(object, "", func(path string, child Object) bool {
+		if entity, ok := child.(*Entity); ok && slices.Contains(entity.Roles, string(role)) {
+			matches = append(matches, EntityMatch{
+				Entity: entity,
+				Path:   path,
+			})
+		}
+		return true
+	})
+
+	return matches
+}
+
+func nested(object Object) iter.Seq[Object] {
+	return func(yield func(Object) bool) {
+		walk(object, "", func(path string, child Object) bool {
+			return yield(child)
+		})
+	}
+}
+
+// walk visits the children of the object recursively, informing the JSON
+// pointer of each one. It returns false when the visit function stops the walk
+func walk(object Object, path string, visit func(path string, child Object) bool) bool {
+	var ok = true
+
+	child := func(name string, index int, object Object) {
+		if !ok {
+			return
+		}
+
+		childPath := path + "/" + name
+		if index >= 0 {
+			childPath += "/" + strconv.Itoa(index)
+		}
+
+		ok = visit(childPath, object) && walk(object, childPath, visit)
+	}
+
+	switch o := object.(type) {
+	case *Domain:
+		for i := range o.Nameservers {
+			child("nameservers", i, &o.Nameservers[i])
+		}
+		for i := range o.Entities {
+			child("entities", i, &o.Entities[i])
+		}
+		if o.Network != nil {
+			child("network", -1, o.Network)
+		}
+
+	case *Entity:
+		for i := range o.Entities {
+			child("entities", i, &o.Entities[i])
+		}
+		for i := range o.Networks {
+			child("networks", i, &o.Networks[i])
+		}
+		for i := range o.Autnums {
+			child("autnums", i, &o.Autnums[i])
+		}
+
+	case *IPNetwork:
+		for i := range o.Entities {
+			child("entities", i, &o.Entities[i])
+		}
+
+	case *AS:
+		for i := range o.Entities {
+			child("entities", i, &o.Entities[i])
+		}
+
+	case *Nameserver:
+		for i := range o.Entities {
+			child("entities", i, &o.Entities[i])
+		}
+	}
+
+	return ok
+}
@@ -0,0 +1,169 @@
+package protocol
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDomainFindEntities(t *testing.T) {
+	domain := Domain{
+		ObjectClassName: "domain",
+		Entities: []Entity{
+			{
+				Handle: "REGISTRANT",
+				Roles:  []string{"registrant"},
+				Entities: []Entity{
+					{Handle: "ABUSE-1", Roles: []string{"abuse", "technical"}},
+				},
+			},
+			{
+				Handle: "REGISTRAR",
+				Roles:  []string{"registrar"},
+				Entities: []Entity{
+					{Handle: "ABUSE-2", Roles: []string{"abuse"}},
+				},
+			},
+		},
+		Nameservers: []Nameserver{
+			{
+				LDHName: "a.dns.br",
+				Entities: []Entity{
+					{Handle: "TECH", Roles: []string{"technical"}},
+				},
+			},
+		},
+		Network: &IPNetwork{
+			Entities: []Entity{
+				{Handle: "ABUSE-3", Roles: []string{"abuse"}},
+			},
+		},
+	}
+
+	data := []struct {
+		description string
+		role        Role
+		expected    []string
+	}{
+		{
+			description: "it should find entities at any depth",
+			role:        RoleAbuse,
+			expected: []string{
+				"ABUSE-1 /entities/0/entities/0",
+				"ABUSE-2 /entities/1/entities/0",
+				"ABUSE-3 /network/entities/0",
+			},
+		},
+		{
+			description: "it should find entities inside nameservers",
+			role:        RoleTechnical,
+			expected: []string{
+				"TECH /nameservers/0/entities/0",
+				"ABUSE-1 /entities/0/entities/0",
+			},
+		},
+		{
+			description: "it should find direct children",
+			role:        RoleRegistrar,
+			expected: []string{
+				"REGISTRAR /entities/1",
+			},
+		},
+		{
+			description: "it should not find anything",
+			role:        RoleNOC,
+		},
+	}
+
+	for i, item := range data {
+		var found []string
+		for _, match := range domain.FindEntities(item.role) {
+			found = append(found, match.Entity.Handle+" "+match.Path)
+		}
+
+		if !reflect.DeepEqual(item.expected, found) {
+			t.Errorf("[%d] %s: unexpected entities. Expected “%v” and got “%v”", i, item.description, item.expected, found)
+		}
+	}
+
+	// the matches must point to the entities inside the object tree
+	domain.FindEntities(RoleRegistrar)[0].Entity.Handle = "CHANGED"
+	if domain.Entities[1].Handle != "CHANGED" {
+		t.Error("Match doesn't point to the entity in the object tree")
+	}
+}
+
+func TestEntityNested(t *testing.T) {
+	entity := Entity{
+		Handle: "ROOT",
+		Entities: []Entity{
+			{
+				Handle: "CHILD",
+				Networks: []IPNetwork{
+					{
+						Handle: "192.0.2.0/24",
+						Entities: []Entity{
+							{Handle: "NETWORK-CHILD"},
+						},
+					},
+				},
+			},
+		},
+		Autnums: []AS{
+			{
+				Handle: "AS64496",
+				Entities: []Entity{
+					{Handle: "AS-CHILD", Roles: []string{"noc"}},
+				},
+			},
+		},
+	}
+
+	var handles []string
+	for object := range entity.Nested() {
+		handles = append(handles, object.GetHandle())
+	}
+
+	expected := []string{"CHILD", "192.0.2.0/24", "NETWORK-CHILD", "AS64496", "AS-CHILD"}
+	if !reflect.DeepEqual(expected, handles) {
+		t.Errorf("Unexpected nested objects. Expected “%v” and got “%v”", expected, handles)
+	}
+
+	handles = nil
+	for object := range entity.Nested() {
+		handles = append(handles, object.GetHandle())
+		if len(handles) == 2 {
+			break
+		}
+	}
+
+	if expected := []string{"CHILD", "192.0.2.0/24"}; !reflect.DeepEqual(expected, handles) {
+		t.Errorf("Unexpected nested objects after break. Expected “%v” and got “%v”", expected, handles)
+	}
+
+	matches := entity.FindEntities(RoleNOC)
+	if len(matches) != 1 || matches[0].Path != "/autnums/0/entities/0" {
+		t.Errorf("Unexpected matches “%#v”", matches)
+	}
+}
+
+func TestNestedWithoutChildren(t *testing.T) {
+	objects := []interface {
+		Object
+		FindEntities(Role) []EntityMatch
+	}{
+		&IPNetwork{Entities: []Entity{{Handle: "A", Roles: []string{"abuse"}}}},
+		&AS{Entities: []Entity{{Handle: "A", Roles: []string{"abuse"}}}},
+		&Nameserver{Entities: []Entity{{Handle: "A", Roles: []string{"abuse"}}}},
+	}
+
+	for i, object := range objects {
+		matches := object.FindEntities(RoleAbuse)
+		if len(matches) != 1 || matches[0].Entity.Handle != "A" || matches[0].Path != "/entities/0" {
+			t.Errorf("[%d] unexpected matches “%#v”", i, matches)
+		}
+	}
+
+	for range (&Domain{}).Nested() {
+		t.Error("Unexpected nested object in an empty domain")
+	}
+}
@@ -0,0 +1,111 @@
+package rdaptest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+// List of registries of the bootstrap service (RFC 9224, section 3)
+const (
+	// RegistryDNS lists the RDAP servers of the top-level domains
+	RegistryDNS Registry = "dns"
+
+	// RegistryASN lists the RDAP servers of the ASN ranges, like "64496-64511"
+	RegistryASN Registry = "asn"
+
+	// RegistryIPv4 lists the RDAP servers of the IPv4 prefixes
+	RegistryIPv4 Registry = "ipv4"
+
+	// RegistryIPv6 lists the RDAP servers of the IPv6 prefixes
+	RegistryIPv6 Registry = "ipv6"
+)
+
+// Registry identifies a bootstrap registry file
+type Registry string
+
+// Bootstrap is a fake IANA bootstrap service. The registries are served in
+// the path "/{registry}.json", so the URI used by the client is returned by
+// the URI method
+type Bootstrap struct {
+	*httptest.Server
+
+	lock     sync.Mutex
+	services map[Registry][][2][]string
+	requests []Request
+}
+
+// NewBootstrap starts a fake bootstrap service with empty registries. It must
+// be closed by the caller
+func NewBootstrap() *Bootstrap {
+	b := &Bootstrap{
+		services: make(map[Registry][][2][]string),
+	}
+	b.Server = httptest.NewServer(http.HandlerFunc(b.serveHTTP))
+	return b
+}
+
+// URI returns the bootstrap URI in the format expected by the client (like
+// rdap.IANABootstrap), with "%s" in the place of the registry name
+func (b *Bootstrap) URI() string {
+	return b.URL + "/%s.json"
+}
+
+// Add lists the RDAP servers of the entries in the registry. The entries are
+// top-level domains, ASN ranges or IP prefixes, according to the registry
+func (b *Bootstrap) Add(registry Registry, entries []string, servers ...string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.services[registry] = append(b.services[registry], [2][]string{entries, servers})
+}
+
+// Requests returns the requests received, in order
+func (b *Bootstrap) Requests() []Request {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return append([]Request(nil), b.requests...)
+}
+
+func (b *Bootstrap) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.requests = append(b.requests, newRequest(r))
+
+	name, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/"), ".json")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	registry := Registry(name)
+	switch registry {
+	case RegistryDNS, RegistryASN, RegistryIPv4, RegistryIPv6:
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	services := b.services[registry]
+	if services == nil {
+		services = [][2][]string{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		Version     string        `json:"version"`
+		Publication time.Time     `json:"publication"`
+		Description string        `json:"description"`
+		Services    [][2][]string `json:"services"`
+	}{
+		Version:     "1.0",
+		Publication: time.Now().UTC().Truncate(time.Second),
+		Description: "RDAP bootstrap registry for tests",
+		Services:    services,
+	})
+}
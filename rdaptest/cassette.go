@@ -0,0 +1,437 @@
+package rdaptest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// RecordEnv is the environment variable that makes RecordOrReplay record new
+// cassettes instead of replaying them
+const RecordEnv = "RDAPTEST_RECORD"
+
+// Redacted replaces the values of the sensitive HTTP headers and query string
+// parameters in the cassettes
+const Redacted = "REDACTED"
+
+// DefaultRedactedHeaders lists the HTTP headers that are redacted when the
+// recorder doesn't define its own list
+var DefaultRedactedHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+}
+
+// DefaultRedactedParameters lists the query string parameters that are
+// redacted when the recorder doesn't define its own list
+var DefaultRedactedParameters = []string{
+	"apikey",
+	"api_key",
+	"access_token",
+	"token",
+}
+
+// HTTPClient is the interface used by the RDAP transport layers to send the
+// HTTP requests, implemented by *http.Client
+type HTTPClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// Cassette stores the recorded HTTP exchanges, including the bootstrap
+// registries downloads
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and its response
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest stores the request data used for matching
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+}
+
+// RecordedResponse stores the response replayed to the client. The body is
+// stored as text, as RDAP responses are UTF-8 JSON documents
+type RecordedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body"`
+}
+
+// LoadCassette reads the cassette file
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("invalid cassette “%s”: %w", path, err)
+	}
+	return &cassette, nil
+}
+
+// Save writes the cassette file
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Recorder is an HTTP client that sends the requests with the wrapped client
+// and records the exchanges in a cassette. Network errors aren't recorded
+type Recorder struct {
+	// Client sends the requests
+	Client HTTPClient
+
+	// RedactedHeaders lists the request and response headers whose values are
+	// replaced by Redacted in the cassette. When nil DefaultRedactedHeaders is
+	// used
+	RedactedHeaders []string
+
+	// RedactedParameters lists the query string parameters, compared without
+	// case, whose values are replaced by Redacted in the cassette. When nil
+	// DefaultRedactedParameters is used
+	RedactedParameters []string
+
+	lock     sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder returns a recorder that sends the requests with the client
+func NewRecorder(client HTTPClient) *Recorder {
+	return &Recorder{
+		Client: client,
+	}
+}
+
+// Do implements the HTTPClient interface
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	redacted := r.RedactedHeaders
+	if redacted == nil {
+		redacted = DefaultRedactedHeaders
+	}
+
+	redactedParameters := r.RedactedParameters
+	if redactedParameters == nil {
+		redactedParameters = DefaultRedactedParameters
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    redactQuery(req.URL, redactedParameters),
+			Header: redact(req.Header, redacted),
+		},
+		Response: RecordedResponse{
+			Status: resp.StatusCode,
+			Header: redact(resp.Header, redacted),
+			Body:   string(body),
+		},
+	})
+
+	return resp, nil
+}
+
+// Cassette returns a copy of the recorded exchanges
+func (r *Recorder) Cassette() *Cassette {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return &Cassette{
+		Interactions: slices.Clone(r.cassette.Interactions),
+	}
+}
+
+// Save writes the recorded exchanges in the cassette file
+func (r *Recorder) Save(path string) error {
+	return r.Cassette().Save(path)
+}
+
+func redact(header http.Header, redacted []string) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+
+	header = header.Clone()
+	for _, key := range redacted {
+		if values, ok := header[http.CanonicalHeaderKey(key)]; ok {
+			for i := range values {
+				values[i] = Redacted
+			}
+		}
+	}
+	return header
+}
+
+// redactQuery returns the URL with the values of the sensitive query string
+// parameters replaced, keeping the order of the parameters
+func redactQuery(u *url.URL, redacted []string) string {
+	if u.RawQuery == "" {
+		return u.String()
+	}
+
+	parameters := strings.Split(u.RawQuery, "&")
+	for i, parameter := range parameters {
+		key, _, _ := strings.Cut(parameter, "=")
+		name, err := url.QueryUnescape(key)
+		if err != nil {
+			name = key
+		}
+
+		if slices.ContainsFunc(redacted, func(r string) bool { return strings.EqualFold(r, name) }) {
+			parameters[i] = key + "=" + Redacted
+		}
+	}
+
+	redactedURL := *u
+	redactedURL.RawQuery = strings.Join(parameters, "&")
+	return redactedURL.String()
+}
+
+// List of request matching modes of the replayer
+const (
+	// MatchStrict requires the same method, URL (including the query string
+	// order) and the recorded headers that weren't redacted. Redacted query
+	// string parameters match any value. The interactions are replayed in the
+	// recorded order, each one only once
+	MatchStrict MatchMode = iota
+
+	// MatchLenient requires only the same method, URL path and query string
+	// parameters in any order, where the redacted ones match any value. The
+	// host is ignored, so the cassette can be
+	// replayed against servers with other addresses, like the ephemeral ones of
+	// this package. The interactions can be replayed many times, in any order
+	MatchLenient
+)
+
+// MatchMode defines how the replayer finds the recorded interaction of a
+// request
+type MatchMode int
+
+// UnrecordedRequestError is returned by the replayer when there's no recorded
+// interaction for the request
+type UnrecordedRequestError struct {
+	Method string
+	URL    string
+	Reason string
+}
+
+// Error implements the error interface
+func (u *UnrecordedRequestError) Error() string {
+	return fmt.Sprintf("rdaptest: unrecorded request %s %s: %s", u.Method, u.URL, u.Reason)
+}
+
+// Replayer is an HTTP client that answers the requests with the interactions
+// of a cassette, without network access
+type Replayer struct {
+	Cassette *Cassette
+	Mode     MatchMode
+
+	lock sync.Mutex
+	next int
+}
+
+// NewReplayer returns a replayer of the cassette
+func NewReplayer(cassette *Cassette, mode MatchMode) *Replayer {
+	return &Replayer{
+		Cassette: cassette,
+		Mode:     mode,
+	}
+}
+
+// Do implements the HTTPClient interface. It fails with an
+// UnrecordedRequestError when the request doesn't match
+func (r *Replayer) Do(req *http.Request) (*http.Response, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var interaction Interaction
+
+	switch r.Mode {
+	case MatchStrict:
+		if r.next >= len(r.Cassette.Interactions) {
+			return nil, &UnrecordedRequestError{
+				Method: req.Method,
+				URL:    req.URL.String(),
+				Reason: fmt.Sprintf("all %d interactions were already replayed", len(r.Cassette.Interactions)),
+			}
+		}
+
+		interaction = r.Cassette.Interactions[r.next]
+		if reason := strictMismatch(interaction.Request, req); reason != "" {
+			return nil, &UnrecordedRequestError{
+				Method: req.Method,
+				URL:    req.URL.String(),
+				Reason: fmt.Sprintf("interaction %d doesn't match: %s", r.next, reason),
+			}
+		}
+		r.next++
+
+	default:
+		index := slices.IndexFunc(r.Cassette.Interactions, func(interaction Interaction) bool {
+			return lenientMatch(interaction.Request, req)
+		})
+		if index < 0 {
+			return nil, &UnrecordedRequestError{
+				Method: req.Method,
+				URL:    req.URL.String(),
+				Reason: "no interaction with the same method, path and query string",
+			}
+		}
+		interaction = r.Cassette.Interactions[index]
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.Status, http.StatusText(interaction.Response.Status)),
+		StatusCode:    interaction.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        interaction.Response.Header.Clone(),
+		Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+		ContentLength: int64(len(interaction.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// Remaining returns the number of interactions not replayed yet in strict
+// mode, useful to check that all expected requests were sent
+func (r *Replayer) Remaining() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.Mode != MatchStrict {
+		return 0
+	}
+	return len(r.Cassette.Interactions) - r.next
+}
+
+func strictMismatch(recorded RecordedRequest, req *http.Request) string {
+	if recorded.Method != req.Method {
+		return fmt.Sprintf("expected method %s", recorded.Method)
+	}
+
+	if !strictURLMatch(recorded.URL, req.URL) {
+		return fmt.Sprintf("expected URL %s", recorded.URL)
+	}
+
+	for key, values := range recorded.Header {
+		if slices.Contains(values, Redacted) {
+			continue
+		}
+		if !slices.Equal(values, req.Header.Values(key)) {
+			return fmt.Sprintf("expected HTTP header %s “%s”", key, strings.Join(values, ", "))
+		}
+	}
+
+	return ""
+}
+
+func lenientMatch(recorded RecordedRequest, req *http.Request) bool {
+	if recorded.Method != req.Method {
+		return false
+	}
+
+	recordedURL, err := url.Parse(recorded.URL)
+	if err != nil {
+		return false
+	}
+
+	if strings.TrimSuffix(recordedURL.Path, "/") != strings.TrimSuffix(req.URL.Path, "/") {
+		return false
+	}
+
+	recordedQuery, query := recordedURL.Query(), req.URL.Query()
+	if len(recordedQuery) != len(query) {
+		return false
+	}
+
+	for key, values := range recordedQuery {
+		if !slices.EqualFunc(values, query[key], matchValue) {
+			return false
+		}
+	}
+	return true
+}
+
+// strictURLMatch compares the URLs with the query string parameters in the
+// same order
+func strictURLMatch(recorded string, u *url.URL) bool {
+	recordedBase, recordedQuery, _ := strings.Cut(recorded, "?")
+	base, query, _ := strings.Cut(u.String(), "?")
+	if recordedBase != base {
+		return false
+	}
+
+	return slices.EqualFunc(strings.Split(recordedQuery, "&"), strings.Split(query, "&"), func(recordedParameter, parameter string) bool {
+		recordedKey, recordedValue, _ := strings.Cut(recordedParameter, "=")
+		key, value, _ := strings.Cut(parameter, "=")
+		return recordedKey == key && matchValue(recordedValue, value)
+	})
+}
+
+// matchValue checks if the value is the recorded one, or if the recorded value
+// was redacted
+func matchValue(recorded, value string) bool {
+	return recorded == value || recorded == Redacted
+}
+
+// RecordOrReplay returns the HTTP client of an integration test. When the
+// environment variable RecordEnv is set, the requests are sent with the client
+// and the cassette file is written at the end of the test. Otherwise the
+// cassette is replayed in the given mode and the test fails if the file can't
+// be read
+func RecordOrReplay(t testing.TB, path string, client HTTPClient, mode MatchMode) HTTPClient {
+	t.Helper()
+
+	if os.Getenv(RecordEnv) != "" {
+		recorder := NewRecorder(client)
+		t.Cleanup(func() {
+			if err := recorder.Save(path); err != nil {
+				t.Errorf("failed to save cassette: %s", err)
+			}
+		})
+		return recorder
+	}
+
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("failed to load cassette (set %s to record it): %s", RecordEnv, err)
+	}
+	return NewReplayer(cassette, mode)
+}
@@ -0,0 +1,176 @@
+package rdaptest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/registrobr/rdap"
+	"github.com/registrobr/rdap/protocol"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	s := NewServer()
+	s.Object("/domain/example.br", &protocol.Domain{ObjectClassName: "domain", LDHName: "example.br"})
+	s.Handle("/domain/cookie.br", Response{
+		Object: &protocol.Domain{ObjectClassName: "domain", LDHName: "cookie.br"},
+		Header: http.Header{"Set-Cookie": []string{"session=secret"}},
+	})
+
+	b := NewBootstrap()
+	b.Add(RegistryDNS, []string{"br"}, s.URL)
+
+	bootstrapURI := b.URI()
+	header := http.Header{"Authorization": []string{"Bearer secret"}}
+
+	recorder := NewRecorder(http.DefaultClient)
+	client := rdap.Client{
+		Transport: rdap.NewBootstrapFetcher(recorder, bootstrapURI, nil),
+	}
+
+	if _, _, err := client.Domain("example.br", header, url.Values{"a": []string{"1"}, "b": []string{"2"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := client.Domain("cookie.br", header, url.Values{"apikey": []string{"secret"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	s.Close()
+	b.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	if err := recorder.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count := len(cassette.Interactions); count != 4 {
+		t.Fatalf("expected 4 interactions (2 bootstrap downloads), got %d", count)
+	}
+
+	if value := cassette.Interactions[1].Request.Header.Get("Authorization"); value != Redacted {
+		t.Errorf("the request header wasn't redacted: “%s”", value)
+	}
+
+	if value := cassette.Interactions[3].Response.Header.Get("Set-Cookie"); value != Redacted {
+		t.Errorf("the response header wasn't redacted: “%s”", value)
+	}
+
+	if value := cassette.Interactions[3].Request.URL; value != s.URL+"/domain/cookie.br?apikey="+Redacted {
+		t.Errorf("the query string parameter wasn't redacted: “%s”", value)
+	}
+
+	// strict replay in the recorded order
+	replayer := NewReplayer(cassette, MatchStrict)
+	client.Transport = rdap.NewBootstrapFetcher(replayer, bootstrapURI, nil)
+
+	domain, _, err := client.Domain("example.br", header, url.Values{"a": []string{"1"}, "b": []string{"2"}})
+	if err != nil || domain.LDHName != "example.br" {
+		t.Errorf("unexpected replayed domain “%v” (%v)", domain, err)
+	}
+
+	_, _, err = client.Domain("example.br", header, nil)
+	expected := fmt.Sprintf("rdaptest: unrecorded request GET %s/domain/example.br: interaction 3 doesn't match: expected URL %s/domain/cookie.br?apikey=%s", s.URL, s.URL, Redacted)
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error “%s”, got “%v”", expected, err)
+	}
+
+	var unrecorded *UnrecordedRequestError
+	if !errors.As(err, &unrecorded) {
+		t.Errorf("unexpected error type %T", err)
+	}
+
+	if remaining := replayer.Remaining(); remaining != 1 {
+		t.Errorf("expected 1 remaining interaction, got %d", remaining)
+	}
+
+	// the redacted parameter matches any value. The bootstrap download was
+	// already replayed by the previous query
+	direct := rdap.Client{
+		URIs:      []string{s.URL},
+		Transport: rdap.NewDefaultFetcher(replayer),
+	}
+	domain, _, err = direct.Domain("cookie.br", header, url.Values{"apikey": []string{"other"}})
+	if err != nil || domain.LDHName != "cookie.br" || replayer.Remaining() != 0 {
+		t.Errorf("unexpected replayed domain “%v” (%v)", domain, err)
+	}
+
+	// lenient replay in any order, ignoring the query string order and the
+	// headers
+	client.Transport = rdap.NewBootstrapFetcher(NewReplayer(cassette, MatchLenient), bootstrapURI, nil)
+
+	for i := 0; i < 2; i++ {
+		domain, _, err = client.Domain("cookie.br", nil, url.Values{"apikey": []string{"other"}})
+		if err != nil || domain.LDHName != "cookie.br" {
+			t.Errorf("unexpected replayed domain “%v” (%v)", domain, err)
+		}
+	}
+
+	domain, _, err = client.Domain("example.br", nil, url.Values{"b": []string{"2"}, "a": []string{"1"}})
+	if err != nil || domain.LDHName != "example.br" {
+		t.Errorf("unexpected replayed domain “%v” (%v)", domain, err)
+	}
+
+	// the host of the server is ignored
+	other := rdap.Client{
+		URIs:      []string{"http://127.0.0.1:1"},
+		Transport: rdap.NewDefaultFetcher(NewReplayer(cassette, MatchLenient)),
+	}
+	domain, _, err = other.Domain("cookie.br", nil, url.Values{"apikey": []string{"other"}})
+	if err != nil || domain.LDHName != "cookie.br" {
+		t.Errorf("unexpected replayed domain from other host “%v” (%v)", domain, err)
+	}
+
+	_, _, err = client.Domain("example.br", nil, nil)
+	expected = fmt.Sprintf("rdaptest: unrecorded request GET %s/domain/example.br: no interaction with the same method, path and query string", s.URL)
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error “%s”, got “%v”", expected, err)
+	}
+
+	// the redacted parameter is still required
+	_, _, err = client.Domain("cookie.br", nil, nil)
+	expected = fmt.Sprintf("rdaptest: unrecorded request GET %s/domain/cookie.br: no interaction with the same method, path and query string", s.URL)
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error “%s”, got “%v”", expected, err)
+	}
+}
+
+func TestRecordOrReplay(t *testing.T) {
+	t.Setenv(RecordEnv, "")
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	cassette := Cassette{
+		Interactions: []Interaction{
+			{
+				Request:  RecordedRequest{Method: http.MethodGet, URL: "https://rdap.example.br/help"},
+				Response: RecordedResponse{Status: http.StatusOK, Body: `{"notices":[]}`},
+			},
+		},
+	}
+	if err := cassette.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	client := RecordOrReplay(t, path, http.DefaultClient, MatchStrict)
+
+	req, err := http.NewRequest(http.MethodGet, "https://rdap.example.br/help", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode != http.StatusOK || resp.Status != "200 OK" {
+		t.Errorf("unexpected status “%s”", resp.Status)
+	}
+}
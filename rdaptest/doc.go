@@ -0,0 +1,10 @@
+// Package rdaptest provides fake RDAP servers and bootstrap registries for
+// tests. The Server answers the configured paths with protocol objects, error
+// responses, delays and custom status codes, recording the requests received
+// for later assertions. The Bootstrap serves the IANA registries described in
+// RFC 9224 (that obsoletes RFC 7484) pointing to the fake servers.
+//
+// For integration tests, the Recorder captures real RDAP exchanges (including
+// the bootstrap downloads) in a cassette file, that the Replayer serves back
+// without network access.
+package rdaptest
@@ -0,0 +1,76 @@
+package rdaptest
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+// Request stores the data of a request received by a fake server
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+}
+
+func newRequest(r *http.Request) Request {
+	return Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+	}
+}
+
+// AssertPath fails the test when the request path is different
+func (r Request) AssertPath(t testing.TB, expected string) {
+	t.Helper()
+
+	if r.Path != expected {
+		t.Errorf("expected request path “%s”, got “%s”", expected, r.Path)
+	}
+}
+
+// AssertHeader fails the test when the first value of the HTTP header is
+// different. An empty expected value checks that the header wasn't sent
+func (r Request) AssertHeader(t testing.TB, key, expected string) {
+	t.Helper()
+
+	if value := r.Header.Get(key); value != expected {
+		t.Errorf("expected HTTP header %s “%s”, got “%s”", key, expected, value)
+	}
+}
+
+// AssertQuery fails the test when the first value of the query string
+// parameter is different. An empty expected value checks that the parameter
+// wasn't sent
+func (r Request) AssertQuery(t testing.TB, key, expected string) {
+	t.Helper()
+
+	if value := r.Query.Get(key); value != expected {
+		t.Errorf("expected query string parameter %s “%s”, got “%s”", key, expected, value)
+	}
+}
+
+// AssertRequestCount fails the test when the server didn't receive the
+// expected number of requests
+func (s *Server) AssertRequestCount(t testing.TB, expected int) {
+	t.Helper()
+
+	if count := len(s.Requests()); count != expected {
+		t.Errorf("expected %d requests, got %d", expected, count)
+	}
+}
+
+// LastRequest returns the last request received by the server. The test
+// fails immediately when there's no request
+func (s *Server) LastRequest(t testing.TB) Request {
+	t.Helper()
+
+	requests := s.Requests()
+	if len(requests) == 0 {
+		t.Fatal("no request received")
+	}
+	return requests[len(requests)-1]
+}
@@ -0,0 +1,144 @@
+package rdaptest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/registrobr/rdap/protocol"
+)
+
+// Response describes how the fake server answers a path
+type Response struct {
+	// Status is the HTTP status code. When zero, 200 is used
+	Status int
+
+	// Object is encoded in JSON as the response body, like a protocol.Domain
+	// or a protocol.Error
+	Object any
+
+	// Body is sent as it is when there's no object, useful to test invalid
+	// responses
+	Body string
+
+	// Header contains extra HTTP headers. The Content-Type is
+	// "application/rdap+json" unless it is defined here
+	Header http.Header
+
+	// Delay is the time waited before answering. The wait is interrupted when
+	// the client cancels the request
+	Delay time.Duration
+}
+
+// Server is a fake RDAP server. Paths without a configured response are
+// answered with an RDAP error and HTTP status 404
+type Server struct {
+	*httptest.Server
+
+	lock      sync.Mutex
+	responses map[string]Response
+	requests  []Request
+}
+
+// NewServer starts a fake RDAP server. It must be closed by the caller
+func NewServer() *Server {
+	s := &Server{
+		responses: make(map[string]Response),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Handle defines the response of the path, like "/domain/example.br". The
+// query string isn't part of the path
+func (s *Server) Handle(path string, response Response) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.responses[normalizePath(path)] = response
+}
+
+// Object answers the path with the object and HTTP status 200
+func (s *Server) Object(path string, object protocol.Object) {
+	s.Handle(path, Response{Object: object})
+}
+
+// Error answers the path with an RDAP error response (RFC 9083, section 6)
+func (s *Server) Error(path string, status int, description ...string) {
+	s.Handle(path, Response{
+		Status: status,
+		Object: protocol.NewError(status, description...),
+	})
+}
+
+// Requests returns the requests received, in order
+func (s *Server) Requests() []Request {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// Reset removes the configured responses and the recorded requests
+func (s *Server) Reset() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.responses = make(map[string]Response)
+	s.requests = nil
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	s.requests = append(s.requests, newRequest(r))
+	response, ok := s.responses[normalizePath(r.URL.Path)]
+	s.lock.Unlock()
+
+	if !ok {
+		response = Response{
+			Status: http.StatusNotFound,
+			Object: protocol.NewNotFoundError(),
+		}
+	}
+
+	if response.Delay > 0 {
+		select {
+		case <-time.After(response.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	writeResponse(w, response)
+}
+
+func writeResponse(w http.ResponseWriter, response Response) {
+	w.Header().Set("Content-Type", "application/rdap+json")
+	for key, values := range response.Header {
+		w.Header()[http.CanonicalHeaderKey(key)] = values
+	}
+
+	status := response.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	body := []byte(response.Body)
+	if response.Object != nil {
+		var err error
+		if body, err = json.Marshal(response.Object); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+func normalizePath(path string) string {
+	return "/" + strings.Trim(path, "/")
+}
@@ -0,0 +1,151 @@
+package rdaptest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/registrobr/rdap"
+	"github.com/registrobr/rdap/protocol"
+)
+
+func TestServer(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	s.Object("domain/example.br", &protocol.Domain{ObjectClassName: "domain", LDHName: "example.br"})
+	s.Error("/domain/limited.br", http.StatusTooManyRequests, "slow down")
+	s.Handle("/domain/invalid.br", Response{Body: "{", Header: http.Header{"content-type": []string{"text/plain"}}})
+	s.Handle("/domain/slow.br", Response{Object: &protocol.Domain{LDHName: "slow.br"}, Delay: time.Second})
+
+	client := rdap.Client{
+		Transport: rdap.NewDefaultFetcher(&http.Client{Timeout: 100 * time.Millisecond}),
+		URIs:      []string{s.URL},
+	}
+
+	data := []struct {
+		description   string
+		fqdn          string
+		expected      *protocol.Domain
+		expectedError error
+	}{
+		{
+			description: "it should answer with the configured object",
+			fqdn:        "example.br",
+			expected:    &protocol.Domain{ObjectClassName: "domain", LDHName: "example.br"},
+		},
+		{
+			description: "it should answer with the configured error",
+			fqdn:        "limited.br",
+			expectedError: protocol.Error{
+				ErrorCode:   http.StatusTooManyRequests,
+				Title:       "Too Many Requests",
+				Description: []string{"slow down"},
+			},
+		},
+		{
+			description:   "it should answer with the configured body and headers",
+			fqdn:          "invalid.br",
+			expectedError: fmt.Errorf("unexpected response: 200 OK"),
+		},
+		{
+			description:   "it should answer not found for unknown paths",
+			fqdn:          "unknown.br",
+			expectedError: rdap.ErrNotFound,
+		},
+	}
+
+	for i, item := range data {
+		domain, _, err := client.Domain(item.fqdn, nil, nil)
+
+		if item.expectedError != nil {
+			if fmt.Sprintf("%v", item.expectedError) != fmt.Sprintf("%v", err) {
+				t.Errorf("[%d] %s: expected error “%v”, got “%v”", i, item.description, item.expectedError, err)
+			}
+
+		} else if err != nil {
+			t.Errorf("[%d] %s: unexpected error “%s”", i, item.description, err)
+
+		} else if !reflect.DeepEqual(item.expected, domain) {
+			t.Errorf("[%d] %s: expected “%#v”, got “%#v”", i, item.description, item.expected, domain)
+		}
+	}
+
+	if _, _, err := client.Domain("slow.br", nil, nil); err == nil {
+		t.Error("expected a timeout with the delayed response")
+	}
+
+	s.AssertRequestCount(t, len(data)+1)
+
+	_, _, err := client.Domain("example.br", http.Header{"X-Forwarded-For": []string{"192.0.2.1"}},
+		url.Values{"ticket": []string{"123"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request := s.LastRequest(t)
+	request.AssertPath(t, "/domain/example.br")
+	request.AssertHeader(t, "Accept", "application/rdap+json")
+	request.AssertHeader(t, "X-Forwarded-For", "192.0.2.1")
+	request.AssertQuery(t, "ticket", "123")
+
+	s.Reset()
+	if _, _, err := client.Domain("example.br", nil, nil); !errors.Is(err, rdap.ErrNotFound) {
+		t.Errorf("unexpected error “%v” after reset", err)
+	}
+	s.AssertRequestCount(t, 1)
+}
+
+func TestBootstrap(t *testing.T) {
+	br := NewServer()
+	defer br.Close()
+	br.Object("/domain/example.br", &protocol.Domain{ObjectClassName: "domain", LDHName: "example.br"})
+	br.Object("/autnum/64500", &protocol.AS{ObjectClassName: "autnum", Handle: "AS64500"})
+
+	rir := NewServer()
+	defer rir.Close()
+	rir.Object("/ip/192.0.2.1", &protocol.IPNetwork{ObjectClassName: "ip network", Handle: "NET-1"})
+
+	b := NewBootstrap()
+	defer b.Close()
+	b.Add(RegistryDNS, []string{"br"}, br.URL+"/")
+	b.Add(RegistryASN, []string{"64496-64511"}, br.URL)
+	b.Add(RegistryIPv4, []string{"192.0.2.0/24"}, rir.URL)
+
+	client := rdap.Client{
+		Transport: rdap.NewBootstrapFetcher(http.DefaultClient, b.URI(), nil),
+	}
+
+	if domain, _, err := client.Domain("example.br", nil, nil); err != nil || domain.LDHName != "example.br" {
+		t.Errorf("unexpected domain “%v” (%v)", domain, err)
+	}
+
+	if as, _, err := client.ASN(64500, nil, nil); err != nil || as.Handle != "AS64500" {
+		t.Errorf("unexpected autnum “%v” (%v)", as, err)
+	}
+
+	if ipnetwork, _, err := client.Query("192.0.2.1", nil, nil); err != nil || ipnetwork.(*protocol.IPNetwork).Handle != "NET-1" {
+		t.Errorf("unexpected IP network “%v” (%v)", ipnetwork, err)
+	}
+
+	if _, _, err := client.Domain("example.com", nil, nil); err == nil {
+		t.Error("expected an error for a domain without RDAP server")
+	}
+
+	br.AssertRequestCount(t, 2)
+	rir.AssertRequestCount(t, 1)
+
+	var paths []string
+	for _, request := range b.Requests() {
+		paths = append(paths, request.Path)
+	}
+
+	expected := []string{"/dns.json", "/asn.json", "/ipv4.json", "/dns.json"}
+	if !reflect.DeepEqual(expected, paths) {
+		t.Errorf("expected bootstrap requests “%v”, got “%v”", expected, paths)
+	}
+}
@@ -0,0 +1,271 @@
+package rdap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/registrobr/rdap/protocol"
+)
+
+// SearchOptions controls the sorting and paging of the search results, as
+// described in RFC 8977, and the subset of the object members (RFC 8982)
+type SearchOptions struct {
+	// Sort is the value of the sort parameter, a comma separated list of
+	// properties with an optional ":a" (ascending) or ":d" (descending)
+	// suffix, like "registrationDate:d,name"
+	Sort string
+
+	// Count requests the total number of objects in the paging metadata
+	Count bool
+
+	// PageSize is the number of objects per page, sent in the limit parameter.
+	// It isn't defined by RFC 8977, so servers may ignore it
+	PageSize int
+
+	// FieldSet requests a subset of the object members, like
+	// protocol.FieldSetID or protocol.FieldSetBrief. Servers without the
+	// subsetting extension return the full objects
+	FieldSet protocol.FieldSet
+}
+
+// Values returns the query string parameters of the options, to be used with
+// the search methods that retrieve a single page
+func (o SearchOptions) Values() url.Values {
+	values := make(url.Values)
+	if o.Sort != "" {
+		values.Set("sort", o.Sort)
+	}
+	if o.Count {
+		values.Set("count", "true")
+	}
+	if o.PageSize > 0 {
+		values.Set("limit", strconv.Itoa(o.PageSize))
+	}
+	if o.FieldSet != "" {
+		values.Set("fieldSet", string(o.FieldSet))
+	}
+	return values
+}
+
+// SearchDomains will query each RDAP server to search the domains where the
+// parameter (name, nsLdhName or nsIp) matches the value, that may contain an
+// asterisk for partial matches (RFC 9082, section 3.2.1). Only the first page
+// of results is returned; use Domains to iterate over all pages. The HTTP
+// header of the RDAP response is also returned to analyze any specific flag
+func (c *Client) SearchDomains(parameter, value string, header http.Header, queryString url.Values) (*protocol.DomainSearchResults, http.Header, error) {
+	results := &protocol.DomainSearchResults{}
+	_, responseHeader, err := c.search(c.URIs, QueryTypeDomains, searchValues(parameter, value, queryString), header, results)
+	if err != nil {
+		return nil, responseHeader, err
+	}
+	return results, responseHeader, nil
+}
+
+// SearchNameservers will query each RDAP server to search the nameservers
+// where the parameter (name or ip) matches the value (RFC 9082, section
+// 3.2.2). Only the first page of results is returned; use Nameservers to
+// iterate over all pages
+func (c *Client) SearchNameservers(parameter, value string, header http.Header, queryString url.Values) (*protocol.NameserverSearchResults, http.Header, error) {
+	results := &protocol.NameserverSearchResults{}
+	_, responseHeader, err := c.search(c.URIs, QueryTypeNameservers, searchValues(parameter, value, queryString), header, results)
+	if err != nil {
+		return nil, responseHeader, err
+	}
+	return results, responseHeader, nil
+}
+
+// SearchEntities will query each RDAP server to search the entities where the
+// parameter (fn or handle) matches the value (RFC 9082, section 3.2.3). Only
+// the first page of results is returned; use Entities to iterate over all
+// pages
+func (c *Client) SearchEntities(parameter, value string, header http.Header, queryString url.Values) (*protocol.EntitySearchResults, http.Header, error) {
+	results := &protocol.EntitySearchResults{}
+	_, responseHeader, err := c.search(c.URIs, QueryTypeEntities, searchValues(parameter, value, queryString), header, results)
+	if err != nil {
+		return nil, responseHeader, err
+	}
+	return results, responseHeader, nil
+}
+
+// Domains iterates over all domains found by the search (see SearchDomains),
+// following the "next" links of the paging metadata (RFC 8977, section 2.3),
+// that carry the cursor of the next page. The iteration stops at the first
+// error, that is yielded with a nil domain. The context is checked between
+// each page
+func (c *Client) Domains(ctx context.Context, parameter, value string, options SearchOptions, header http.Header) iter.Seq2[*protocol.Domain, error] {
+	return searchAll(ctx, c, QueryTypeDomains, searchValues(parameter, value, options.Values()), header,
+		func(results *protocol.DomainSearchResults) ([]protocol.Domain, *protocol.PagingMetadata) {
+			return results.Domains, results.PagingMetadata
+		})
+}
+
+// Nameservers iterates over all nameservers found by the search (see
+// SearchNameservers), following the "next" links like Domains
+func (c *Client) Nameservers(ctx context.Context, parameter, value string, options SearchOptions, header http.Header) iter.Seq2[*protocol.Nameserver, error] {
+	return searchAll(ctx, c, QueryTypeNameservers, searchValues(parameter, value, options.Values()), header,
+		func(results *protocol.NameserverSearchResults) ([]protocol.Nameserver, *protocol.PagingMetadata) {
+			return results.Nameservers, results.PagingMetadata
+		})
+}
+
+// Entities iterates over all entities found by the search (see
+// SearchEntities), following the "next" links like Domains
+func (c *Client) Entities(ctx context.Context, parameter, value string, options SearchOptions, header http.Header) iter.Seq2[*protocol.Entity, error] {
+	return searchAll(ctx, c, QueryTypeEntities, searchValues(parameter, value, options.Values()), header,
+		func(results *protocol.EntitySearchResults) ([]protocol.Entity, *protocol.PagingMetadata) {
+			return results.Entities, results.PagingMetadata
+		})
+}
+
+// searchValues adds the search parameter to a copy of the query string
+func searchValues(parameter, value string, queryString url.Values) url.Values {
+	values := make(url.Values)
+	for key, items := range queryString {
+		values[key] = append([]string(nil), items...)
+	}
+	values.Set(parameter, value)
+	return values
+}
+
+// search sends the search request and decodes the response into results. The
+// URL of the response is returned to resolve relative links
+func (c *Client) search(uris []string, queryType QueryType, queryString url.Values, header http.Header, results any) (*url.URL, http.Header, error) {
+	resp, err := c.Transport.Fetch(uris, queryType, "", header, queryString)
+	defer func() {
+		if resp != nil && resp.Body != nil {
+			resp.Body.Close()
+		}
+	}()
+
+	if err != nil {
+		if resp != nil {
+			return nil, resp.Header, err
+		}
+		return nil, nil, err
+	}
+
+	if err = json.NewDecoder(resp.Body).Decode(results); err != nil {
+		return nil, resp.Header, err
+	}
+
+	var responseURL *url.URL
+	if resp.Request != nil {
+		responseURL = resp.Request.URL
+	}
+	return responseURL, resp.Header, nil
+}
+
+// searchAll iterates over the items of all pages of the search
+func searchAll[T any, R any](
+	ctx context.Context,
+	c *Client,
+	queryType QueryType,
+	queryString url.Values,
+	header http.Header,
+	page func(*R) ([]T, *protocol.PagingMetadata),
+) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		uris := c.URIs
+		visited := make(map[string]bool)
+
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			results := new(R)
+			responseURL, _, err := c.search(uris, queryType, queryString, header, results)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if responseURL != nil {
+				visited[pageKey(responseURL)] = true
+			}
+
+			items, paging := page(results)
+			for i := range items {
+				if !yield(&items[i], nil) {
+					return
+				}
+			}
+
+			next := paging.Next()
+			if next == "" {
+				return
+			}
+
+			nextURL, err := url.Parse(next)
+			if err == nil && responseURL != nil {
+				nextURL = responseURL.ResolveReference(nextURL)
+			}
+
+			var ok bool
+			if err == nil {
+				var uri string
+				uri, queryType, queryString, ok = parseSearchURI(nextURL)
+				uris = []string{uri}
+			}
+
+			if !ok {
+				yield(nil, fmt.Errorf("invalid next page link “%s”", next))
+				return
+			}
+
+			// protects against servers that link to a page already retrieved
+			if visited[pageKey(nextURL)] {
+				return
+			}
+			visited[pageKey(nextURL)] = true
+		}
+	}
+}
+
+// pageKey identifies a search page by its URL, ignoring the order of the query
+// string parameters
+func pageKey(u *url.URL) string {
+	key := url.URL{
+		Scheme:   u.Scheme,
+		User:     u.User,
+		Host:     u.Host,
+		Path:     strings.TrimRight(u.Path, "/"),
+		RawQuery: u.Query().Encode(),
+	}
+	return key.String()
+}
+
+// parseSearchURI splits a search URL into the server address, the search type
+// and the query string, as expected by the Fetcher interface
+func parseSearchURI(u *url.URL) (uri string, queryType QueryType, queryString url.Values, ok bool) {
+	if u.Scheme == "" || u.Host == "" {
+		return
+	}
+
+	path := strings.TrimRight(u.Path, "/")
+	index := strings.LastIndex(path, "/")
+	if index < 0 {
+		return
+	}
+
+	queryType = QueryType(path[index+1:])
+	switch queryType {
+	case QueryTypeDomains, QueryTypeNameservers, QueryTypeEntities:
+	default:
+		return
+	}
+
+	base := url.URL{
+		Scheme: u.Scheme,
+		User:   u.User,
+		Host:   u.Host,
+		Path:   path[:index],
+	}
+	return base.String(), queryType, u.Query(), true
+}
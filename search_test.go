@@ -0,0 +1,207 @@
+package rdap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/registrobr/rdap/protocol"
+	"github.com/registrobr/rdap/server"
+)
+
+func TestClientSearch(t *testing.T) {
+	var objects []protocol.Object
+	for i := 1; i <= 5; i++ {
+		objects = append(objects,
+			&protocol.Domain{ObjectClassName: "domain", LDHName: fmt.Sprintf("example%d.br", i)},
+			&protocol.Entity{ObjectClassName: "entity", Handle: fmt.Sprintf("E-%d", i)},
+		)
+	}
+	objects = append(objects, &protocol.Nameserver{ObjectClassName: "nameserver", LDHName: "a.dns.br"})
+
+	backend, err := server.NewMemoryBackend(objects...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := server.NewHandler(backend)
+	handler.SearchLimit = 3
+	handler.CursorKey = []byte("secret")
+
+	var requests []string
+	ts := httptest.NewServer(http.StripPrefix("/rdap", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RawQuery)
+		handler.ServeHTTP(w, r)
+	})))
+	defer ts.Close()
+
+	client := NewClient([]string{ts.URL + "/rdap/"})
+
+	results, _, err := client.SearchDomains("name", "example*", nil, SearchOptions{Sort: "name:d", Count: true}.Values())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results.Domains) != 3 || results.Domains[0].LDHName != "example5.br" {
+		t.Errorf("unexpected first page “%#v”", results.Domains)
+	}
+
+	if results.PagingMetadata == nil || results.PagingMetadata.TotalCount == nil || *results.PagingMetadata.TotalCount != 5 {
+		t.Errorf("unexpected paging metadata “%#v”", results.PagingMetadata)
+	}
+
+	results, _, err = client.SearchDomains("name", "example1.br", nil, SearchOptions{FieldSet: protocol.FieldSetID}.Values())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if results.SubsettingMetadata == nil || results.SubsettingMetadata.CurrentFieldSet != protocol.FieldSetID {
+		t.Errorf("unexpected subsetting metadata “%#v”", results.SubsettingMetadata)
+	}
+
+	nameservers, _, err := client.SearchNameservers("name", "a.dns.br", nil, nil)
+	if err != nil || len(nameservers.Nameservers) != 1 {
+		t.Errorf("unexpected nameserver search results “%#v” (%v)", nameservers, err)
+	}
+
+	var handles []string
+	for entity, err := range client.Entities(context.Background(), "handle", "E-*", SearchOptions{Sort: "handle:d", PageSize: 2}, nil) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		handles = append(handles, entity.Handle)
+	}
+
+	expected := []string{"E-5", "E-4", "E-3", "E-2", "E-1"}
+	if !reflect.DeepEqual(expected, handles) {
+		t.Errorf("expected entities “%v”, got “%v”", expected, handles)
+	}
+
+	// 3 single page searches and 3 pages
+	if len(requests) != 6 {
+		t.Errorf("expected 6 requests, got %d: %v", len(requests), requests)
+	}
+
+	requests = nil
+	var names []string
+	for domain, err := range client.Domains(context.Background(), "name", "example*", SearchOptions{}, nil) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, domain.LDHName)
+		if len(names) == 2 {
+			break
+		}
+	}
+
+	if len(names) != 2 || len(requests) != 1 {
+		t.Errorf("the iteration didn't stop: %v (%d requests)", names, len(requests))
+	}
+
+	for domain, err := range client.Domains(context.Background(), "name", "ex*le.br", SearchOptions{}, nil) {
+		expectedErr := protocol.Error{
+			ErrorCode:   http.StatusUnprocessableEntity,
+			Title:       "Unprocessable Entity",
+			Description: []string{"unsupported search pattern “ex*le.br”: the asterisk must be at the end of a label"},
+		}
+		if domain != nil || fmt.Sprintf("%v", expectedErr) != fmt.Sprintf("%v", err) {
+			t.Errorf("expected error “%v”, got “%v” (%v)", expectedErr, err, domain)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, err := range client.Entities(ctx, "handle", "E-*", SearchOptions{}, nil) {
+		if err != context.Canceled {
+			t.Errorf("unexpected error “%v” with a canceled context", err)
+		}
+	}
+}
+
+func TestClientSearchLoop(t *testing.T) {
+	var requests int
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		// the next page link points back to the first page
+		results := protocol.DomainSearchResults{
+			Domains: []protocol.Domain{{ObjectClassName: "domain", LDHName: "example.br"}},
+			PagingMetadata: &protocol.PagingMetadata{
+				Links: []protocol.Link{{Rel: "next", Href: ts.URL + "/domains/?sort=name&name=example%2A"}},
+			},
+		}
+
+		w.Header().Set("Content-Type", "application/rdap+json")
+		json.NewEncoder(w).Encode(results)
+	}))
+	defer ts.Close()
+
+	client := NewClient([]string{ts.URL})
+
+	var names []string
+	for domain, err := range client.Domains(context.Background(), "name", "example*", SearchOptions{Sort: "name"}, nil) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, domain.LDHName)
+	}
+
+	if !reflect.DeepEqual([]string{"example.br"}, names) || requests != 1 {
+		t.Errorf("the first page was retrieved again: %v (%d requests)", names, requests)
+	}
+}
+
+func TestParseSearchURI(t *testing.T) {
+	data := []struct {
+		description         string
+		href                string
+		expectedURI         string
+		expectedQueryType   QueryType
+		expectedQueryString url.Values
+		expectedOK          bool
+	}{
+		{
+			description:         "it should split a search URL",
+			href:                "https://rdap.example.br/rdap/domains/?name=exam%2A&cursor=abc",
+			expectedURI:         "https://rdap.example.br/rdap",
+			expectedQueryType:   QueryTypeDomains,
+			expectedQueryString: url.Values{"name": []string{"exam*"}, "cursor": []string{"abc"}},
+			expectedOK:          true,
+		},
+		{
+			description: "it should ignore relative URLs",
+			href:        "/rdap/domains?cursor=abc",
+		},
+		{
+			description: "it should ignore URLs of other query types",
+			href:        "https://rdap.example.br/domain/example.br",
+		},
+	}
+
+	for i, item := range data {
+		u, err := url.Parse(item.href)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		uri, queryType, queryString, ok := parseSearchURI(u)
+		if ok != item.expectedOK {
+			t.Errorf("[%d] %s: expected ok %t", i, item.description, item.expectedOK)
+			continue
+		}
+
+		if !ok {
+			continue
+		}
+
+		if uri != item.expectedURI || queryType != item.expectedQueryType || !reflect.DeepEqual(item.expectedQueryString, queryString) {
+			t.Errorf("[%d] %s: unexpected result “%s”, “%s”, “%v”", i, item.description, uri, queryType, queryString)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+
+	"github.com/registrobr/rdap/protocol"
+)
+
+var (
+	// ErrNotFound is used by the backend when the requested object doesn't
+	// exist. The handler answers with HTTP status 404
+	ErrNotFound = errors.New("not found")
+
+	// ErrNotImplemented is used by the backend when it doesn't support the
+	// query type. The handler answers with HTTP status 501
+	ErrNotImplemented = errors.New("not implemented")
+)
+
+// Backend retrieves the RDAP objects requested to the server. The query
+// values are already validated and normalized by the handler: domain and
+// nameserver names are lower case ASCII (IDNA) without the final dot, and IP
+// networks are masked. Besides ErrNotFound and ErrNotImplemented, the methods
+// can return a *protocol.Error to control the error response (see MapError)
+type Backend interface {
+	Domain(ctx context.Context, fqdn string) (*protocol.Domain, error)
+	Ticket(ctx context.Context, ticket int) (*protocol.Domain, error)
+	Nameserver(ctx context.Context, fqdn string) (*protocol.Nameserver, error)
+	Entity(ctx context.Context, handle string) (*protocol.Entity, error)
+	IP(ctx context.Context, ip netip.Addr) (*protocol.IPNetwork, error)
+	IPNetwork(ctx context.Context, ipnet netip.Prefix) (*protocol.IPNetwork, error)
+	Autnum(ctx context.Context, asn uint32) (*protocol.AS, error)
+	Help(ctx context.Context) (*protocol.Help, error)
+}
+
+// UnimplementedBackend answers all queries with ErrNotImplemented. Embed it
+// in backends that support only some query types
+type UnimplementedBackend struct{}
+
+// Domain implements the Backend interface
+func (UnimplementedBackend) Domain(context.Context, string) (*protocol.Domain, error) {
+	return nil, ErrNotImplemented
+}
+
+// Ticket implements the Backend interface
+func (UnimplementedBackend) Ticket(context.Context, int) (*protocol.Domain, error) {
+	return nil, ErrNotImplemented
+}
+
+// Nameserver implements the Backend interface
+func (UnimplementedBackend) Nameserver(context.Context, string) (*protocol.Nameserver, error) {
+	return nil, ErrNotImplemented
+}
+
+// Entity implements the Backend interface
+func (UnimplementedBackend) Entity(context.Context, string) (*protocol.Entity, error) {
+	return nil, ErrNotImplemented
+}
+
+// IP implements the Backend interface
+func (UnimplementedBackend) IP(context.Context, netip.Addr) (*protocol.IPNetwork, error) {
+	return nil, ErrNotImplemented
+}
+
+// IPNetwork implements the Backend interface
+func (UnimplementedBackend) IPNetwork(context.Context, netip.Prefix) (*protocol.IPNetwork, error) {
+	return nil, ErrNotImplemented
+}
+
+// Autnum implements the Backend interface
+func (UnimplementedBackend) Autnum(context.Context, uint32) (*protocol.AS, error) {
+	return nil, ErrNotImplemented
+}
+
+// Help implements the Backend interface
+func (UnimplementedBackend) Help(context.Context) (*protocol.Help, error) {
+	return nil, ErrNotImplemented
+}
@@ -0,0 +1,5 @@
+// Package server contains the building blocks of an RDAP server. The Handler
+// parses and validates the queries described in RFC 9082 (that obsoletes RFC
+// 7482), retrieves the objects from a Backend and writes the responses with
+// the HTTP status codes of RFC 7480.
+package server
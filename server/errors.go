@@ -0,0 +1,41 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/registrobr/rdap/protocol"
+)
+
+// ErrorMapper converts an error of the query parsing or of the backend to the
+// error response. It returns nil to use the default mapping (MapError)
+type ErrorMapper func(r *http.Request, err error) *protocol.Error
+
+// MapError converts the error to the error response. Backends can return
+// typed errors like protocol.NewTooManyRequestsError, that are sent as they
+// are. Malformed queries are answered with HTTP status 400, unsupported search
+// patterns with 422, ErrNotFound with 404, ErrNotImplemented with 501 and
+// unknown errors with 500, without exposing the error message
+func MapError(err error) *protocol.Error {
+	var protocolErr *protocol.Error
+	var queryErr *QueryError
+	var patternErr *PatternError
+
+	switch {
+	case errors.As(err, &protocolErr):
+		// copy the error, as the backend may reuse it and the middlewares
+		// change the response
+		response := *protocolErr
+		return &response
+	case errors.As(err, &queryErr):
+		return protocol.NewBadRequestError(queryErr.Error())
+	case errors.As(err, &patternErr):
+		return protocol.NewUnprocessableEntityError(patternErr.Error())
+	case errors.Is(err, ErrNotFound):
+		return protocol.NewNotFoundError()
+	case errors.Is(err, ErrNotImplemented):
+		return protocol.NewNotImplementedError()
+	}
+
+	return protocol.NewError(http.StatusInternalServerError)
+}
@@ -0,0 +1,124 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/registrobr/rdap/protocol"
+)
+
+func TestMapError(t *testing.T) {
+	data := []struct {
+		description string
+		err         error
+		expected    *protocol.Error
+	}{
+		{
+			description: "it should keep the protocol errors",
+			err:         fmt.Errorf("backend: %w", protocol.NewTooManyRequestsError(time.Minute, "slow down")),
+			expected: &protocol.Error{
+				ErrorCode:   http.StatusTooManyRequests,
+				Title:       "Too Many Requests",
+				Description: []string{"slow down"},
+				RetryAfter:  time.Minute,
+			},
+		},
+		{
+			description: "it should keep the protocol errors returned as value",
+			err:         protocol.Error{ErrorCode: http.StatusUnprocessableEntity, Title: "Unsupported"},
+			expected: &protocol.Error{
+				ErrorCode: http.StatusUnprocessableEntity,
+				Title:     "Unsupported",
+			},
+		},
+		{
+			description: "it should map malformed queries",
+			err:         &QueryError{Path: "/ip/x", Reason: "invalid IP address “x”"},
+			expected: &protocol.Error{
+				ErrorCode:   http.StatusBadRequest,
+				Title:       "Bad Request",
+				Description: []string{"malformed query “/ip/x”: invalid IP address “x”"},
+			},
+		},
+		{
+			description: "it should map not found errors",
+			err:         fmt.Errorf("domain example.br: %w", ErrNotFound),
+			expected: &protocol.Error{
+				ErrorCode: http.StatusNotFound,
+				Title:     "Not Found",
+			},
+		},
+		{
+			description: "it should map not implemented errors",
+			err:         ErrNotImplemented,
+			expected: &protocol.Error{
+				ErrorCode: http.StatusNotImplemented,
+				Title:     "Not Implemented",
+			},
+		},
+		{
+			description: "it should hide unknown errors",
+			err:         errors.New("database is down"),
+			expected: &protocol.Error{
+				ErrorCode: http.StatusInternalServerError,
+				Title:     "Internal Server Error",
+			},
+		},
+	}
+
+	for i, item := range data {
+		if response := MapError(item.err); !reflect.DeepEqual(item.expected, response) {
+			t.Errorf("[%d] %s: expected “%#v”, got “%#v”", i, item.description, item.expected, response)
+		}
+	}
+}
+
+func TestHandlerErrorMapper(t *testing.T) {
+	rateLimited := protocol.NewTooManyRequestsError(1500*time.Millisecond, "too many queries")
+
+	handler := &Handler{
+		Backend: UnimplementedBackend{},
+		ErrorMapper: func(r *http.Request, err error) *protocol.Error {
+			if r.Header.Get("X-Limited") != "" {
+				return rateLimited
+			}
+			return nil
+		},
+	}
+	handler.Use(Notices(protocol.Notice{Title: "Notice"}))
+
+	r := httptest.NewRequest(http.MethodGet, "/domain/example.br", nil)
+	r.Header.Set("X-Limited", "1")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("unexpected status %d", w.Code)
+	}
+
+	if retryAfter := w.Header().Get("Retry-After"); retryAfter != "2" {
+		t.Errorf("unexpected Retry-After “%s”", retryAfter)
+	}
+
+	expected := `{"notices":[{"title":"Notice"}],"errorCode":429,"title":"Too Many Requests","description":["too many queries"]}` + "\n"
+	if body := w.Body.String(); body != expected {
+		t.Errorf("unexpected body “%s”", body)
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/domain/example.br", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("unexpected status %d without the error mapper", w.Code)
+	}
+
+	if w.Header().Get("Retry-After") != "" {
+		t.Errorf("unexpected Retry-After without the error mapper")
+	}
+}
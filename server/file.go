@@ -0,0 +1,180 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/registrobr/rdap/protocol"
+)
+
+// FileBackend is a MemoryBackend that loads the objects from a directory,
+// including its subdirectories. Files with the extension ".json" contain one
+// RDAP object (search results have all their items loaded), and files with
+// the extensions ".jsonl" or ".ndjson" contain one object per line (JSON
+// Lines). Other files are ignored
+type FileBackend struct {
+	MemoryBackend
+
+	// Dir is the directory of the object files
+	Dir string
+
+	reloadLock sync.Mutex
+	signature  string
+}
+
+// NewFileBackend returns a backend with the objects loaded from the directory
+func NewFileBackend(dir string) (*FileBackend, error) {
+	f := FileBackend{
+		Dir: dir,
+	}
+
+	if err := f.Reload(); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// Reload reads all files again. When a file is invalid the current objects
+// are kept
+func (f *FileBackend) Reload() error {
+	f.reloadLock.Lock()
+	defer f.reloadLock.Unlock()
+
+	files, signature, err := f.files()
+	if err != nil {
+		return err
+	}
+
+	f.signature = signature
+	return f.load(files)
+}
+
+// Watch checks the directory in the given interval, reloading the objects
+// when a file is added, removed or changed, until the context is done. The
+// reload errors are informed to onError, that can be nil
+func (f *FileBackend) Watch(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := f.reloadChanged(); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
+
+// reloadChanged reloads the objects only when the files changed since the
+// last load
+func (f *FileBackend) reloadChanged() error {
+	f.reloadLock.Lock()
+	defer f.reloadLock.Unlock()
+
+	files, signature, err := f.files()
+	if err != nil {
+		return err
+	}
+
+	if signature == f.signature {
+		return nil
+	}
+
+	// the signature is stored even on failure, so the same broken files
+	// aren't reported in every check
+	f.signature = signature
+	return f.load(files)
+}
+
+// files lists the object files of the directory, returning also a signature
+// built from their names, sizes and modification times
+func (f *FileBackend) files() ([]string, string, error) {
+	var files []string
+	var signature strings.Builder
+
+	err := filepath.WalkDir(f.Dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		switch filepath.Ext(path) {
+		case ".json", ".jsonl", ".ndjson":
+		default:
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		files = append(files, path)
+		fmt.Fprintf(&signature, "%s %d %d\n", path, info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+
+	return files, signature.String(), err
+}
+
+func (f *FileBackend) load(files []string) error {
+	var objects []protocol.Object
+	for _, file := range files {
+		fileObjects, err := loadFile(file)
+		if err != nil {
+			return err
+		}
+		objects = append(objects, fileObjects...)
+	}
+
+	return f.Load(objects...)
+}
+
+func loadFile(file string) ([]protocol.Object, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if filepath.Ext(file) == ".json" {
+		object, err := protocol.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		return []protocol.Object{object}, nil
+	}
+
+	var objects []protocol.Object
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		object, err := protocol.Decode(bytes.NewReader(scanner.Bytes()))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", file, line, err)
+		}
+		objects = append(objects, object)
+	}
+
+	return objects, scanner.Err()
+}
@@ -0,0 +1,158 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/registrobr/rdap"
+	"github.com/registrobr/rdap/protocol"
+)
+
+func TestFileBackend(t *testing.T) {
+	backend, err := NewFileBackend("testdata/objects")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := httptest.NewServer(NewHandler(backend))
+	defer ts.Close()
+
+	client := rdap.NewClient([]string{ts.URL})
+
+	data := []struct {
+		description    string
+		query          string
+		expectedHandle string
+		expectedError  error
+	}{
+		{
+			description:    "it should answer a domain query",
+			query:          "example.br",
+			expectedHandle: "example.br",
+		},
+		{
+			description:    "it should answer an entity loaded from JSON Lines",
+			query:          "ABUSE-1",
+			expectedHandle: "ABUSE-1",
+		},
+		{
+			description:    "it should answer an IP query with the most specific network",
+			query:          "192.0.2.1",
+			expectedHandle: "192.0.2.0/24",
+		},
+		{
+			description:    "it should answer an IP network query",
+			query:          "2001:db8:1::/48",
+			expectedHandle: "2001:db8::/32",
+		},
+		{
+			description:    "it should answer an autnum query",
+			query:          "64500",
+			expectedHandle: "AS64500",
+		},
+		{
+			description:   "it should answer not found",
+			query:         "unknown.br",
+			expectedError: rdap.ErrNotFound,
+		},
+	}
+
+	for i, item := range data {
+		object, _, err := client.Query(item.query, nil, nil)
+
+		if item.expectedError != nil {
+			if fmt.Sprintf("%v", item.expectedError) != fmt.Sprintf("%v", err) {
+				t.Errorf("[%d] %s: expected error “%v”, got “%v”", i, item.description, item.expectedError, err)
+			}
+
+		} else if err != nil {
+			t.Errorf("[%d] %s: unexpected error “%s”", i, item.description, err)
+
+		} else if handle := object.(protocol.Object).GetHandle(); handle != item.expectedHandle {
+			t.Errorf("[%d] %s: expected handle “%s”, got “%s”", i, item.description, item.expectedHandle, handle)
+		}
+	}
+
+	domain, err := backend.Domain(context.Background(), "xn--caf-dma.br")
+	if err != nil || domain.UnicodeName != "café.br" {
+		t.Errorf("unexpected IDN result “%v” (%v)", domain, err)
+	}
+
+	help, err := backend.Help(context.Background())
+	if err != nil || len(help.Notices) != 1 {
+		t.Errorf("unexpected help “%v” (%v)", help, err)
+	}
+}
+
+func TestFileBackendReload(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "objects.jsonl")
+
+	// the file is replaced at once, so the watcher never reads it half written
+	write := func(content string) {
+		tmp := file + ".tmp"
+		if err := os.WriteFile(tmp, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		// changes the modification time, as the file system may have a low
+		// resolution
+		modTime := time.Now().Add(time.Duration(len(content)) * time.Second)
+		if err := os.Chtimes(tmp, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Rename(tmp, file); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`{"objectClassName": "entity", "handle": "OLD"}` + "\n")
+
+	backend, err := NewFileBackend(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errs := make(chan error, 10)
+	go backend.Watch(ctx, 10*time.Millisecond, func(err error) { errs <- err })
+
+	write(`{"objectClassName": "entity", "handle": "NEW"}` + "\n")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := backend.Entity(ctx, "NEW"); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the file wasn't reloaded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if _, err := backend.Entity(ctx, "OLD"); err == nil {
+		t.Error("the old entity wasn't removed")
+	}
+
+	write(`{"objectClassName": "entity", "handle": "NEW"}` + "\n" + `{"objectClassName": "unknown"}` + "\n")
+
+	select {
+	case err := <-errs:
+		expected := fmt.Sprintf("%s:2: unknown object class name “unknown”", file)
+		if err.Error() != expected {
+			t.Errorf("expected error “%s”, got “%s”", expected, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the reload error wasn't informed")
+	}
+
+	if _, err := backend.Entity(ctx, "NEW"); err != nil {
+		t.Error("the objects weren't kept after the reload error")
+	}
+}
@@ -0,0 +1,287 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/netip"
+	"strconv"
+
+	"github.com/registrobr/rdap/protocol"
+)
+
+// Handler is the http.Handler of the RDAP server. It must receive the URL
+// path relative to the RDAP base URL, so when it isn't mounted in the root
+// path use http.StripPrefix
+type Handler struct {
+	Backend Backend
+
+	// Middlewares change the top-level response objects, in the order that
+	// they were added
+	Middlewares []Middleware
+
+	// ErrorMapper converts the query and backend errors to error responses.
+	// When it is nil or returns nil, MapError is used
+	ErrorMapper ErrorMapper
+
+	// SearchLimit is the maximum number of objects in a search response. When
+	// it is zero DefaultSearchLimit is used
+	SearchLimit int
+
+	// CursorKey signs the cursors of the search pages (RFC 8977, section 2.3).
+	// When it is empty the searches aren't paged, and the results beyond the
+	// limit are truncated. Servers with many instances must share the key, and
+	// it must be kept between restarts, or the cursors of the clients become
+	// invalid
+	CursorKey []byte
+
+	// TrustedProxies are the networks whose X-Forwarded-Proto and
+	// X-Forwarded-Host HTTP headers are used to build the self, up, paging and
+	// sorting links (see BaseURL)
+	TrustedProxies []netip.Prefix
+
+	// DefaultFieldSet is the field set of the searches without the fieldSet
+	// parameter (RFC 8982). When it is empty the subsetting is disabled and
+	// the searches always return the full objects
+	DefaultFieldSet protocol.FieldSet
+}
+
+// NewHandler returns a Handler that retrieves the objects from the backend.
+// The Conformance and Links middlewares are already added and the searches
+// return the full objects by default. The paging is enabled by setting the
+// CursorKey
+func NewHandler(backend Backend) *Handler {
+	h := &Handler{
+		Backend:         backend,
+		DefaultFieldSet: protocol.FieldSetFull,
+	}
+	h.Middlewares = []Middleware{Conformance(backend), h.Links}
+	return h
+}
+
+// Use adds middlewares to the handler
+func (h *Handler) Use(middlewares ...Middleware) {
+	h.Middlewares = append(h.Middlewares, middlewares...)
+}
+
+// ServeHTTP implements the http.Handler interface. Only the GET and HEAD
+// methods are allowed (RFC 7480, section 4.1), malformed queries are answered
+// with HTTP status 400 and objects that don't exist with 404 (RFC 7480,
+// section 5.3). Searches are answered only when the backend implements
+// SearchBackend
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		h.writeError(w, r, protocol.NewError(http.StatusMethodNotAllowed))
+		return
+	}
+
+	if isSearch(r.URL.EscapedPath()) {
+		h.serveSearch(w, r)
+		return
+	}
+
+	query, err := ParseQuery(r.URL.EscapedPath())
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	object, err := h.query(r.Context(), query)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	if help, ok := object.(*protocol.Help); ok {
+		// the help lists the field sets of the searches (RFC 8982, section 3)
+		help.SubsettingMetadata = h.subsettingMetadata(r, "", h.DefaultFieldSet)
+	}
+
+	h.write(w, r, http.StatusOK, object)
+}
+
+// query retrieves the object from the backend according to the query type
+func (h *Handler) query(ctx context.Context, query Query) (protocol.Object, error) {
+	switch query.Type {
+	case QueryTypeDomain:
+		return found(h.Backend.Domain(ctx, query.Name))
+	case QueryTypeTicket:
+		return found(h.Backend.Ticket(ctx, query.Ticket))
+	case QueryTypeNameserver:
+		return found(h.Backend.Nameserver(ctx, query.Name))
+	case QueryTypeEntity:
+		return found(h.Backend.Entity(ctx, query.Handle))
+	case QueryTypeIP:
+		if query.IPNetwork.IsValid() {
+			return found(h.Backend.IPNetwork(ctx, query.IPNetwork))
+		}
+		return found(h.Backend.IP(ctx, query.IP))
+	case QueryTypeAutnum:
+		return found(h.Backend.Autnum(ctx, query.ASN))
+	case QueryTypeHelp:
+		return found(h.Backend.Help(ctx))
+	}
+
+	return nil, ErrNotImplemented
+}
+
+// serveSearch answers the searches of RFC 9082, section 3.2
+func (h *Handler) serveSearch(w http.ResponseWriter, r *http.Request) {
+	backend, ok := h.Backend.(SearchBackend)
+	if !ok {
+		h.writeError(w, r, ErrNotImplemented)
+		return
+	}
+
+	path, values := r.URL.EscapedPath(), r.URL.Query()
+
+	query, err := ParseSearchQuery(path, values)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	query.Limit = h.SearchLimit
+	if query.Limit <= 0 {
+		query.Limit = DefaultSearchLimit
+	}
+
+	var properties []SortProperty
+	if sortingBackend, ok := backend.(SortingBackend); ok {
+		properties = sortingBackend.SortProperties(query.Type)
+	}
+
+	if err := h.parsePaging(path, values, properties, &query); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	if query.FieldSet, err = h.parseFieldSet(path, values); err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	object, err := h.search(r, backend, query, properties)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.write(w, r, http.StatusOK, object)
+}
+
+// search retrieves the search results from the backend, limiting them to the
+// page size and trimming the objects to the field set
+func (h *Handler) search(r *http.Request, backend SearchBackend, query SearchQuery, properties []SortProperty) (protocol.Object, error) {
+	ctx := r.Context()
+	subsetting := h.subsettingMetadata(r, query.Type, query.FieldSet)
+
+	switch query.Type {
+	case SearchTypeDomains:
+		results, err := backend.SearchDomains(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		domains, metadata := paginate(h, r, query, properties, results)
+		domains = subset(domains, query.FieldSet, (*protocol.Domain).Subset)
+		return &protocol.DomainSearchResults{
+			Domains:            domains,
+			Remarks:            metadata.remarks,
+			PagingMetadata:     metadata.paging,
+			SortingMetadata:    metadata.sorting,
+			SubsettingMetadata: subsetting,
+		}, nil
+
+	case SearchTypeNameservers:
+		results, err := backend.SearchNameservers(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		nameservers, metadata := paginate(h, r, query, properties, results)
+		nameservers = subset(nameservers, query.FieldSet, (*protocol.Nameserver).Subset)
+		return &protocol.NameserverSearchResults{
+			Nameservers:        nameservers,
+			Remarks:            metadata.remarks,
+			PagingMetadata:     metadata.paging,
+			SortingMetadata:    metadata.sorting,
+			SubsettingMetadata: subsetting,
+		}, nil
+
+	case SearchTypeEntities:
+		results, err := backend.SearchEntities(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		entities, metadata := paginate(h, r, query, properties, results)
+		entities = subset(entities, query.FieldSet, (*protocol.Entity).Subset)
+		return &protocol.EntitySearchResults{
+			Entities:           entities,
+			Remarks:            metadata.remarks,
+			PagingMetadata:     metadata.paging,
+			SortingMetadata:    metadata.sorting,
+			SubsettingMetadata: subsetting,
+		}, nil
+	}
+
+	return nil, ErrNotImplemented
+}
+
+// found converts the backend result to an object, considering a nil object
+// as not found
+func found[T any, P interface {
+	*T
+	protocol.Object
+}](object P, err error) (protocol.Object, error) {
+	if err != nil {
+		return nil, err
+	}
+	if object == nil {
+		return nil, ErrNotFound
+	}
+	return object, nil
+}
+
+// write sends the object as the response body after applying the
+// middlewares. The body is omitted for HEAD requests
+func (h *Handler) write(w http.ResponseWriter, r *http.Request, status int, object protocol.Object) {
+	for _, middleware := range h.Middlewares {
+		middleware(r, object)
+	}
+
+	w.Header().Set("Content-Type", "application/rdap+json")
+	w.WriteHeader(status)
+
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	// the status code was already sent, so there's nothing to do with an
+	// encoding error
+	json.NewEncoder(w).Encode(object)
+}
+
+// writeError sends the error response body described in RFC 9083, section 6.
+// The HTTP status code is the error code of the response
+func (h *Handler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var response *protocol.Error
+	if h.ErrorMapper != nil {
+		response = h.ErrorMapper(r, err)
+	}
+	if response == nil {
+		response = MapError(err)
+	}
+
+	if response.RetryAfter > 0 {
+		seconds := int(math.Ceil(response.RetryAfter.Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	}
+
+	status := response.ErrorCode
+	if status < 400 || status > 599 {
+		status = http.StatusInternalServerError
+	}
+
+	h.write(w, r, status, response)
+}
@@ -0,0 +1,202 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"reflect"
+	"testing"
+
+	"github.com/registrobr/rdap/protocol"
+)
+
+// testBackend answers the domain, IP and help queries. The other query types
+// aren't implemented
+type testBackend struct {
+	UnimplementedBackend
+}
+
+func (testBackend) Domain(ctx context.Context, fqdn string) (*protocol.Domain, error) {
+	switch fqdn {
+	case "example.com":
+		return &protocol.Domain{ObjectClassName: "domain", LDHName: fqdn}, nil
+	case "nil.com":
+		return nil, nil
+	case "error.com":
+		return nil, errors.New("database is down")
+	}
+	return nil, ErrNotFound
+}
+
+func (testBackend) IP(ctx context.Context, ip netip.Addr) (*protocol.IPNetwork, error) {
+	return &protocol.IPNetwork{ObjectClassName: "ip network", StartAddress: ip.String()}, nil
+}
+
+func (testBackend) IPNetwork(ctx context.Context, ipnet netip.Prefix) (*protocol.IPNetwork, error) {
+	return &protocol.IPNetwork{ObjectClassName: "ip network", Handle: ipnet.String()}, nil
+}
+
+func (testBackend) Help(ctx context.Context) (*protocol.Help, error) {
+	return &protocol.Help{Notices: []protocol.Notice{{Title: "Help"}}}, nil
+}
+
+func TestHandler(t *testing.T) {
+	data := []struct {
+		description    string
+		method         string
+		target         string
+		expectedStatus int
+		expected       any
+	}{
+		{
+			description:    "it should return a domain",
+			method:         http.MethodGet,
+			target:         "/domain/EXAMPLE.com",
+			expectedStatus: http.StatusOK,
+			expected:       &protocol.Domain{ObjectClassName: "domain", LDHName: "example.com"},
+		},
+		{
+			description:    "it should return an IP network queried by address",
+			method:         http.MethodGet,
+			target:         "/ip/192.0.2.1",
+			expectedStatus: http.StatusOK,
+			expected:       &protocol.IPNetwork{ObjectClassName: "ip network", StartAddress: "192.0.2.1"},
+		},
+		{
+			description:    "it should return an IP network queried by prefix",
+			method:         http.MethodGet,
+			target:         "/ip/2001:db8::1/32",
+			expectedStatus: http.StatusOK,
+			expected:       &protocol.IPNetwork{ObjectClassName: "ip network", Handle: "2001:db8::/32"},
+		},
+		{
+			description:    "it should return the help",
+			method:         http.MethodGet,
+			target:         "/help",
+			expectedStatus: http.StatusOK,
+			expected:       &protocol.Help{Notices: []protocol.Notice{{Title: "Help"}}},
+		},
+		{
+			description:    "it should answer HEAD requests without body",
+			method:         http.MethodHead,
+			target:         "/domain/example.com",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			description:    "it should reject other methods",
+			method:         http.MethodPost,
+			target:         "/domain/example.com",
+			expectedStatus: http.StatusMethodNotAllowed,
+			expected: &protocol.Error{
+				ErrorCode: http.StatusMethodNotAllowed,
+				Title:     "Method Not Allowed",
+			},
+		},
+		{
+			description:    "it should reject malformed queries",
+			method:         http.MethodGet,
+			target:         "/autnum/AS1",
+			expectedStatus: http.StatusBadRequest,
+			expected: &protocol.Error{
+				ErrorCode:   http.StatusBadRequest,
+				Title:       "Bad Request",
+				Description: []string{"malformed query “/autnum/AS1”: invalid autonomous system number “AS1”"},
+			},
+		},
+		{
+			description:    "it should answer not found",
+			method:         http.MethodGet,
+			target:         "/domain/unknown.com",
+			expectedStatus: http.StatusNotFound,
+			expected: &protocol.Error{
+				ErrorCode: http.StatusNotFound,
+				Title:     "Not Found",
+			},
+		},
+		{
+			description:    "it should answer not found when the backend returns no object",
+			method:         http.MethodGet,
+			target:         "/domain/nil.com",
+			expectedStatus: http.StatusNotFound,
+			expected: &protocol.Error{
+				ErrorCode: http.StatusNotFound,
+				Title:     "Not Found",
+			},
+		},
+		{
+			description:    "it should answer not implemented",
+			method:         http.MethodGet,
+			target:         "/entity/XXXX",
+			expectedStatus: http.StatusNotImplemented,
+			expected: &protocol.Error{
+				ErrorCode: http.StatusNotImplemented,
+				Title:     "Not Implemented",
+			},
+		},
+		{
+			description:    "it should answer not implemented searches",
+			method:         http.MethodGet,
+			target:         "/domains?name=exam*",
+			expectedStatus: http.StatusNotImplemented,
+			expected: &protocol.Error{
+				ErrorCode: http.StatusNotImplemented,
+				Title:     "Not Implemented",
+			},
+		},
+		{
+			description:    "it should hide backend errors",
+			method:         http.MethodGet,
+			target:         "/domain/error.com",
+			expectedStatus: http.StatusInternalServerError,
+			expected: &protocol.Error{
+				ErrorCode: http.StatusInternalServerError,
+				Title:     "Internal Server Error",
+			},
+		},
+	}
+
+	// without middlewares, so the responses are the same of the backend
+	handler := &Handler{Backend: testBackend{}}
+
+	for i, item := range data {
+		r := httptest.NewRequest(item.method, item.target, nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != item.expectedStatus {
+			t.Errorf("[%d] %s: expected status %d, got %d", i, item.description, item.expectedStatus, w.Code)
+		}
+
+		if contentType := w.Header().Get("Content-Type"); contentType != "application/rdap+json" {
+			t.Errorf("[%d] %s: unexpected content type “%s”", i, item.description, contentType)
+		}
+
+		if item.expected == nil {
+			if w.Body.Len() > 0 {
+				t.Errorf("[%d] %s: unexpected body “%s”", i, item.description, w.Body)
+			}
+			continue
+		}
+
+		object := reflect.New(reflect.TypeOf(item.expected).Elem()).Interface()
+		if err := json.Unmarshal(w.Body.Bytes(), object); err != nil {
+			t.Errorf("[%d] %s: invalid body “%s”: %s", i, item.description, w.Body, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(item.expected, object) {
+			t.Errorf("[%d] %s: expected object “%#v”, got “%#v”", i, item.description, item.expected, object)
+		}
+	}
+
+	r := httptest.NewRequest(http.MethodDelete, "/help", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if allow := w.Header().Get("Allow"); allow != "GET, HEAD" {
+		t.Errorf("unexpected Allow header “%s”", allow)
+	}
+}
@@ -0,0 +1,142 @@
+package server
+
+import (
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/registrobr/rdap/protocol"
+)
+
+// Links is the middleware, added by NewHandler, that adds the "self" link to
+// the top-level and nested objects (domains, nameservers, entities, IP
+// networks and autnums) that don't have one, as recommended in RFC 9083,
+// section 4.2. IP networks with a parent handle also get an "up" link. The URLs
+// are built from the RDAP base URL of the request (see BaseURL) with the
+// TrustedProxies of the handler, like the paging and sorting links
+func (h *Handler) Links(r *http.Request, object protocol.Object) {
+	base := BaseURL(r, h.TrustedProxies...)
+
+	addLinks(base, object)
+	for child := range nestedObjects(object) {
+		addLinks(base, child)
+	}
+}
+
+// BaseURL returns the RDAP base URL of the request, with the final slash, like
+// "https://rdap.example.br/rdap/". The path prefix removed by http.StripPrefix
+// is kept. The X-Forwarded-Proto and X-Forwarded-Host HTTP headers are used
+// only when the remote address belongs to one of the trusted proxies, and the
+// forwarded scheme must be "http" or "https"
+func BaseURL(r *http.Request, trustedProxies ...netip.Prefix) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	host := r.Host
+
+	if trusted(r.RemoteAddr, trustedProxies) {
+		// only the schemes of RDAP URLs are accepted (RFC 7480, section 4)
+		switch proto := strings.ToLower(forwarded(r.Header.Get("X-Forwarded-Proto"))); proto {
+		case "http", "https":
+			scheme = proto
+		}
+		if forwardedHost := forwarded(r.Header.Get("X-Forwarded-Host")); forwardedHost != "" {
+			host = forwardedHost
+		}
+	}
+
+	// the handler receives only the path relative to the base URL, so the
+	// prefix is what was removed from the original path
+	var prefix string
+	if original, err := url.ParseRequestURI(r.RequestURI); err == nil {
+		originalPath, path := original.EscapedPath(), r.URL.EscapedPath()
+		if strings.HasSuffix(originalPath, path) {
+			prefix = strings.TrimSuffix(originalPath, path)
+		}
+	}
+
+	return scheme + "://" + host + strings.TrimSuffix(prefix, "/") + "/"
+}
+
+// trusted checks if the remote address belongs to one of the proxy networks
+func trusted(remoteAddr string, trustedProxies []netip.Prefix) bool {
+	if len(trustedProxies) == 0 {
+		return false
+	}
+
+	addrPort, err := netip.ParseAddrPort(remoteAddr)
+	if err != nil {
+		return false
+	}
+
+	addr := addrPort.Addr().Unmap()
+	return slices.ContainsFunc(trustedProxies, func(proxy netip.Prefix) bool {
+		return proxy.Contains(addr)
+	})
+}
+
+// forwarded returns the value added by the first proxy, as each proxy appends
+// its value separated by comma
+func forwarded(value string) string {
+	value, _, _ = strings.Cut(value, ",")
+	return strings.TrimSpace(value)
+}
+
+// addLinks adds the self and up links to the object
+func addLinks(base string, object protocol.Object) {
+	switch o := object.(type) {
+	case *protocol.Domain:
+		if o.LDHName != "" {
+			o.Links = addLink(o.Links, "self", base+"domain/"+o.LDHName)
+		}
+
+	case *protocol.Nameserver:
+		if o.LDHName != "" {
+			o.Links = addLink(o.Links, "self", base+"nameserver/"+o.LDHName)
+		}
+
+	case *protocol.Entity:
+		if o.Handle != "" {
+			o.Links = addLink(o.Links, "self", base+"entity/"+url.PathEscape(o.Handle))
+		}
+
+	case *protocol.AS:
+		if o.StartAutnum != 0 {
+			o.Links = addLink(o.Links, "self", base+"autnum/"+strconv.FormatUint(uint64(o.StartAutnum), 10))
+		}
+
+	case *protocol.IPNetwork:
+		// a range that isn't a single prefix can't be queried, as the query of
+		// one of its prefixes may return a more specific network
+		if prefixes, err := o.Prefixes(); err == nil && len(prefixes) == 1 {
+			o.Links = addLink(o.Links, "self", base+"ip/"+prefixes[0].String())
+		}
+
+		if o.ParentHandle == "" {
+			return
+		}
+		if parent, ok := o.ParentPrefix(); ok {
+			o.Links = addLink(o.Links, "up", base+"ip/"+parent.String())
+		}
+	}
+}
+
+// addLink appends the link when there's no link with the same relation type.
+// The backing array of the current links isn't changed, as it may be shared
+// by other objects of the backend
+func addLink(links []protocol.Link, rel, href string) []protocol.Link {
+	if slices.ContainsFunc(links, func(link protocol.Link) bool { return link.Rel == rel }) {
+		return links
+	}
+
+	return append(slices.Clip(links), protocol.Link{
+		Value: href,
+		Rel:   rel,
+		Href:  href,
+		Type:  "application/rdap+json",
+	})
+}
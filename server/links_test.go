@@ -0,0 +1,221 @@
+package server
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"reflect"
+	"testing"
+
+	"github.com/registrobr/rdap/protocol"
+)
+
+func TestBaseURL(t *testing.T) {
+	proxies := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+	data := []struct {
+		description    string
+		target         string
+		path           string
+		remoteAddr     string
+		tls            bool
+		header         http.Header
+		trustedProxies []netip.Prefix
+		expected       string
+	}{
+		{
+			description: "it should use the request host",
+			target:      "http://rdap.example.br/domain/example.br",
+			remoteAddr:  "192.0.2.1:1234",
+			expected:    "http://rdap.example.br/",
+		},
+		{
+			description: "it should detect TLS connections",
+			target:      "https://rdap.example.br/domain/example.br",
+			remoteAddr:  "192.0.2.1:1234",
+			tls:         true,
+			expected:    "https://rdap.example.br/",
+		},
+		{
+			description: "it should keep the prefix removed from the path",
+			target:      "http://rdap.example.br/rdap/v1/domain/example.br",
+			path:        "/domain/example.br",
+			remoteAddr:  "192.0.2.1:1234",
+			expected:    "http://rdap.example.br/rdap/v1/",
+		},
+		{
+			description: "it should use the headers of trusted proxies",
+			target:      "http://backend:8080/domain/example.br",
+			remoteAddr:  "[::ffff:10.1.2.3]:1234",
+			header: http.Header{
+				"X-Forwarded-Proto": []string{"HTTPS"},
+				"X-Forwarded-Host":  []string{"rdap.example.br, proxy.example.br"},
+			},
+			trustedProxies: proxies,
+			expected:       "https://rdap.example.br/",
+		},
+		{
+			description: "it should ignore unsupported forwarded schemes",
+			target:      "http://backend:8080/domain/example.br",
+			remoteAddr:  "10.1.2.3:1234",
+			header: http.Header{
+				"X-Forwarded-Proto": []string{"javascript"},
+				"X-Forwarded-Host":  []string{"rdap.example.br"},
+			},
+			trustedProxies: proxies,
+			expected:       "http://rdap.example.br/",
+		},
+		{
+			description: "it should ignore the headers of other clients",
+			target:      "http://backend:8080/domain/example.br",
+			remoteAddr:  "192.0.2.1:1234",
+			header: http.Header{
+				"X-Forwarded-Proto": []string{"https"},
+				"X-Forwarded-Host":  []string{"evil.example.com"},
+			},
+			trustedProxies: proxies,
+			expected:       "http://backend:8080/",
+		},
+	}
+
+	for i, item := range data {
+		r := httptest.NewRequest(http.MethodGet, item.target, nil)
+		r.RemoteAddr = item.remoteAddr
+		if !item.tls {
+			r.TLS = nil
+		} else if r.TLS == nil {
+			r.TLS = &tls.ConnectionState{}
+		}
+		for key, values := range item.header {
+			r.Header[key] = values
+		}
+		if item.path != "" {
+			r.URL.Path = item.path
+		}
+
+		if baseURL := BaseURL(r, item.trustedProxies...); baseURL != item.expected {
+			t.Errorf("[%d] %s: expected “%s”, got “%s”", i, item.description, item.expected, baseURL)
+		}
+	}
+}
+
+func TestLinks(t *testing.T) {
+	selfLink := func(href string) protocol.Link {
+		return protocol.Link{Value: href, Rel: "self", Href: href, Type: "application/rdap+json"}
+	}
+
+	domain := &protocol.Domain{
+		LDHName: "example.br",
+		Links: []protocol.Link{
+			{Rel: "related", Href: "https://registrar.example.br"},
+		},
+		Nameservers: []protocol.Nameserver{
+			{LDHName: "a.dns.br"},
+		},
+		Entities: []protocol.Entity{
+			{
+				Handle: "ABC/123",
+				Autnums: []protocol.AS{
+					{StartAutnum: 64512, EndAutnum: 64512},
+				},
+				Networks: []protocol.IPNetwork{
+					{
+						StartAddress: "192.0.2.0",
+						EndAddress:   "192.0.2.255",
+						ParentHandle: "192.0.0.0/16",
+					},
+					{
+						StartAddress: "2001:db8::",
+						EndAddress:   "2001:db8:0:2::ffff",
+						ParentHandle: "NET-2001-DB8",
+					},
+					{
+						StartAddress: "198.51.100.0",
+						EndAddress:   "198.51.100.127",
+						ParentHandle: "NET-198-51-100",
+						Links:        []protocol.Link{selfLink("https://other.example.net/ip/198.51.100.0/25")},
+					},
+				},
+			},
+		},
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "https://rdap.example.br/domain/example.br", nil)
+	(&Handler{}).Links(r, domain)
+
+	expected := &protocol.Domain{
+		LDHName: "example.br",
+		Links: []protocol.Link{
+			{Rel: "related", Href: "https://registrar.example.br"},
+			selfLink("https://rdap.example.br/domain/example.br"),
+		},
+		Nameservers: []protocol.Nameserver{
+			{
+				LDHName: "a.dns.br",
+				Links:   []protocol.Link{selfLink("https://rdap.example.br/nameserver/a.dns.br")},
+			},
+		},
+		Entities: []protocol.Entity{
+			{
+				Handle: "ABC/123",
+				Links:  []protocol.Link{selfLink("https://rdap.example.br/entity/ABC%2F123")},
+				Autnums: []protocol.AS{
+					{
+						StartAutnum: 64512,
+						EndAutnum:   64512,
+						Links:       []protocol.Link{selfLink("https://rdap.example.br/autnum/64512")},
+					},
+				},
+				Networks: []protocol.IPNetwork{
+					{
+						StartAddress: "192.0.2.0",
+						EndAddress:   "192.0.2.255",
+						ParentHandle: "192.0.0.0/16",
+						Links: []protocol.Link{
+							selfLink("https://rdap.example.br/ip/192.0.2.0/24"),
+							{
+								Value: "https://rdap.example.br/ip/192.0.0.0/16",
+								Rel:   "up",
+								Href:  "https://rdap.example.br/ip/192.0.0.0/16",
+								Type:  "application/rdap+json",
+							},
+						},
+					},
+					{
+						StartAddress: "2001:db8::",
+						EndAddress:   "2001:db8:0:2::ffff",
+						ParentHandle: "NET-2001-DB8",
+						// the range isn't a single prefix, so there's no self link
+						Links: []protocol.Link{
+							{
+								Value: "https://rdap.example.br/ip/2001:db8::/62",
+								Rel:   "up",
+								Href:  "https://rdap.example.br/ip/2001:db8::/62",
+								Type:  "application/rdap+json",
+							},
+						},
+					},
+					{
+						StartAddress: "198.51.100.0",
+						EndAddress:   "198.51.100.127",
+						ParentHandle: "NET-198-51-100",
+						Links: []protocol.Link{
+							selfLink("https://other.example.net/ip/198.51.100.0/25"),
+							{
+								Value: "https://rdap.example.br/ip/198.51.100.0/24",
+								Rel:   "up",
+								Href:  "https://rdap.example.br/ip/198.51.100.0/24",
+								Type:  "application/rdap+json",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(expected, domain) {
+		t.Errorf("unexpected links.\n%#v", domain)
+	}
+}
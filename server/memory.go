@@ -0,0 +1,554 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/registrobr/rdap/protocol"
+	"golang.org/x/net/idna"
+)
+
+// MemoryBackend is a reference Backend that answers the queries with objects
+// stored in memory. Domains and nameservers are indexed by ldhName,
+// unicodeName and handle, entities by handle, IP networks by range (the most
+// specific network that contains the queried address or prefix is returned)
+// and autnums by ASN range. Each query returns a new copy of the object, so
+// the middlewares can change it safely. It also implements SearchBackend and
+// SortingBackend, sorting the matches by name (domains and nameservers) or
+// handle (entities) when no sort is requested. Ticket queries aren't
+// supported
+type MemoryBackend struct {
+	UnimplementedBackend
+
+	lock  sync.RWMutex
+	index *memoryIndex
+}
+
+// NewMemoryBackend returns a backend with the given objects
+func NewMemoryBackend(objects ...protocol.Object) (*MemoryBackend, error) {
+	var m MemoryBackend
+	if err := m.Load(objects...); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Load replaces all objects of the backend. The supported objects are domains,
+// nameservers, entities, IP networks, autnums, the help and the search results,
+// that have their items added. It fails when two objects have the same key,
+// keeping the current objects
+func (m *MemoryBackend) Load(objects ...protocol.Object) error {
+	index := newMemoryIndex()
+	for _, object := range objects {
+		if err := index.add(object); err != nil {
+			return err
+		}
+	}
+
+	m.lock.Lock()
+	m.index = index
+	m.lock.Unlock()
+	return nil
+}
+
+// Domain implements the Backend interface
+func (m *MemoryBackend) Domain(ctx context.Context, fqdn string) (*protocol.Domain, error) {
+	return decodeEntry[protocol.Domain](m.current().domains[fqdn])
+}
+
+// Nameserver implements the Backend interface
+func (m *MemoryBackend) Nameserver(ctx context.Context, fqdn string) (*protocol.Nameserver, error) {
+	return decodeEntry[protocol.Nameserver](m.current().nameservers[fqdn])
+}
+
+// Entity implements the Backend interface
+func (m *MemoryBackend) Entity(ctx context.Context, handle string) (*protocol.Entity, error) {
+	return decodeEntry[protocol.Entity](m.current().entities[handle])
+}
+
+// IP implements the Backend interface
+func (m *MemoryBackend) IP(ctx context.Context, ip netip.Addr) (*protocol.IPNetwork, error) {
+	return decodeEntry[protocol.IPNetwork](m.current().network(ip, ip))
+}
+
+// IPNetwork implements the Backend interface
+func (m *MemoryBackend) IPNetwork(ctx context.Context, ipnet netip.Prefix) (*protocol.IPNetwork, error) {
+	return decodeEntry[protocol.IPNetwork](m.current().network(ipnet.Addr(), protocol.LastAddr(ipnet)))
+}
+
+// Autnum implements the Backend interface
+func (m *MemoryBackend) Autnum(ctx context.Context, asn uint32) (*protocol.AS, error) {
+	return decodeEntry[protocol.AS](m.current().autnum(asn))
+}
+
+// Help implements the Backend interface. When no help was loaded an empty one
+// is returned
+func (m *MemoryBackend) Help(ctx context.Context) (*protocol.Help, error) {
+	help := m.current().help
+	if help == nil {
+		return &protocol.Help{}, nil
+	}
+	return decodeEntry[protocol.Help](help)
+}
+
+// SearchDomains implements the SearchBackend interface
+func (m *MemoryBackend) SearchDomains(ctx context.Context, query SearchQuery) (SearchResults[protocol.Domain], error) {
+	return searchEntries[protocol.Domain](m.current().domainList, query)
+}
+
+// SearchNameservers implements the SearchBackend interface
+func (m *MemoryBackend) SearchNameservers(ctx context.Context, query SearchQuery) (SearchResults[protocol.Nameserver], error) {
+	return searchEntries[protocol.Nameserver](m.current().nameserverList, query)
+}
+
+// SearchEntities implements the SearchBackend interface
+func (m *MemoryBackend) SearchEntities(ctx context.Context, query SearchQuery) (SearchResults[protocol.Entity], error) {
+	return searchEntries[protocol.Entity](m.current().entityList, query)
+}
+
+// SortProperties implements the SortingBackend interface. Domains are sorted
+// by name and by the registration, expiration and last changed dates,
+// nameservers by name and entities by handle and full name. Without the sort
+// parameter the results are sorted by name or handle
+func (m *MemoryBackend) SortProperties(searchType SearchType) []SortProperty {
+	return slices.Clone(memorySortProperties[searchType])
+}
+
+var memorySortProperties = map[SearchType][]SortProperty{
+	SearchTypeDomains: {
+		{Name: "name", JSONPath: "$.domainSearchResults[*].ldhName", Default: true},
+		{Name: "registrationDate", JSONPath: `$.domainSearchResults[*].events[?(@.eventAction=="registration")].eventDate`},
+		{Name: "expirationDate", JSONPath: `$.domainSearchResults[*].events[?(@.eventAction=="expiration")].eventDate`},
+		{Name: "lastChangedDate", JSONPath: `$.domainSearchResults[*].events[?(@.eventAction=="last changed")].eventDate`},
+	},
+	SearchTypeNameservers: {
+		{Name: "name", JSONPath: "$.nameserverSearchResults[*].ldhName", Default: true},
+	},
+	SearchTypeEntities: {
+		{Name: "handle", JSONPath: "$.entitySearchResults[*].handle", Default: true},
+		{Name: "fn", JSONPath: `$.entitySearchResults[*].vcardArray[1][?(@[0]=="fn")][3]`},
+	},
+}
+
+func (m *MemoryBackend) current() *memoryIndex {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if m.index == nil {
+		return newMemoryIndex()
+	}
+	return m.index
+}
+
+// memoryIndex stores the objects in JSON, so each query decodes a new copy
+type memoryIndex struct {
+	domains     map[string][]byte
+	nameservers map[string][]byte
+	entities    map[string][]byte
+	networks    []networkEntry
+	autnums     []autnumEntry
+	help        []byte
+
+	// the search lists keep the order that the objects were loaded
+	domainList     []searchEntry
+	nameserverList []searchEntry
+	entityList     []searchEntry
+}
+
+// searchEntry stores the values of the object compared with each search
+// parameter and the values of each sort property
+type searchEntry struct {
+	values   map[SearchParameter][]string
+	sortKeys map[string]string
+	data     []byte
+}
+
+type networkEntry struct {
+	start, end netip.Addr
+	data       []byte
+}
+
+type autnumEntry struct {
+	start, end uint32
+	data       []byte
+}
+
+func newMemoryIndex() *memoryIndex {
+	return &memoryIndex{
+		domains:     make(map[string][]byte),
+		nameservers: make(map[string][]byte),
+		entities:    make(map[string][]byte),
+	}
+}
+
+func (m *memoryIndex) add(object protocol.Object) error {
+	switch o := object.(type) {
+	case *protocol.DomainSearchResults:
+		for i := range o.Domains {
+			if err := m.add(&o.Domains[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case *protocol.NameserverSearchResults:
+		for i := range o.Nameservers {
+			if err := m.add(&o.Nameservers[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case *protocol.EntitySearchResults:
+		for i := range o.Entities {
+			if err := m.add(&o.Entities[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+
+	switch o := object.(type) {
+	case *protocol.Domain:
+		if err := addNames(m.domains, "domain", data, o.LDHName, o.UnicodeName, o.Handle); err != nil {
+			return err
+		}
+
+		var nsNames, nsIPs []string
+		for _, nameserver := range o.Nameservers {
+			nsNames = append(nsNames, searchNames(nameserver.LDHName, nameserver.UnicodeName)...)
+			nsIPs = append(nsIPs, searchIPs(nameserver.IPAddresses)...)
+		}
+
+		names := searchNames(o.LDHName, o.UnicodeName)
+		m.domainList = append(m.domainList, searchEntry{
+			values: map[SearchParameter][]string{
+				SearchParameterName:      names,
+				SearchParameterNSLDHName: nsNames,
+				SearchParameterNSIP:      nsIPs,
+			},
+			sortKeys: map[string]string{
+				"name":             nameSortKey(names),
+				"registrationDate": eventSortKey(o.Events, protocol.EventActionRegistration),
+				"expirationDate":   eventSortKey(o.Events, protocol.EventActionExpiration),
+				"lastChangedDate":  eventSortKey(o.Events, protocol.EventActionLastChanged),
+			},
+			data: data,
+		})
+
+	case *protocol.Nameserver:
+		if err := addNames(m.nameservers, "nameserver", data, o.LDHName, o.UnicodeName, o.Handle); err != nil {
+			return err
+		}
+
+		names := searchNames(o.LDHName, o.UnicodeName)
+		m.nameserverList = append(m.nameserverList, searchEntry{
+			values: map[SearchParameter][]string{
+				SearchParameterName: names,
+				SearchParameterIP:   searchIPs(o.IPAddresses),
+			},
+			sortKeys: map[string]string{
+				"name": nameSortKey(names),
+			},
+			data: data,
+		})
+
+	case *protocol.Entity:
+		if o.Handle == "" {
+			return fmt.Errorf("entity without handle")
+		}
+		if _, ok := m.entities[o.Handle]; ok {
+			return fmt.Errorf("duplicated entity “%s”", o.Handle)
+		}
+		m.entities[o.Handle] = data
+
+		m.entityList = append(m.entityList, searchEntry{
+			values: map[SearchParameter][]string{
+				SearchParameterFN:     {o.FullName()},
+				SearchParameterHandle: {o.Handle},
+			},
+			sortKeys: map[string]string{
+				"handle": strings.ToLower(o.Handle),
+				"fn":     strings.ToLower(o.FullName()),
+			},
+			data: data,
+		})
+
+	case *protocol.IPNetwork:
+		if err := o.Validate(); err != nil {
+			return fmt.Errorf("IP network “%s”: %w", o.Handle, err)
+		}
+		start, _ := o.StartAddr()
+		end, _ := o.EndAddr()
+		for _, network := range m.networks {
+			if network.start == start.Unmap() && network.end == end.Unmap() {
+				return fmt.Errorf("duplicated IP network “%s - %s”", o.StartAddress, o.EndAddress)
+			}
+		}
+		m.networks = append(m.networks, networkEntry{start: start.Unmap(), end: end.Unmap(), data: data})
+
+	case *protocol.AS:
+		if o.StartAutnum > o.EndAutnum {
+			return fmt.Errorf("invalid autnum range “%d - %d”", o.StartAutnum, o.EndAutnum)
+		}
+		for _, autnum := range m.autnums {
+			if autnum.start == o.StartAutnum && autnum.end == o.EndAutnum {
+				return fmt.Errorf("duplicated autnum “%d - %d”", o.StartAutnum, o.EndAutnum)
+			}
+		}
+		m.autnums = append(m.autnums, autnumEntry{start: o.StartAutnum, end: o.EndAutnum, data: data})
+
+	case *protocol.Help:
+		if m.help != nil {
+			return fmt.Errorf("duplicated help")
+		}
+		m.help = data
+
+	default:
+		return fmt.Errorf("unsupported object “%T”", object)
+	}
+
+	return nil
+}
+
+// addNames indexes the object by all of its names, converted to the format
+// of the validated queries (lower case ASCII without the final dot)
+func addNames(index map[string][]byte, objectClass string, data []byte, names ...string) error {
+	var keys []string
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+
+		key, err := idna.ToASCII(strings.ToLower(name))
+		if err != nil {
+			key = strings.ToLower(name)
+		}
+		key = strings.TrimSuffix(key, ".")
+
+		if slices.Contains(keys, key) {
+			continue
+		}
+		if _, ok := index[key]; ok {
+			return fmt.Errorf("duplicated %s “%s”", objectClass, name)
+		}
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return fmt.Errorf("%s without name", objectClass)
+	}
+
+	for _, key := range keys {
+		index[key] = data
+	}
+	return nil
+}
+
+// searchNames returns the names compared with the search patterns, in lower
+// case without the final dot, both in ASCII and Unicode
+func searchNames(names ...string) []string {
+	var result []string
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+
+		name = strings.TrimSuffix(strings.ToLower(name), ".")
+		variants := []string{name}
+		if ascii, err := idna.ToASCII(name); err == nil {
+			variants = append(variants, ascii)
+		}
+		if unicode, err := idna.ToUnicode(name); err == nil {
+			variants = append(variants, unicode)
+		}
+
+		for _, variant := range variants {
+			if !slices.Contains(result, variant) {
+				result = append(result, variant)
+			}
+		}
+	}
+	return result
+}
+
+// searchIPs returns the nameserver addresses in the canonical format, the same
+// used by the IP of the search query
+func searchIPs(ipAddresses *protocol.IPAddresses) []string {
+	if ipAddresses == nil {
+		return nil
+	}
+
+	var result []string
+	for _, value := range slices.Concat(ipAddresses.V4, ipAddresses.V6) {
+		if ip, err := netip.ParseAddr(value); err == nil {
+			result = append(result, ip.Unmap().String())
+		}
+	}
+	return result
+}
+
+// nameSortKey returns the normalized name of the object. Objects identified
+// only by the handle don't have a name, so they are the last ones
+func nameSortKey(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	return names[0]
+}
+
+// eventSortKey returns the date of the first event with the action, in a
+// format that sorts like the dates
+func eventSortKey(events []protocol.Event, action protocol.EventAction) string {
+	for _, event := range events {
+		if event.Action == action && !event.Date.IsZero() {
+			return event.Date.UTC().Format("2006-01-02T15:04:05.000000000Z")
+		}
+	}
+	return ""
+}
+
+// searchEntries decodes the objects of the requested page that match the
+// search, up to one more than the limit, so the handler detects that there
+// are more objects
+func searchEntries[T any](entries []searchEntry, query SearchQuery) (SearchResults[T], error) {
+	var matches []searchEntry
+	for _, entry := range entries {
+		values := entry.values[query.Parameter]
+		if query.IP.IsValid() {
+			if slices.Contains(values, query.IP.String()) {
+				matches = append(matches, entry)
+			}
+		} else if slices.ContainsFunc(values, query.Pattern.Match) {
+			matches = append(matches, entry)
+		}
+	}
+
+	sort := query.Sort
+	if len(sort) == 0 {
+		sort = defaultSortKeys(query.Type)
+	}
+
+	slices.SortStableFunc(matches, func(a, b searchEntry) int {
+		return compareSortKeys(a, b, sort)
+	})
+
+	results := SearchResults[T]{
+		Total: len(matches),
+	}
+
+	matches = matches[min(query.Offset, len(matches)):]
+	if query.Limit > 0 && len(matches) > query.Limit+1 {
+		matches = matches[:query.Limit+1]
+	}
+
+	for _, entry := range matches {
+		item, err := decodeEntry[T](entry.data)
+		if err != nil {
+			return SearchResults[T]{}, err
+		}
+		results.Items = append(results.Items, *item)
+	}
+
+	return results, nil
+}
+
+// defaultSortKeys returns the properties that order the results when the
+// client doesn't choose one
+func defaultSortKeys(searchType SearchType) []SortKey {
+	var keys []SortKey
+	for _, property := range memorySortProperties[searchType] {
+		if property.Default {
+			keys = append(keys, SortKey{Property: property.Name})
+		}
+	}
+	return keys
+}
+
+// compareSortKeys orders the entries by the sort properties. Objects without
+// the property are always the last ones
+func compareSortKeys(a, b searchEntry, keys []SortKey) int {
+	for _, key := range keys {
+		valueA, valueB := a.sortKeys[key.Property], b.sortKeys[key.Property]
+
+		switch {
+		case valueA == valueB:
+			continue
+		case valueA == "":
+			return 1
+		case valueB == "":
+			return -1
+		}
+
+		result := strings.Compare(valueA, valueB)
+		if key.Descending {
+			result = -result
+		}
+		return result
+	}
+	return 0
+}
+
+// network returns the most specific IP network that contains the range
+func (m *memoryIndex) network(start, end netip.Addr) []byte {
+	start, end = start.Unmap(), end.Unmap()
+
+	var found *networkEntry
+	for i, network := range m.networks {
+		if network.start.Compare(start) > 0 || network.end.Compare(end) < 0 {
+			continue
+		}
+
+		if found == nil || network.start.Compare(found.start) > 0 ||
+			(network.start == found.start && network.end.Compare(found.end) < 0) {
+			found = &m.networks[i]
+		}
+	}
+
+	if found == nil {
+		return nil
+	}
+	return found.data
+}
+
+// autnum returns the smallest autnum range that contains the ASN
+func (m *memoryIndex) autnum(asn uint32) []byte {
+	var found *autnumEntry
+	for i, autnum := range m.autnums {
+		if autnum.start > asn || autnum.end < asn {
+			continue
+		}
+
+		if found == nil || autnum.end-autnum.start < found.end-found.start {
+			found = &m.autnums[i]
+		}
+	}
+
+	if found == nil {
+		return nil
+	}
+	return found.data
+}
+
+// decodeEntry decodes a new copy of the stored object
+func decodeEntry[T any](data []byte) (*T, error) {
+	if data == nil {
+		return nil, ErrNotFound
+	}
+
+	object := new(T)
+	if err := json.Unmarshal(data, object); err != nil {
+		return nil, err
+	}
+	return object, nil
+}
@@ -0,0 +1,271 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"testing"
+
+	"github.com/registrobr/rdap/protocol"
+)
+
+func TestMemoryBackend(t *testing.T) {
+	backend, err := NewMemoryBackend(
+		&protocol.Domain{ObjectClassName: "domain", Handle: "D-1", LDHName: "Example.BR."},
+		&protocol.Domain{ObjectClassName: "domain", LDHName: "xn--caf-dma.br", UnicodeName: "café.br"},
+		&protocol.NameserverSearchResults{
+			Nameservers: []protocol.Nameserver{
+				{ObjectClassName: "nameserver", LDHName: "a.dns.br"},
+				{ObjectClassName: "nameserver", LDHName: "b.dns.br"},
+			},
+		},
+		&protocol.Entity{ObjectClassName: "entity", Handle: "ABC"},
+		&protocol.IPNetwork{ObjectClassName: "ip network", Handle: "NET-1", IPVersion: "v4", StartAddress: "192.0.0.0", EndAddress: "192.0.255.255"},
+		&protocol.IPNetwork{ObjectClassName: "ip network", Handle: "NET-2", IPVersion: "v4", StartAddress: "192.0.2.0", EndAddress: "192.0.2.255"},
+		&protocol.IPNetwork{ObjectClassName: "ip network", Handle: "NET-3", IPVersion: "v6", StartAddress: "2001:db8::", EndAddress: "2001:db8::ffff"},
+		&protocol.AS{ObjectClassName: "autnum", Handle: "AS-1", StartAutnum: 64496, EndAutnum: 64511},
+		&protocol.AS{ObjectClassName: "autnum", Handle: "AS-2", StartAutnum: 64500, EndAutnum: 64501},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	handle := func(object protocol.Object, err error) string {
+		if err != nil {
+			return err.Error()
+		}
+		return object.GetHandle()
+	}
+	ldhName := func(domain *protocol.Domain, err error) string {
+		if err != nil {
+			return err.Error()
+		}
+		return domain.LDHName
+	}
+	nameserver := func(nameserver *protocol.Nameserver, err error) string {
+		if err != nil {
+			return err.Error()
+		}
+		return nameserver.LDHName
+	}
+
+	data := []struct {
+		description string
+		result      string
+		expected    string
+	}{
+		{
+			description: "it should find a domain by the normalized ldhName",
+			result:      ldhName(backend.Domain(ctx, "example.br")),
+			expected:    "Example.BR.",
+		},
+		{
+			description: "it should find a domain by handle",
+			result:      ldhName(backend.Domain(ctx, "d-1")),
+			expected:    "Example.BR.",
+		},
+		{
+			description: "it should find a domain by unicodeName",
+			result:      ldhName(backend.Domain(ctx, "xn--caf-dma.br")),
+			expected:    "xn--caf-dma.br",
+		},
+		{
+			description: "it should answer not found for unknown domains",
+			result:      ldhName(backend.Domain(ctx, "unknown.br")),
+			expected:    "not found",
+		},
+		{
+			description: "it should find the items of search results",
+			result:      nameserver(backend.Nameserver(ctx, "b.dns.br")),
+			expected:    "b.dns.br",
+		},
+		{
+			description: "it should find an entity by handle",
+			result:      handle(backend.Entity(ctx, "ABC")),
+			expected:    "ABC",
+		},
+		{
+			description: "it should find the most specific network of an address",
+			result:      handle(backend.IP(ctx, netip.MustParseAddr("192.0.2.10"))),
+			expected:    "NET-2",
+		},
+		{
+			description: "it should find the less specific network when the address is outside the more specific",
+			result:      handle(backend.IP(ctx, netip.MustParseAddr("192.0.3.10"))),
+			expected:    "NET-1",
+		},
+		{
+			description: "it should find the network that contains the whole prefix",
+			result:      handle(backend.IPNetwork(ctx, netip.MustParsePrefix("192.0.2.0/23"))),
+			expected:    "NET-1",
+		},
+		{
+			description: "it should find an IPv6 network",
+			result:      handle(backend.IPNetwork(ctx, netip.MustParsePrefix("2001:db8::/120"))),
+			expected:    "NET-3",
+		},
+		{
+			description: "it should answer not found for prefixes larger than the networks",
+			result:      handle(backend.IPNetwork(ctx, netip.MustParsePrefix("2001:db8::/32"))),
+			expected:    "not found",
+		},
+		{
+			description: "it should find the smallest autnum range",
+			result:      handle(backend.Autnum(ctx, 64501)),
+			expected:    "AS-2",
+		},
+		{
+			description: "it should find the larger autnum range",
+			result:      handle(backend.Autnum(ctx, 64511)),
+			expected:    "AS-1",
+		},
+		{
+			description: "it should answer not found for unknown autnums",
+			result:      handle(backend.Autnum(ctx, 1)),
+			expected:    "not found",
+		},
+	}
+
+	for i, item := range data {
+		if item.result != item.expected {
+			t.Errorf("[%d] %s: expected “%s”, got “%s”", i, item.description, item.expected, item.result)
+		}
+	}
+
+	// each query must return a copy, so the middlewares don't change the
+	// stored objects
+	domain, _ := backend.Domain(ctx, "example.br")
+	domain.Notices = append(domain.Notices, protocol.Notice{Title: "Changed"})
+
+	if domain, _ := backend.Domain(ctx, "example.br"); len(domain.Notices) > 0 {
+		t.Error("the stored domain was changed")
+	}
+
+	if _, err := backend.Ticket(ctx, 1); !errors.Is(err, ErrNotImplemented) {
+		t.Errorf("unexpected ticket error “%v”", err)
+	}
+}
+
+func TestMemoryBackendLoad(t *testing.T) {
+	data := []struct {
+		description   string
+		objects       []protocol.Object
+		expectedError error
+	}{
+		{
+			description: "it should detect duplicated domains",
+			objects: []protocol.Object{
+				&protocol.Domain{LDHName: "example.br"},
+				&protocol.Domain{LDHName: "EXAMPLE.BR."},
+			},
+			expectedError: fmt.Errorf("duplicated domain “EXAMPLE.BR.”"),
+		},
+		{
+			description: "it should detect domains without name",
+			objects: []protocol.Object{
+				&protocol.Domain{ObjectClassName: "domain"},
+			},
+			expectedError: fmt.Errorf("domain without name"),
+		},
+		{
+			description: "it should detect entities without handle",
+			objects: []protocol.Object{
+				&protocol.Entity{ObjectClassName: "entity"},
+			},
+			expectedError: fmt.Errorf("entity without handle"),
+		},
+		{
+			description: "it should detect duplicated IP networks",
+			objects: []protocol.Object{
+				&protocol.IPNetwork{IPVersion: "v4", StartAddress: "192.0.2.0", EndAddress: "192.0.2.255"},
+				&protocol.IPNetwork{IPVersion: "v4", StartAddress: "192.0.2.0", EndAddress: "192.0.2.255"},
+			},
+			expectedError: fmt.Errorf("duplicated IP network “192.0.2.0 - 192.0.2.255”"),
+		},
+		{
+			description: "it should detect invalid autnum ranges",
+			objects: []protocol.Object{
+				&protocol.AS{StartAutnum: 2, EndAutnum: 1},
+			},
+			expectedError: fmt.Errorf("invalid autnum range “2 - 1”"),
+		},
+		{
+			description: "it should reject unsupported objects",
+			objects: []protocol.Object{
+				&protocol.Error{ErrorCode: 404},
+			},
+			expectedError: fmt.Errorf("unsupported object “*protocol.Error”"),
+		},
+	}
+
+	for i, item := range data {
+		backend, err := NewMemoryBackend(&protocol.Entity{Handle: "KEEP"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = backend.Load(item.objects...)
+		if fmt.Sprintf("%v", item.expectedError) != fmt.Sprintf("%v", err) {
+			t.Errorf("[%d] %s: expected error “%v”, got “%v”", i, item.description, item.expectedError, err)
+		}
+
+		if _, err := backend.Entity(context.Background(), "KEEP"); err != nil {
+			t.Errorf("[%d] %s: the current objects weren't kept", i, item.description)
+		}
+	}
+}
+
+func TestMemoryBackendHandleOnly(t *testing.T) {
+	backend, err := NewMemoryBackend(
+		&protocol.Domain{
+			ObjectClassName: "domain",
+			Handle:          "DOM-1",
+			Nameservers:     []protocol.Nameserver{{ObjectClassName: "nameserver", LDHName: "a.dns.br"}},
+		},
+		&protocol.Domain{
+			ObjectClassName: "domain",
+			LDHName:         "example.br",
+			Nameservers:     []protocol.Nameserver{{ObjectClassName: "nameserver", LDHName: "a.dns.br"}},
+		},
+		&protocol.Nameserver{ObjectClassName: "nameserver", Handle: "NS-1", IPAddresses: &protocol.IPAddresses{V4: []string{"192.0.2.1"}}},
+		&protocol.Nameserver{ObjectClassName: "nameserver", LDHName: "a.dns.br", IPAddresses: &protocol.IPAddresses{V4: []string{"192.0.2.1"}}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if domain, err := backend.Domain(ctx, "dom-1"); err != nil || domain.Handle != "DOM-1" {
+		t.Errorf("unexpected domain “%#v” (%v)", domain, err)
+	}
+
+	domains, err := backend.SearchDomains(ctx, SearchQuery{
+		Type:      SearchTypeDomains,
+		Parameter: SearchParameterNSLDHName,
+		Pattern:   SearchPattern{Prefix: "a.dns.br"},
+		Limit:     10,
+		Sort:      []SortKey{{Property: "name"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(domains.Items) != 2 || domains.Items[0].LDHName != "example.br" || domains.Items[1].Handle != "DOM-1" {
+		t.Errorf("unexpected domains “%#v”", domains.Items)
+	}
+
+	nameservers, err := backend.SearchNameservers(ctx, SearchQuery{
+		Type:      SearchTypeNameservers,
+		Parameter: SearchParameterIP,
+		IP:        netip.MustParseAddr("192.0.2.1"),
+		Limit:     10,
+		Sort:      []SortKey{{Property: "name", Descending: true}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nameservers.Items) != 2 || nameservers.Items[0].LDHName != "a.dns.br" || nameservers.Items[1].Handle != "NS-1" {
+		t.Errorf("unexpected nameservers “%#v”", nameservers.Items)
+	}
+}
@@ -0,0 +1,222 @@
+package server
+
+import (
+	"iter"
+	"net/http"
+	"slices"
+
+	"github.com/registrobr/rdap/protocol"
+)
+
+// RDAPLevel0 is the conformance value of the RDAP responses (RFC 9083,
+// section 4.1)
+const RDAPLevel0 = "rdap_level_0"
+
+// Middleware changes the top-level response object before it is written. It is
+// also called for the error responses
+type Middleware func(r *http.Request, object protocol.Object)
+
+// ExtensionBackend is implemented by backends whose objects contain members of
+// RDAP extensions, like the NIC.br extension or cidr0
+type ExtensionBackend interface {
+	Backend
+
+	// Extensions returns the conformance values of the extensions used in the
+	// responses, like protocol.NICBRIdentifier
+	Extensions() []string
+}
+
+// Conformance fills the rdapConformance member of the top-level object with
+// "rdap_level_0" and the extensions of the backend, when it implements
+// ExtensionBackend. Search results with paging or sorting metadata also get
+// the "paging" and "sorting" values (RFC 8977, section 8), and the responses
+// with subsetting metadata get "subsetting" (RFC 8982, section 7). The member
+// is removed from the nested objects, as it must appear only in the topmost
+// object (RFC 9083, section 4.1)
+func Conformance(backend Backend) Middleware {
+	levels := []string{RDAPLevel0}
+	if extensionBackend, ok := backend.(ExtensionBackend); ok {
+		for _, extension := range extensionBackend.Extensions() {
+			if !slices.Contains(levels, extension) {
+				levels = append(levels, extension)
+			}
+		}
+	}
+
+	return func(r *http.Request, object protocol.Object) {
+		setter, ok := object.(protocol.ConformanceSetter)
+		if !ok {
+			return
+		}
+		setter.SetConformance(append(slices.Clone(levels), metadataLevels(object)...))
+
+		for child := range nestedObjects(object) {
+			if setter, ok := child.(protocol.ConformanceSetter); ok && len(child.GetConformance()) > 0 {
+				setter.SetConformance(nil)
+			}
+		}
+	}
+}
+
+// metadataLevels returns the conformance values of the RFC 8977 and RFC 8982
+// members of the search results and help responses
+func metadataLevels(object protocol.Object) []string {
+	var paging *protocol.PagingMetadata
+	var sorting *protocol.SortingMetadata
+	var subsetting *protocol.SubsettingMetadata
+
+	switch o := object.(type) {
+	case *protocol.DomainSearchResults:
+		paging, sorting, subsetting = o.PagingMetadata, o.SortingMetadata, o.SubsettingMetadata
+	case *protocol.NameserverSearchResults:
+		paging, sorting, subsetting = o.PagingMetadata, o.SortingMetadata, o.SubsettingMetadata
+	case *protocol.EntitySearchResults:
+		paging, sorting, subsetting = o.PagingMetadata, o.SortingMetadata, o.SubsettingMetadata
+	case *protocol.Help:
+		subsetting = o.SubsettingMetadata
+	}
+
+	var levels []string
+	if sorting != nil {
+		levels = append(levels, protocol.SortingIdentifier)
+	}
+	if paging != nil {
+		levels = append(levels, protocol.PagingIdentifier)
+	}
+	if subsetting != nil {
+		levels = append(levels, protocol.SubsettingIdentifier)
+	}
+	return levels
+}
+
+// Port43 fills the port43 member of the top-level object with the WHOIS
+// server address (RFC 9083, section 4.7). Error responses are left untouched
+func Port43(whois string) Middleware {
+	return func(r *http.Request, object protocol.Object) {
+		if _, ok := object.(*protocol.Error); ok {
+			return
+		}
+
+		if setter, ok := object.(protocol.Port43Setter); ok {
+			setter.SetPort43(whois)
+		}
+	}
+}
+
+// Notices appends the notices to the ones of the top-level object (RFC 9083,
+// section 4.3). It is used for the notices configured once per deployment,
+// like TermsOfServiceNotice and StatusCodesNotice
+func Notices(notices ...protocol.Notice) Middleware {
+	return func(r *http.Request, object protocol.Object) {
+		switch o := object.(type) {
+		case *protocol.Domain:
+			o.Notices = appendNotices(o.Notices, notices)
+		case *protocol.Entity:
+			o.Notices = appendNotices(o.Notices, notices)
+		case *protocol.Nameserver:
+			o.Notices = appendNotices(o.Notices, notices)
+		case *protocol.IPNetwork:
+			o.Notices = appendNotices(o.Notices, notices)
+		case *protocol.AS:
+			o.Notices = appendNotices(o.Notices, notices)
+		case *protocol.Help:
+			o.Notices = appendNotices(o.Notices, notices)
+		case *protocol.Error:
+			o.Notices = appendNotices(o.Notices, notices)
+		case *protocol.DomainSearchResults:
+			o.Notices = appendNotices(o.Notices, notices)
+		case *protocol.NameserverSearchResults:
+			o.Notices = appendNotices(o.Notices, notices)
+		case *protocol.EntitySearchResults:
+			o.Notices = appendNotices(o.Notices, notices)
+		}
+	}
+}
+
+// TermsOfServiceNotice builds the notice that points to the terms of service
+// of the RDAP service
+func TermsOfServiceNotice(href string) protocol.Notice {
+	return protocol.Notice{
+		Title: "Terms of Service",
+		Description: []string{
+			"By querying this service, you agree to its terms of service.",
+		},
+		Links: []protocol.Link{
+			{
+				Value: href,
+				Rel:   "terms-of-service",
+				Href:  href,
+				Type:  "text/html",
+			},
+		},
+	}
+}
+
+// StatusCodesNotice builds the notice that explains where to find the meaning
+// of the EPP status codes (RFC 8056)
+func StatusCodesNotice() protocol.Notice {
+	return protocol.Notice{
+		Title: "Status Codes",
+		Description: []string{
+			"For more information on domain status codes, please visit https://icann.org/epp",
+		},
+		Links: []protocol.Link{
+			{
+				Value: "https://icann.org/epp",
+				Rel:   "glossary",
+				Href:  "https://icann.org/epp",
+				Type:  "text/html",
+			},
+		},
+	}
+}
+
+// appendNotices adds the notices without changing the backing array of the
+// current ones, that may be shared by other objects of the backend
+func appendNotices(current, notices []protocol.Notice) []protocol.Notice {
+	return append(slices.Clip(current), notices...)
+}
+
+// nestedObjects iterates over all objects inside the top-level object,
+// including the items of the search results
+func nestedObjects(object protocol.Object) iter.Seq[protocol.Object] {
+	return func(yield func(protocol.Object) bool) {
+		var items []protocol.Object
+
+		switch o := object.(type) {
+		case *protocol.DomainSearchResults:
+			for i := range o.Domains {
+				items = append(items, &o.Domains[i])
+			}
+		case *protocol.NameserverSearchResults:
+			for i := range o.Nameservers {
+				items = append(items, &o.Nameservers[i])
+			}
+		case *protocol.EntitySearchResults:
+			for i := range o.Entities {
+				items = append(items, &o.Entities[i])
+			}
+		default:
+			items = []protocol.Object{object}
+		}
+
+		for _, item := range items {
+			if item != object && !yield(item) {
+				return
+			}
+
+			nested, ok := item.(interface {
+				Nested() iter.Seq[protocol.Object]
+			})
+			if !ok {
+				continue
+			}
+
+			for child := range nested.Nested() {
+				if !yield(child) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/registrobr/rdap/protocol"
+)
+
+type extensionBackend struct {
+	UnimplementedBackend
+}
+
+func (extensionBackend) Extensions() []string {
+	return []string{protocol.NICBRIdentifier, protocol.CIDR0Identifier, RDAPLevel0}
+}
+
+func TestConformance(t *testing.T) {
+	data := []struct {
+		description string
+		backend     Backend
+		object      protocol.Object
+		expected    protocol.Object
+	}{
+		{
+			description: "it should add only rdap_level_0 for backends without extensions",
+			backend:     UnimplementedBackend{},
+			object:      &protocol.Nameserver{LDHName: "a.dns.br"},
+			expected: &protocol.Nameserver{
+				LDHName:     "a.dns.br",
+				Conformance: protocol.Conformance{Levels: []string{"rdap_level_0"}},
+			},
+		},
+		{
+			description: "it should add the backend extensions without duplicates",
+			backend:     extensionBackend{},
+			object:      &protocol.Error{ErrorCode: http.StatusNotFound},
+			expected: &protocol.Error{
+				ErrorCode:   http.StatusNotFound,
+				Conformance: protocol.Conformance{Levels: []string{"rdap_level_0", "nicbr_level_0", "cidr0"}},
+			},
+		},
+		{
+			description: "it should remove the conformance of nested objects",
+			backend:     UnimplementedBackend{},
+			object: &protocol.Domain{
+				LDHName: "example.br",
+				Entities: []protocol.Entity{
+					{
+						Handle:      "XXXX",
+						Conformance: protocol.Conformance{Levels: []string{"rdap_level_0"}},
+						Networks: []protocol.IPNetwork{
+							{
+								Handle:      "192.0.2.0/24",
+								Conformance: protocol.Conformance{Levels: []string{"rdap_level_0"}},
+							},
+						},
+					},
+				},
+			},
+			expected: &protocol.Domain{
+				LDHName:     "example.br",
+				Conformance: protocol.Conformance{Levels: []string{"rdap_level_0"}},
+				Entities: []protocol.Entity{
+					{
+						Handle: "XXXX",
+						Networks: []protocol.IPNetwork{
+							{Handle: "192.0.2.0/24"},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "it should remove the conformance of search results",
+			backend:     UnimplementedBackend{},
+			object: &protocol.DomainSearchResults{
+				Domains: []protocol.Domain{
+					{
+						LDHName:     "example.br",
+						Conformance: protocol.Conformance{Levels: []string{"rdap_level_0"}},
+					},
+				},
+			},
+			expected: &protocol.DomainSearchResults{
+				Conformance: protocol.Conformance{Levels: []string{"rdap_level_0"}},
+				Domains: []protocol.Domain{
+					{LDHName: "example.br"},
+				},
+			},
+		},
+		{
+			description: "it should add the paging and sorting values",
+			backend:     UnimplementedBackend{},
+			object: &protocol.EntitySearchResults{
+				Entities:        []protocol.Entity{},
+				PagingMetadata:  &protocol.PagingMetadata{PageSize: 10, PageNumber: 1},
+				SortingMetadata: &protocol.SortingMetadata{CurrentSort: "handle"},
+			},
+			expected: &protocol.EntitySearchResults{
+				Entities:        []protocol.Entity{},
+				PagingMetadata:  &protocol.PagingMetadata{PageSize: 10, PageNumber: 1},
+				SortingMetadata: &protocol.SortingMetadata{CurrentSort: "handle"},
+				Conformance:     protocol.Conformance{Levels: []string{"rdap_level_0", "sorting", "paging"}},
+			},
+		},
+	}
+
+	for i, item := range data {
+		r := httptest.NewRequest(http.MethodGet, "/help", nil)
+		Conformance(item.backend)(r, item.object)
+
+		if !reflect.DeepEqual(item.expected, item.object) {
+			t.Errorf("[%d] %s: expected object “%#v”, got “%#v”", i, item.description, item.expected, item.object)
+		}
+	}
+}
+
+func TestPort43(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/entity/XXXX", nil)
+
+	entity := &protocol.Entity{
+		Handle:   "XXXX",
+		Entities: []protocol.Entity{{Handle: "YYYY"}},
+	}
+	Port43("whois.nic.br")(r, entity)
+
+	if entity.Port43.Port43 != "whois.nic.br" {
+		t.Errorf("unexpected port43 “%s”", entity.Port43.Port43)
+	}
+
+	if entity.Entities[0].Port43.Port43 != "" {
+		t.Errorf("unexpected port43 “%s” in nested entity", entity.Entities[0].Port43.Port43)
+	}
+
+	nameserver := &protocol.Nameserver{LDHName: "a.dns.br"}
+	Port43("whois.nic.br")(r, nameserver)
+
+	if nameserver.Port43.Port43 != "whois.nic.br" {
+		t.Errorf("unexpected port43 “%s” in nameserver", nameserver.Port43.Port43)
+	}
+
+	errorResponse := &protocol.Error{ErrorCode: http.StatusNotFound}
+	Port43("whois.nic.br")(r, errorResponse)
+
+	if errorResponse.Port43.Port43 != "" {
+		t.Errorf("unexpected port43 “%s” in error response", errorResponse.Port43.Port43)
+	}
+}
+
+func TestNotices(t *testing.T) {
+	notices := []protocol.Notice{
+		TermsOfServiceNotice("https://registro.br/termo/"),
+		StatusCodesNotice(),
+	}
+
+	shared := make([]protocol.Notice, 1, 10)
+	shared[0] = protocol.Notice{Title: "Backend"}
+
+	domain := &protocol.Domain{Notices: shared}
+	handler := &Handler{Backend: UnimplementedBackend{}}
+	handler.Use(Notices(notices...))
+
+	r := httptest.NewRequest(http.MethodGet, "/domain/example.br", nil)
+	for _, middleware := range handler.Middlewares {
+		middleware(r, domain)
+	}
+
+	expected := append([]protocol.Notice{{Title: "Backend"}}, notices...)
+	if !reflect.DeepEqual(expected, domain.Notices) {
+		t.Errorf("expected notices “%#v”, got “%#v”", expected, domain.Notices)
+	}
+
+	if extra := shared[:2][1]; !reflect.DeepEqual(extra, protocol.Notice{}) {
+		t.Errorf("notices were appended to the backend array: “%#v”", extra)
+	}
+}
+
+func TestNewHandlerMiddlewares(t *testing.T) {
+	handler := NewHandler(extensionBackend{})
+	handler.Use(Port43("whois.nic.br"), Notices(StatusCodesNotice()))
+
+	r := httptest.NewRequest(http.MethodGet, "/domain/example.br", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	expected := `{"notices":[{"title":"Status Codes","description":["For more information on domain status codes, please visit https://icann.org/epp"],"links":[{"value":"https://icann.org/epp","rel":"glossary","href":"https://icann.org/epp","type":"text/html"}]}],"errorCode":501,"title":"Not Implemented","rdapConformance":["rdap_level_0","nicbr_level_0","cidr0"]}` + "\n"
+
+	if body := w.Body.String(); body != expected {
+		t.Errorf("unexpected body “%s”", body)
+	}
+}
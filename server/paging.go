@@ -0,0 +1,310 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/registrobr/rdap/protocol"
+)
+
+// SortProperty is a property that the backend can sort the search results by
+// (RFC 8977, section 2.2)
+type SortProperty struct {
+	// Name is the value of the sort parameter, like "name" or
+	// "registrationDate"
+	Name string
+
+	// JSONPath locates the property in the search results. It is informed to
+	// the clients in the sorting metadata
+	JSONPath string
+
+	// Default is true for the property used when the client doesn't choose one
+	Default bool
+}
+
+// SortKey is one of the properties of the sort parameter, in the order of
+// precedence
+type SortKey struct {
+	Property   string
+	Descending bool
+}
+
+// SortingBackend is implemented by search backends that sort the results. The
+// sort parameter is accepted only with the declared properties, and the
+// sorting metadata lists them in the responses
+type SortingBackend interface {
+	SearchBackend
+
+	// SortProperties returns the properties supported by the search type
+	SortProperties(searchType SearchType) []SortProperty
+}
+
+// cursor stores the position of the next page. It is signed by the handler,
+// so the clients can't change it
+type cursor struct {
+	Offset int    `json:"o"`
+	Limit  int    `json:"l"`
+	Search string `json:"s"`
+}
+
+// encodeCursor returns the opaque value of the cursor parameter, that is the
+// cursor in JSON followed by its HMAC-SHA256, encoded in base64 (RFC 4648,
+// section 5)
+func (h *Handler) encodeCursor(c cursor) string {
+	data, _ := json.Marshal(c)
+
+	mac := hmac.New(sha256.New, h.CursorKey)
+	mac.Write(data)
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(data))
+}
+
+// decodeCursor checks the signature of the cursor parameter
+func (h *Handler) decodeCursor(value string) (cursor, bool) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(data) <= sha256.Size {
+		return cursor{}, false
+	}
+	data, signature := data[:len(data)-sha256.Size], data[len(data)-sha256.Size:]
+
+	mac := hmac.New(sha256.New, h.CursorKey)
+	mac.Write(data)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return cursor{}, false
+	}
+
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Offset < 0 || c.Limit <= 0 {
+		return cursor{}, false
+	}
+	return c, true
+}
+
+// searchDigest identifies the search of a cursor, so it can't be used with
+// other search parameters or sort order
+func searchDigest(query SearchQuery) string {
+	value := query.Pattern.String()
+	if query.IP.IsValid() {
+		value = query.IP.String()
+	}
+
+	digest := sha256.Sum256([]byte(strings.Join([]string{
+		string(query.Type), string(query.Parameter), value, formatSort(query.Sort),
+	}, "\n")))
+	return base64.RawURLEncoding.EncodeToString(digest[:12])
+}
+
+// parsePaging fills the sorting and paging attributes of the search query
+// from the sort, count, limit and cursor parameters (RFC 8977, section 2).
+// The limit parameter isn't defined by RFC 8977, so it can only reduce the
+// page size
+func (h *Handler) parsePaging(path string, values url.Values, properties []SortProperty, query *SearchQuery) error {
+	fail := func(format string, a ...any) error {
+		return &QueryError{
+			Path:   path,
+			Reason: fmt.Sprintf(format, a...),
+		}
+	}
+
+	var err error
+	if query.Sort, err = parseSort(values.Get("sort"), properties); err != nil {
+		return fail("%s", err)
+	}
+
+	switch count := values.Get("count"); count {
+	case "", "false":
+	case "true":
+		query.Count = true
+	default:
+		return fail("invalid count “%s”", count)
+	}
+
+	if limit := values.Get("limit"); limit != "" {
+		pageSize, err := strconv.Atoi(limit)
+		if err != nil || pageSize <= 0 {
+			return fail("invalid limit “%s”", limit)
+		}
+		query.Limit = min(query.Limit, pageSize)
+	}
+
+	value := values.Get("cursor")
+	if value == "" {
+		return nil
+	}
+
+	c, ok := h.decodeCursor(value)
+	if !ok || len(h.CursorKey) == 0 || c.Search != searchDigest(*query) {
+		return fail("invalid cursor")
+	}
+
+	query.Offset, query.Limit = c.Offset, c.Limit
+	return nil
+}
+
+// parseSort parses the sort parameter, a comma separated list of properties
+// with an optional ":a" (ascending) or ":d" (descending) suffix
+func parseSort(value string, properties []SortProperty) ([]SortKey, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var keys []SortKey
+	for item := range strings.SplitSeq(value, ",") {
+		property, order, _ := strings.Cut(item, ":")
+
+		if !slices.ContainsFunc(properties, func(p SortProperty) bool { return p.Name == property }) {
+			return nil, fmt.Errorf("unsupported sort property “%s”", property)
+		}
+
+		if slices.ContainsFunc(keys, func(key SortKey) bool { return key.Property == property }) {
+			return nil, fmt.Errorf("repeated sort property “%s”", property)
+		}
+
+		key := SortKey{Property: property}
+		switch order {
+		case "", "a":
+		case "d":
+			key.Descending = true
+		default:
+			return nil, fmt.Errorf("invalid sort order “%s”", order)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// formatSort returns the sort keys in the format of the sort parameter
+func formatSort(keys []SortKey) string {
+	items := make([]string, len(keys))
+	for i, key := range keys {
+		items[i] = key.Property
+		if key.Descending {
+			items[i] += ":d"
+		}
+	}
+	return strings.Join(items, ",")
+}
+
+// searchURLs returns the URL of the search type, used to build the links of
+// the search results, and the URL of the current request
+func searchURLs(h *Handler, r *http.Request, searchType SearchType) (base, current string) {
+	base = BaseURL(r, h.TrustedProxies...) + string(searchType)
+	current = base
+	if r.URL.RawQuery != "" {
+		current += "?" + r.URL.RawQuery
+	}
+	return base, current
+}
+
+// searchMetadata stores the members shared by all search results
+type searchMetadata struct {
+	remarks []protocol.Remark
+	paging  *protocol.PagingMetadata
+	sorting *protocol.SortingMetadata
+}
+
+// paginate limits the items to the page size. When the handler has a cursor
+// key, the paging metadata links to the next page; otherwise the bigger result
+// sets are truncated with a remark that explains why (RFC 9083, section
+// 10.2.1)
+func paginate[T any](h *Handler, r *http.Request, query SearchQuery, properties []SortProperty, results SearchResults[T]) ([]T, searchMetadata) {
+	var metadata searchMetadata
+
+	items := results.Items
+	reason := results.Truncated
+
+	more := query.Limit > 0 && len(items) > query.Limit
+	if more {
+		items = items[:query.Limit]
+	}
+
+	if items == nil {
+		// the search results member is required even without results
+		items = []T{}
+	}
+
+	base, current := searchURLs(h, r, query.Type)
+
+	if len(h.CursorKey) > 0 {
+		metadata.paging = &protocol.PagingMetadata{
+			PageSize:   query.Limit,
+			PageNumber: query.Offset/query.Limit + 1,
+		}
+
+		if query.Count {
+			total := results.Total
+			metadata.paging.TotalCount = &total
+		}
+
+		if more {
+			values := r.URL.Query()
+			values.Set("cursor", h.encodeCursor(cursor{
+				Offset: query.Offset + query.Limit,
+				Limit:  query.Limit,
+				Search: searchDigest(query),
+			}))
+
+			metadata.paging.Links = []protocol.Link{
+				{
+					Value: current,
+					Rel:   "next",
+					Href:  base + "?" + values.Encode(),
+					Type:  "application/rdap+json",
+				},
+			}
+		}
+
+	} else if more && reason == "" {
+		reason = protocol.RemarkTypeResultTruncatedUnexplainableReasons
+	}
+
+	if reason != "" {
+		metadata.remarks = []protocol.Remark{
+			{
+				Title: "Result Set Truncated",
+				Type:  string(reason),
+				Description: []string{
+					fmt.Sprintf("The result set was limited to %d objects.", len(items)),
+				},
+			},
+		}
+	}
+
+	if len(properties) > 0 {
+		metadata.sorting = &protocol.SortingMetadata{
+			CurrentSort: formatSort(query.Sort),
+		}
+
+		for _, property := range properties {
+			values := r.URL.Query()
+			values.Del("cursor")
+			values.Set("sort", property.Name)
+
+			metadata.sorting.AvailableSorts = append(metadata.sorting.AvailableSorts, protocol.AvailableSort{
+				Property: property.Name,
+				JSONPath: property.JSONPath,
+				Default:  property.Default,
+				Links: []protocol.Link{
+					{
+						Value: current,
+						Rel:   "alternate",
+						Href:  base + "?" + values.Encode(),
+						Type:  "application/rdap+json",
+					},
+				},
+			})
+		}
+	}
+
+	return items, metadata
+}
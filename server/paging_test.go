@@ -0,0 +1,209 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/registrobr/rdap/protocol"
+)
+
+func TestParseSort(t *testing.T) {
+	properties := []SortProperty{{Name: "name"}, {Name: "registrationDate"}}
+
+	data := []struct {
+		description   string
+		value         string
+		expected      []SortKey
+		expectedError error
+	}{
+		{
+			description: "it should parse the sort properties in order",
+			value:       "registrationDate:d,name:a",
+			expected: []SortKey{
+				{Property: "registrationDate", Descending: true},
+				{Property: "name"},
+			},
+		},
+		{
+			description: "it should accept an empty sort",
+		},
+		{
+			description:   "it should reject unsupported properties",
+			value:         "name,handle",
+			expectedError: fmt.Errorf("unsupported sort property “handle”"),
+		},
+		{
+			description:   "it should reject repeated properties",
+			value:         "name:a,name:d",
+			expectedError: fmt.Errorf("repeated sort property “name”"),
+		},
+		{
+			description:   "it should reject invalid orders",
+			value:         "name:x",
+			expectedError: fmt.Errorf("invalid sort order “x”"),
+		},
+	}
+
+	for i, item := range data {
+		keys, err := parseSort(item.value, properties)
+
+		if item.expectedError != nil {
+			if fmt.Sprintf("%v", item.expectedError) != fmt.Sprintf("%v", err) {
+				t.Errorf("[%d] %s: expected error “%v”, got “%v”", i, item.description, item.expectedError, err)
+			}
+
+		} else if err != nil {
+			t.Errorf("[%d] %s: unexpected error “%s”", i, item.description, err)
+
+		} else if !reflect.DeepEqual(item.expected, keys) {
+			t.Errorf("[%d] %s: expected “%#v”, got “%#v”", i, item.description, item.expected, keys)
+		}
+	}
+}
+
+func TestHandlerPaging(t *testing.T) {
+	var objects []protocol.Object
+	for i := 1; i <= 5; i++ {
+		objects = append(objects, &protocol.Domain{
+			ObjectClassName: "domain",
+			LDHName:         fmt.Sprintf("example%d.br", i),
+			Events: []protocol.Event{
+				{
+					Action: protocol.EventActionRegistration,
+					Date:   protocol.NewEventDate(time.Date(2020, time.January, 6-i, 0, 0, 0, 0, time.UTC)),
+				},
+			},
+		})
+	}
+
+	backend, err := NewMemoryBackend(objects...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := NewHandler(backend)
+	handler.SearchLimit = 2
+	handler.CursorKey = []byte("secret")
+
+	search := func(target string) (int, *protocol.DomainSearchResults) {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		var results protocol.DomainSearchResults
+		if w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), &results); err != nil {
+				t.Fatalf("invalid body “%s”: %s", w.Body, err)
+			}
+		}
+		return w.Code, &results
+	}
+
+	names := func(results *protocol.DomainSearchResults) []string {
+		var names []string
+		for _, domain := range results.Domains {
+			names = append(names, domain.LDHName)
+		}
+		return names
+	}
+
+	// follow the next links until the last page
+	var pages [][]string
+	target := "/domains?name=example*&sort=registrationDate:d&count=true"
+	for target != "" {
+		status, results := search(target)
+		if status != http.StatusOK {
+			t.Fatalf("unexpected status %d for “%s”", status, target)
+		}
+		pages = append(pages, names(results))
+
+		if results.PagingMetadata == nil {
+			t.Fatal("missing paging metadata")
+		}
+
+		if total := results.PagingMetadata.TotalCount; total == nil || *total != 5 {
+			t.Errorf("unexpected total count %v", total)
+		}
+
+		if number := results.PagingMetadata.PageNumber; number != len(pages) {
+			t.Errorf("expected page number %d, got %d", len(pages), number)
+		}
+
+		if results.SortingMetadata == nil || results.SortingMetadata.CurrentSort != "registrationDate:d" {
+			t.Errorf("unexpected sorting metadata “%#v”", results.SortingMetadata)
+		}
+
+		expectedLevels := []string{"rdap_level_0", "sorting", "paging", "subsetting"}
+		if !reflect.DeepEqual(expectedLevels, results.Levels) {
+			t.Errorf("expected conformance “%v”, got “%v”", expectedLevels, results.Levels)
+		}
+
+		target = ""
+		if next := results.PagingMetadata.Next(); next != "" {
+			u, err := url.Parse(next)
+			if err != nil {
+				t.Fatal(err)
+			}
+			target = u.RequestURI()
+		}
+	}
+
+	expected := [][]string{
+		{"example1.br", "example2.br"},
+		{"example3.br", "example4.br"},
+		{"example5.br"},
+	}
+	if !reflect.DeepEqual(expected, pages) {
+		t.Errorf("expected pages “%v”, got “%v”", expected, pages)
+	}
+
+	// a smaller page size
+	_, results := search("/domains?name=example*&sort=name:d&limit=1")
+	if got := names(results); !reflect.DeepEqual([]string{"example5.br"}, got) {
+		t.Errorf("unexpected page “%v”", got)
+	}
+	if results.PagingMetadata.PageSize != 1 || results.PagingMetadata.TotalCount != nil {
+		t.Errorf("unexpected paging metadata “%#v”", results.PagingMetadata)
+	}
+
+	if sorts := results.SortingMetadata.AvailableSorts; len(sorts) == 0 || sorts[0].Property != "name" || !sorts[0].Default {
+		t.Errorf("expected name as the default sort property, got “%#v”", sorts)
+	}
+
+	next, err := url.Parse(results.PagingMetadata.Next())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cursor := next.Query().Get("cursor")
+
+	for i, target := range []string{
+		"/domains?name=example*&sort=name:a&cursor=" + cursor,
+		"/domains?name=other*&sort=name:d&cursor=" + cursor,
+		"/domains?name=example*&sort=name:d&cursor=" + strings.ToUpper(cursor),
+		"/domains?name=example*&sort=handle",
+		"/domains?name=example*&count=yes",
+		"/domains?name=example*&limit=0",
+	} {
+		if status, _ := search(target); status != http.StatusBadRequest {
+			t.Errorf("[%d] expected status 400 for “%s”, got %d", i, target, status)
+		}
+	}
+
+	// without cursor key the results are truncated
+	handler.CursorKey = nil
+	if status, _ := search("/domains?name=example*&sort=name:d&cursor=" + cursor); status != http.StatusBadRequest {
+		t.Errorf("expected status 400 for a cursor without cursor key, got %d", status)
+	}
+
+	_, results = search("/domains?name=example*")
+	if results.PagingMetadata != nil || len(results.Remarks) != 1 {
+		t.Errorf("expected a truncated result set, got “%#v”", results)
+	}
+}
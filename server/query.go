@@ -0,0 +1,178 @@
+package server
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+var (
+	fqdnRX = regexp.MustCompile(`^((([a-z0-9][a-z0-9\-]*[a-z0-9])|[a-z0-9]+)\.)*([a-z]+|xn\-\-[a-z0-9]+)\.?$`)
+)
+
+// List of path segments that identify the query type, as described in RFC
+// 9082, section 3.1
+const (
+	// QueryTypeDomain used to query a domain name or a reverse DNS zone
+	QueryTypeDomain QueryType = "domain"
+
+	// QueryTypeTicket used to query a domain request. This query type was
+	// created by NIC.br
+	QueryTypeTicket QueryType = "ticket"
+
+	// QueryTypeNameserver used to query a nameserver by its name
+	QueryTypeNameserver QueryType = "nameserver"
+
+	// QueryTypeEntity used to query an entity by its handle
+	QueryTypeEntity QueryType = "entity"
+
+	// QueryTypeIP used to query an IP network by one of its addresses or by a
+	// CIDR prefix
+	QueryTypeIP QueryType = "ip"
+
+	// QueryTypeAutnum used to query an autonomous system by its number
+	QueryTypeAutnum QueryType = "autnum"
+
+	// QueryTypeHelp used to retrieve the server help
+	QueryTypeHelp QueryType = "help"
+)
+
+// QueryType stores the path segment that identifies the type of the query
+type QueryType string
+
+// Query stores the parsed and validated RDAP query. Only the attribute
+// related to the query type is filled
+type Query struct {
+	Type QueryType
+
+	// Name is the domain or nameserver name in lower case ASCII (IDNA),
+	// without the final dot
+	Name string
+
+	// Handle is the entity handle
+	Handle string
+
+	// Ticket is the domain request number
+	Ticket int
+
+	// IP is the queried address of an IP query without prefix length
+	IP netip.Addr
+
+	// IPNetwork is the masked prefix of an IP query with prefix length
+	IPNetwork netip.Prefix
+
+	// ASN is the autonomous system number
+	ASN uint32
+}
+
+// QueryError is returned when the query path is malformed
+type QueryError struct {
+	Path   string
+	Reason string
+}
+
+// Error implements the error interface
+func (q *QueryError) Error() string {
+	return fmt.Sprintf("malformed query “%s”: %s", q.Path, q.Reason)
+}
+
+// ParseQuery parses the escaped URL path relative to the RDAP base URL, like
+// "domain/example.com" or "ip/192.0.2.0/24". The domain names are converted to
+// IDNA like in the client, and IP and ASN values must follow RFC 9082,
+// section 3.1. It fails with a QueryError when the path is malformed
+func ParseQuery(path string) (Query, error) {
+	fail := func(format string, a ...any) (Query, error) {
+		return Query{}, &QueryError{
+			Path:   path,
+			Reason: fmt.Sprintf(format, a...),
+		}
+	}
+
+	var segments []string
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		unescaped, err := url.PathUnescape(segment)
+		if err != nil {
+			return fail("invalid escape in “%s”", segment)
+		}
+		segments = append(segments, unescaped)
+	}
+
+	query := Query{
+		Type: QueryType(segments[0]),
+	}
+
+	switch query.Type {
+	case QueryTypeHelp:
+		if len(segments) != 1 {
+			return fail("unexpected path after help")
+		}
+		return query, nil
+
+	case QueryTypeIP:
+		if len(segments) != 2 && len(segments) != 3 {
+			return fail("expected an IP address or a CIDR prefix")
+		}
+
+	case QueryTypeDomain, QueryTypeTicket, QueryTypeNameserver, QueryTypeEntity, QueryTypeAutnum:
+		if len(segments) != 2 {
+			return fail("expected one value for the %s query", query.Type)
+		}
+
+	default:
+		return fail("unknown query type “%s”", query.Type)
+	}
+
+	value := segments[1]
+	if value == "" {
+		return fail("empty value for the %s query", query.Type)
+	}
+
+	switch query.Type {
+	case QueryTypeDomain, QueryTypeNameserver:
+		name, err := idna.ToASCII(strings.ToLower(value))
+		if err != nil || !fqdnRX.MatchString(name) {
+			return fail("invalid name “%s”", value)
+		}
+		query.Name = strings.TrimSuffix(name, ".")
+
+	case QueryTypeTicket:
+		ticket, err := strconv.Atoi(value)
+		if err != nil || ticket <= 0 {
+			return fail("invalid ticket “%s”", value)
+		}
+		query.Ticket = ticket
+
+	case QueryTypeEntity:
+		query.Handle = value
+
+	case QueryTypeAutnum:
+		asn, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return fail("invalid autonomous system number “%s”", value)
+		}
+		query.ASN = uint32(asn)
+
+	case QueryTypeIP:
+		if len(segments) == 3 {
+			ipnet, err := netip.ParsePrefix(value + "/" + segments[2])
+			if err != nil {
+				return fail("invalid CIDR prefix “%s/%s”", value, segments[2])
+			}
+			query.IPNetwork = ipnet.Masked()
+			break
+		}
+
+		ip, err := netip.ParseAddr(value)
+		if err != nil || ip.Zone() != "" {
+			return fail("invalid IP address “%s”", value)
+		}
+		query.IP = ip.Unmap()
+	}
+
+	return query, nil
+}
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"fmt"
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+	data := []struct {
+		description   string
+		path          string
+		expected      Query
+		expectedError error
+	}{
+		{
+			description: "it should parse a domain query",
+			path:        "/domain/Example.COM.",
+			expected: Query{
+				Type: QueryTypeDomain,
+				Name: "example.com",
+			},
+		},
+		{
+			description: "it should convert an IDN to ASCII",
+			path:        "/domain/%C3%A7.com.br",
+			expected: Query{
+				Type: QueryTypeDomain,
+				Name: "xn--7ca.com.br",
+			},
+		},
+		{
+			description: "it should parse a nameserver query",
+			path:        "nameserver/a.dns.br",
+			expected: Query{
+				Type: QueryTypeNameserver,
+				Name: "a.dns.br",
+			},
+		},
+		{
+			description: "it should parse an entity query",
+			path:        "/entity/XXXX%2FYYYY",
+			expected: Query{
+				Type:   QueryTypeEntity,
+				Handle: "XXXX/YYYY",
+			},
+		},
+		{
+			description: "it should parse an IP address query",
+			path:        "/ip/2001:db8::1",
+			expected: Query{
+				Type: QueryTypeIP,
+				IP:   netip.MustParseAddr("2001:db8::1"),
+			},
+		},
+		{
+			description: "it should parse and mask an IP network query",
+			path:        "/ip/192.0.2.1/24",
+			expected: Query{
+				Type:      QueryTypeIP,
+				IPNetwork: netip.MustParsePrefix("192.0.2.0/24"),
+			},
+		},
+		{
+			description: "it should parse an autnum query",
+			path:        "/autnum/4294967295",
+			expected: Query{
+				Type: QueryTypeAutnum,
+				ASN:  4294967295,
+			},
+		},
+		{
+			description: "it should parse a ticket query",
+			path:        "/ticket/123",
+			expected: Query{
+				Type:   QueryTypeTicket,
+				Ticket: 123,
+			},
+		},
+		{
+			description: "it should parse a help query",
+			path:        "/help",
+			expected: Query{
+				Type: QueryTypeHelp,
+			},
+		},
+		{
+			description: "it should fail with an unknown query type",
+			path:        "/whois/example.com",
+			expectedError: &QueryError{
+				Path:   "/whois/example.com",
+				Reason: "unknown query type “whois”",
+			},
+		},
+		{
+			description: "it should fail with an empty path",
+			path:        "/",
+			expectedError: &QueryError{
+				Path:   "/",
+				Reason: "unknown query type “”",
+			},
+		},
+		{
+			description: "it should fail without the query value",
+			path:        "/domain",
+			expectedError: &QueryError{
+				Path:   "/domain",
+				Reason: "expected one value for the domain query",
+			},
+		},
+		{
+			description: "it should fail with extra path segments",
+			path:        "/domain/example.com/extra",
+			expectedError: &QueryError{
+				Path:   "/domain/example.com/extra",
+				Reason: "expected one value for the domain query",
+			},
+		},
+		{
+			description: "it should fail with an invalid domain name",
+			path:        "/domain/-example.com",
+			expectedError: &QueryError{
+				Path:   "/domain/-example.com",
+				Reason: "invalid name “-example.com”",
+			},
+		},
+		{
+			description: "it should fail with an invalid IP address",
+			path:        "/ip/192.0.2.256",
+			expectedError: &QueryError{
+				Path:   "/ip/192.0.2.256",
+				Reason: "invalid IP address “192.0.2.256”",
+			},
+		},
+		{
+			description: "it should fail with an invalid prefix length",
+			path:        "/ip/192.0.2.0/33",
+			expectedError: &QueryError{
+				Path:   "/ip/192.0.2.0/33",
+				Reason: "invalid CIDR prefix “192.0.2.0/33”",
+			},
+		},
+		{
+			description: "it should fail with an ASN out of range",
+			path:        "/autnum/4294967296",
+			expectedError: &QueryError{
+				Path:   "/autnum/4294967296",
+				Reason: "invalid autonomous system number “4294967296”",
+			},
+		},
+		{
+			description: "it should fail with an invalid ticket",
+			path:        "/ticket/0",
+			expectedError: &QueryError{
+				Path:   "/ticket/0",
+				Reason: "invalid ticket “0”",
+			},
+		},
+		{
+			description: "it should fail with an invalid escape",
+			path:        "/entity/%zz",
+			expectedError: &QueryError{
+				Path:   "/entity/%zz",
+				Reason: "invalid escape in “%zz”",
+			},
+		},
+	}
+
+	for i, item := range data {
+		query, err := ParseQuery(item.path)
+
+		if item.expectedError != nil {
+			if fmt.Sprintf("%v", item.expectedError) != fmt.Sprintf("%v", err) {
+				t.Errorf("[%d] %s: expected error “%v”, got “%v”", i, item.description, item.expectedError, err)
+			}
+
+		} else if err != nil {
+			t.Errorf("[%d] %s: unexpected error “%s”", i, item.description, err)
+
+		} else if !reflect.DeepEqual(item.expected, query) {
+			t.Errorf("[%d] %s: expected query “%#v”, got “%#v”", i, item.description, item.expected, query)
+		}
+	}
+}
@@ -0,0 +1,341 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strings"
+
+	"github.com/registrobr/rdap/protocol"
+	"golang.org/x/net/idna"
+)
+
+// DefaultSearchLimit is the maximum number of objects in a search response
+// when the handler doesn't define one
+const DefaultSearchLimit = 100
+
+// List of path segments that identify the search type, as described in RFC
+// 9082, section 3.2
+const (
+	// SearchTypeDomains used to search domains by name, nameserver name or
+	// nameserver IP address
+	SearchTypeDomains SearchType = "domains"
+
+	// SearchTypeNameservers used to search nameservers by name or IP address
+	SearchTypeNameservers SearchType = "nameservers"
+
+	// SearchTypeEntities used to search entities by full name or handle
+	SearchTypeEntities SearchType = "entities"
+)
+
+// SearchType stores the path segment that identifies the type of the search
+type SearchType string
+
+// List of query string parameters of the searches (RFC 9082, section 3.2)
+const (
+	// SearchParameterName searches domains or nameservers by name
+	SearchParameterName SearchParameter = "name"
+
+	// SearchParameterNSLDHName searches domains by the name of one of their
+	// nameservers
+	SearchParameterNSLDHName SearchParameter = "nsLdhName"
+
+	// SearchParameterNSIP searches domains by the IP address of one of their
+	// nameservers
+	SearchParameterNSIP SearchParameter = "nsIp"
+
+	// SearchParameterIP searches nameservers by IP address
+	SearchParameterIP SearchParameter = "ip"
+
+	// SearchParameterFN searches entities by the full name of the jCard
+	SearchParameterFN SearchParameter = "fn"
+
+	// SearchParameterHandle searches entities by handle
+	SearchParameterHandle SearchParameter = "handle"
+)
+
+// SearchParameter stores the query string parameter of the search
+type SearchParameter string
+
+var searchParameters = map[SearchType][]SearchParameter{
+	SearchTypeDomains:     {SearchParameterName, SearchParameterNSLDHName, SearchParameterNSIP},
+	SearchTypeNameservers: {SearchParameterName, SearchParameterIP},
+	SearchTypeEntities:    {SearchParameterFN, SearchParameterHandle},
+}
+
+// SearchPattern is a search value with an optional asterisk that matches zero
+// or more characters (RFC 9082, section 4.1). Name patterns are lower case
+// ASCII (IDNA), except for the partial label before the asterisk
+type SearchPattern struct {
+	Prefix  string
+	Suffix  string
+	Partial bool
+}
+
+// Match checks if the value matches the pattern, ignoring case. When the
+// pattern has a suffix, the asterisk doesn't match dots, so it replaces only
+// the end of a label
+func (s SearchPattern) Match(value string) bool {
+	value = strings.ToLower(value)
+	prefix, suffix := strings.ToLower(s.Prefix), strings.ToLower(s.Suffix)
+
+	if !s.Partial {
+		return value == prefix
+	}
+
+	if len(value) < len(prefix)+len(suffix) ||
+		!strings.HasPrefix(value, prefix) || !strings.HasSuffix(value, suffix) {
+		return false
+	}
+
+	if suffix == "" {
+		return true
+	}
+
+	middle := value[len(prefix) : len(value)-len(suffix)]
+	return !strings.Contains(middle, ".")
+}
+
+// String returns the pattern as it is in the query string
+func (s SearchPattern) String() string {
+	if !s.Partial {
+		return s.Prefix
+	}
+	return s.Prefix + "*" + s.Suffix
+}
+
+// SearchQuery stores the parsed and validated search. Only one parameter is
+// allowed, and the Pattern or the IP is filled according to it
+type SearchQuery struct {
+	Type      SearchType
+	Parameter SearchParameter
+
+	// Pattern is the name, full name or handle searched
+	Pattern SearchPattern
+
+	// IP is the address of the nsIp and ip parameters
+	IP netip.Addr
+
+	// Limit is the maximum number of objects in the response. Backends should
+	// return at most one more object, so the handler can detect that the result
+	// set was truncated or that there's a next page
+	Limit int
+
+	// Offset is the number of objects to skip, used to retrieve the next pages
+	Offset int
+
+	// Sort lists the properties that order the results. Only the properties
+	// declared by a SortingBackend are used
+	Sort []SortKey
+
+	// Count is true when the client requested the total number of objects,
+	// that should be informed in SearchResults.Total
+	Count bool
+
+	// FieldSet is the subset of members returned to the client. The handler
+	// trims the objects, so backends may use it only to avoid loading the
+	// members that aren't returned
+	FieldSet protocol.FieldSet
+}
+
+// PatternError is returned when the search pattern isn't supported. The
+// handler answers with HTTP status 422, as recommended in RFC 9082, section
+// 4.1. Backends can also return it for the partial matches that they don't
+// support
+type PatternError struct {
+	Pattern string
+	Reason  string
+}
+
+// Error implements the error interface
+func (p *PatternError) Error() string {
+	return fmt.Sprintf("unsupported search pattern “%s”: %s", p.Pattern, p.Reason)
+}
+
+// SearchResults is the answer of the backend to a search. When the backend
+// itself limited the results, like for unauthenticated users, it informs the
+// reason in Truncated with one of the protocol.RemarkTypeResultTruncated*
+// values
+type SearchResults[T any] struct {
+	Items     []T
+	Truncated protocol.RemarkType
+
+	// Total is the number of objects that match the search, used only when
+	// the query asks for the count
+	Total int
+}
+
+// SearchBackend is implemented by backends that support the searches of RFC
+// 9082, section 3.2. The searches of backends that don't implement it are
+// answered with HTTP status 501
+type SearchBackend interface {
+	Backend
+
+	SearchDomains(ctx context.Context, query SearchQuery) (SearchResults[protocol.Domain], error)
+	SearchNameservers(ctx context.Context, query SearchQuery) (SearchResults[protocol.Nameserver], error)
+	SearchEntities(ctx context.Context, query SearchQuery) (SearchResults[protocol.Entity], error)
+}
+
+// isSearch checks if the escaped URL path is one of the search types
+func isSearch(path string) bool {
+	_, ok := searchParameters[SearchType(strings.Trim(path, "/"))]
+	return ok
+}
+
+// ParseSearchQuery parses the escaped URL path relative to the RDAP base URL,
+// like "domains", and its query string. Exactly one of the search parameters
+// must be informed, other parameters are ignored. It fails with a QueryError
+// when the search is malformed, or with a PatternError when the partial match
+// isn't supported
+func ParseSearchQuery(path string, values url.Values) (SearchQuery, error) {
+	fail := func(format string, a ...any) (SearchQuery, error) {
+		return SearchQuery{}, &QueryError{
+			Path:   path,
+			Reason: fmt.Sprintf(format, a...),
+		}
+	}
+
+	query := SearchQuery{
+		Type: SearchType(strings.Trim(path, "/")),
+	}
+
+	parameters, ok := searchParameters[query.Type]
+	if !ok {
+		return fail("unknown search type “%s”", query.Type)
+	}
+
+	var names []string
+	for _, parameter := range parameters {
+		if values.Has(string(parameter)) {
+			query.Parameter = parameter
+			names = append(names, string(parameter))
+		}
+	}
+
+	switch {
+	case len(names) == 0:
+		return fail("expected one of the search parameters %s", joinParameters(parameters))
+	case len(names) > 1:
+		return fail("only one search parameter is allowed, got %s", strings.Join(names, ", "))
+	}
+
+	if len(values[string(query.Parameter)]) > 1 {
+		return fail("repeated search parameter %s", query.Parameter)
+	}
+
+	value := values.Get(string(query.Parameter))
+	if value == "" {
+		return fail("empty value for the search parameter %s", query.Parameter)
+	}
+
+	var err error
+
+	switch query.Parameter {
+	case SearchParameterName, SearchParameterNSLDHName:
+		query.Pattern, err = parseNamePattern(value)
+
+	case SearchParameterFN, SearchParameterHandle:
+		query.Pattern, err = parseTrailingPattern(value)
+
+	case SearchParameterNSIP, SearchParameterIP:
+		ip, parseErr := netip.ParseAddr(value)
+		if parseErr != nil || ip.Zone() != "" {
+			return fail("invalid IP address “%s”", value)
+		}
+		query.IP = ip.Unmap()
+	}
+
+	var patternErr *PatternError
+	if errors.As(err, &patternErr) {
+		return SearchQuery{}, err
+	} else if err != nil {
+		return fail("%s", err)
+	}
+
+	return query, nil
+}
+
+// parseNamePattern parses a domain or nameserver name with an optional
+// asterisk at the end of a label, like "exam*" or "exam*.com". The complete
+// labels are converted to IDNA
+func parseNamePattern(value string) (SearchPattern, error) {
+	value = strings.TrimSuffix(strings.ToLower(value), ".")
+
+	prefix, suffix, partial := strings.Cut(value, "*")
+	if !partial {
+		name, err := idna.ToASCII(value)
+		if err != nil || !fqdnRX.MatchString(name) {
+			return SearchPattern{}, fmt.Errorf("invalid name “%s”", value)
+		}
+		return SearchPattern{Prefix: name}, nil
+	}
+
+	if strings.Contains(suffix, "*") {
+		return SearchPattern{}, &PatternError{Pattern: value, Reason: "only one asterisk is allowed"}
+	}
+
+	if prefix == "" {
+		return SearchPattern{}, &PatternError{Pattern: value, Reason: "at least one character must precede the asterisk"}
+	}
+
+	if suffix != "" && !strings.HasPrefix(suffix, ".") {
+		return SearchPattern{}, &PatternError{Pattern: value, Reason: "the asterisk must be at the end of a label"}
+	}
+
+	pattern := SearchPattern{Partial: true}
+
+	// only the complete labels can be converted, the last label of the prefix
+	// may be incomplete
+	completeLabels, partialLabel := "", prefix
+	if i := strings.LastIndex(prefix, "."); i >= 0 {
+		completeLabels, partialLabel = prefix[:i+1], prefix[i+1:]
+	}
+
+	if completeLabels != "" {
+		labels, err := idna.ToASCII(strings.TrimSuffix(completeLabels, "."))
+		if err != nil || !fqdnRX.MatchString(labels+".a") {
+			return SearchPattern{}, fmt.Errorf("invalid name “%s”", value)
+		}
+		completeLabels = labels + "."
+	}
+	pattern.Prefix = completeLabels + partialLabel
+
+	if suffix != "" {
+		labels, err := idna.ToASCII(strings.TrimPrefix(suffix, "."))
+		if err != nil || !fqdnRX.MatchString(labels) {
+			return SearchPattern{}, fmt.Errorf("invalid name “%s”", value)
+		}
+		pattern.Suffix = "." + labels
+	}
+
+	return pattern, nil
+}
+
+// parseTrailingPattern parses a full name or handle with an optional asterisk
+// at the end, like "Joe*"
+func parseTrailingPattern(value string) (SearchPattern, error) {
+	prefix, suffix, partial := strings.Cut(value, "*")
+	if !partial {
+		return SearchPattern{Prefix: value}, nil
+	}
+
+	if suffix != "" {
+		return SearchPattern{}, &PatternError{Pattern: value, Reason: "only a trailing asterisk is allowed"}
+	}
+
+	if prefix == "" {
+		return SearchPattern{}, &PatternError{Pattern: value, Reason: "at least one character must precede the asterisk"}
+	}
+
+	return SearchPattern{Prefix: prefix, Partial: true}, nil
+}
+
+func joinParameters(parameters []SearchParameter) string {
+	names := make([]string, len(parameters))
+	for i, parameter := range parameters {
+		names[i] = string(parameter)
+	}
+	return strings.Join(names, ", ")
+}
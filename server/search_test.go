@@ -0,0 +1,472 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/registrobr/rdap/protocol"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	data := []struct {
+		description   string
+		path          string
+		query         string
+		expected      SearchQuery
+		expectedError error
+	}{
+		{
+			description: "it should parse a domain search by name",
+			path:        "/domains",
+			query:       "name=Example.COM.",
+			expected: SearchQuery{
+				Type:      SearchTypeDomains,
+				Parameter: SearchParameterName,
+				Pattern:   SearchPattern{Prefix: "example.com"},
+			},
+		},
+		{
+			description: "it should parse a partial name with a domain suffix",
+			path:        "/domains",
+			query:       "name=www.exam*.%C3%A7.br",
+			expected: SearchQuery{
+				Type:      SearchTypeDomains,
+				Parameter: SearchParameterName,
+				Pattern:   SearchPattern{Prefix: "www.exam", Suffix: ".xn--7ca.br", Partial: true},
+			},
+		},
+		{
+			description: "it should keep the partial label in Unicode",
+			path:        "/nameservers",
+			query:       "name=caf%C3%A9*",
+			expected: SearchQuery{
+				Type:      SearchTypeNameservers,
+				Parameter: SearchParameterName,
+				Pattern:   SearchPattern{Prefix: "café", Partial: true},
+			},
+		},
+		{
+			description: "it should parse a domain search by nameserver address",
+			path:        "domains/",
+			query:       "nsIp=%3A%3Affff%3A192.0.2.1&other=1",
+			expected: SearchQuery{
+				Type:      SearchTypeDomains,
+				Parameter: SearchParameterNSIP,
+				IP:        netip.MustParseAddr("192.0.2.1"),
+			},
+		},
+		{
+			description: "it should parse an entity search by full name",
+			path:        "/entities",
+			query:       "fn=Joe%20User*",
+			expected: SearchQuery{
+				Type:      SearchTypeEntities,
+				Parameter: SearchParameterFN,
+				Pattern:   SearchPattern{Prefix: "Joe User", Partial: true},
+			},
+		},
+		{
+			description: "it should detect a missing parameter",
+			path:        "/entities",
+			query:       "name=example",
+			expectedError: &QueryError{
+				Path:   "/entities",
+				Reason: "expected one of the search parameters fn, handle",
+			},
+		},
+		{
+			description: "it should detect more than one parameter",
+			path:        "/domains",
+			query:       "name=example.com&nsIp=192.0.2.1",
+			expectedError: &QueryError{
+				Path:   "/domains",
+				Reason: "only one search parameter is allowed, got name, nsIp",
+			},
+		},
+		{
+			description: "it should detect a repeated parameter",
+			path:        "/nameservers",
+			query:       "ip=192.0.2.1&ip=192.0.2.2",
+			expectedError: &QueryError{
+				Path:   "/nameservers",
+				Reason: "repeated search parameter ip",
+			},
+		},
+		{
+			description: "it should detect an empty value",
+			path:        "/entities",
+			query:       "handle=",
+			expectedError: &QueryError{
+				Path:   "/entities",
+				Reason: "empty value for the search parameter handle",
+			},
+		},
+		{
+			description: "it should detect an invalid IP address",
+			path:        "/nameservers",
+			query:       "ip=192.0.2",
+			expectedError: &QueryError{
+				Path:   "/nameservers",
+				Reason: "invalid IP address “192.0.2”",
+			},
+		},
+		{
+			description: "it should detect an invalid name",
+			path:        "/domains",
+			query:       "name=-example.com",
+			expectedError: &QueryError{
+				Path:   "/domains",
+				Reason: "invalid name “-example.com”",
+			},
+		},
+		{
+			description: "it should detect an invalid domain suffix",
+			path:        "/domains",
+			query:       "nsLdhName=ns*.-dns.br",
+			expectedError: &QueryError{
+				Path:   "/domains",
+				Reason: "invalid name “ns*.-dns.br”",
+			},
+		},
+		{
+			description: "it should reject an asterisk inside a label",
+			path:        "/domains",
+			query:       "name=ex*le.com",
+			expectedError: &PatternError{
+				Pattern: "ex*le.com",
+				Reason:  "the asterisk must be at the end of a label",
+			},
+		},
+		{
+			description: "it should reject more than one asterisk",
+			path:        "/domains",
+			query:       "name=ex*.co*",
+			expectedError: &PatternError{
+				Pattern: "ex*.co*",
+				Reason:  "only one asterisk is allowed",
+			},
+		},
+		{
+			description: "it should reject a name pattern without prefix",
+			path:        "/nameservers",
+			query:       "name=*.br",
+			expectedError: &PatternError{
+				Pattern: "*.br",
+				Reason:  "at least one character must precede the asterisk",
+			},
+		},
+		{
+			description: "it should reject an asterisk before the end of the handle",
+			path:        "/entities",
+			query:       "handle=A*B",
+			expectedError: &PatternError{
+				Pattern: "A*B",
+				Reason:  "only a trailing asterisk is allowed",
+			},
+		},
+		{
+			description: "it should reject a handle pattern without prefix",
+			path:        "/entities",
+			query:       "handle=*",
+			expectedError: &PatternError{
+				Pattern: "*",
+				Reason:  "at least one character must precede the asterisk",
+			},
+		},
+		{
+			description: "it should detect an unknown search type",
+			path:        "/autnums",
+			query:       "name=x",
+			expectedError: &QueryError{
+				Path:   "/autnums",
+				Reason: "unknown search type “autnums”",
+			},
+		},
+	}
+
+	for i, item := range data {
+		values, err := url.ParseQuery(item.query)
+		if err != nil {
+			t.Fatalf("[%d] %s: invalid query string: %s", i, item.description, err)
+		}
+
+		query, err := ParseSearchQuery(item.path, values)
+
+		if item.expectedError != nil {
+			if fmt.Sprintf("%v", item.expectedError) != fmt.Sprintf("%v", err) {
+				t.Errorf("[%d] %s: expected error “%v”, got “%v”", i, item.description, item.expectedError, err)
+			}
+
+		} else if err != nil {
+			t.Errorf("[%d] %s: unexpected error “%s”", i, item.description, err)
+
+		} else if !reflect.DeepEqual(item.expected, query) {
+			t.Errorf("[%d] %s: expected “%#v”, got “%#v”", i, item.description, item.expected, query)
+		}
+	}
+}
+
+func TestSearchPatternMatch(t *testing.T) {
+	data := []struct {
+		description string
+		pattern     SearchPattern
+		value       string
+		expected    bool
+	}{
+		{
+			description: "it should match the exact value ignoring case",
+			pattern:     SearchPattern{Prefix: "example.com"},
+			value:       "EXAMPLE.com",
+			expected:    true,
+		},
+		{
+			description: "it should not match a different value",
+			pattern:     SearchPattern{Prefix: "example.com"},
+			value:       "example.com.br",
+		},
+		{
+			description: "it should match the trailing characters",
+			pattern:     SearchPattern{Prefix: "exam", Partial: true},
+			value:       "example.net",
+			expected:    true,
+		},
+		{
+			description: "it should match zero characters",
+			pattern:     SearchPattern{Prefix: "exam", Suffix: ".com", Partial: true},
+			value:       "exam.com",
+			expected:    true,
+		},
+		{
+			description: "it should match the end of the label before the suffix",
+			pattern:     SearchPattern{Prefix: "exam", Suffix: ".com", Partial: true},
+			value:       "example.com",
+			expected:    true,
+		},
+		{
+			description: "it should not match other labels before the suffix",
+			pattern:     SearchPattern{Prefix: "exam", Suffix: ".com", Partial: true},
+			value:       "example.test.com",
+		},
+		{
+			description: "it should not match a different suffix",
+			pattern:     SearchPattern{Prefix: "exam", Suffix: ".com", Partial: true},
+			value:       "example.net",
+		},
+	}
+
+	for i, item := range data {
+		if matched := item.pattern.Match(item.value); matched != item.expected {
+			t.Errorf("[%d] %s: expected %t, got %t", i, item.description, item.expected, matched)
+		}
+	}
+}
+
+func TestHandlerSearch(t *testing.T) {
+	backend, err := NewMemoryBackend(
+		&protocol.Domain{
+			ObjectClassName: "domain",
+			LDHName:         "example.br",
+			Nameservers: []protocol.Nameserver{
+				{ObjectClassName: "nameserver", LDHName: "a.dns.br", IPAddresses: &protocol.IPAddresses{V4: []string{"192.0.2.1"}}},
+			},
+		},
+		&protocol.Domain{ObjectClassName: "domain", LDHName: "example.com.br"},
+		&protocol.Domain{ObjectClassName: "domain", LDHName: "xn--exempl-gva.br", UnicodeName: "exemplé.br"},
+		&protocol.Nameserver{ObjectClassName: "nameserver", LDHName: "a.dns.br", IPAddresses: &protocol.IPAddresses{V6: []string{"2001:db8::1"}}},
+		&protocol.Entity{
+			ObjectClassName: "entity",
+			Handle:          "JOE-1",
+			VCardArray:      []any{"vcard", []any{[]any{"fn", map[string]any{}, "text", "Joe User"}}},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// hides the sorting support, so the responses don't have sorting metadata
+	handler := &Handler{Backend: struct{ SearchBackend }{backend}, SearchLimit: 2}
+
+	data := []struct {
+		description    string
+		target         string
+		expectedStatus int
+		expected       protocol.Object
+	}{
+		{
+			description:    "it should search domains by partial name",
+			target:         "/domains?name=exam*.br",
+			expectedStatus: http.StatusOK,
+			expected: &protocol.DomainSearchResults{
+				Domains: []protocol.Domain{
+					{
+						ObjectClassName: "domain",
+						LDHName:         "example.br",
+						Nameservers: []protocol.Nameserver{
+							{ObjectClassName: "nameserver", LDHName: "a.dns.br", IPAddresses: &protocol.IPAddresses{V4: []string{"192.0.2.1"}}},
+						},
+					},
+				},
+			},
+		},
+		{
+			description:    "it should truncate the result set",
+			target:         "/domains?name=ex*",
+			expectedStatus: http.StatusOK,
+			expected: &protocol.DomainSearchResults{
+				Remarks: []protocol.Remark{
+					{
+						Title:       "Result Set Truncated",
+						Type:        string(protocol.RemarkTypeResultTruncatedUnexplainableReasons),
+						Description: []string{"The result set was limited to 2 objects."},
+					},
+				},
+				Domains: []protocol.Domain{
+					{
+						ObjectClassName: "domain",
+						LDHName:         "example.br",
+						Nameservers: []protocol.Nameserver{
+							{ObjectClassName: "nameserver", LDHName: "a.dns.br", IPAddresses: &protocol.IPAddresses{V4: []string{"192.0.2.1"}}},
+						},
+					},
+					{ObjectClassName: "domain", LDHName: "example.com.br"},
+				},
+			},
+		},
+		{
+			description:    "it should search domains by Unicode name",
+			target:         "/domains?name=exempl%C3%A9*",
+			expectedStatus: http.StatusOK,
+			expected: &protocol.DomainSearchResults{
+				Domains: []protocol.Domain{
+					{ObjectClassName: "domain", LDHName: "xn--exempl-gva.br", UnicodeName: "exemplé.br"},
+				},
+			},
+		},
+		{
+			description:    "it should search domains by nameserver address",
+			target:         "/domains?nsIp=192.0.2.1",
+			expectedStatus: http.StatusOK,
+			expected: &protocol.DomainSearchResults{
+				Domains: []protocol.Domain{
+					{
+						ObjectClassName: "domain",
+						LDHName:         "example.br",
+						Nameservers: []protocol.Nameserver{
+							{ObjectClassName: "nameserver", LDHName: "a.dns.br", IPAddresses: &protocol.IPAddresses{V4: []string{"192.0.2.1"}}},
+						},
+					},
+				},
+			},
+		},
+		{
+			description:    "it should search nameservers by address",
+			target:         "/nameservers?ip=2001:DB8::1",
+			expectedStatus: http.StatusOK,
+			expected: &protocol.NameserverSearchResults{
+				Nameservers: []protocol.Nameserver{
+					{ObjectClassName: "nameserver", LDHName: "a.dns.br", IPAddresses: &protocol.IPAddresses{V6: []string{"2001:db8::1"}}},
+				},
+			},
+		},
+		{
+			description:    "it should search entities by full name",
+			target:         "/entities?fn=joe*",
+			expectedStatus: http.StatusOK,
+			expected: &protocol.EntitySearchResults{
+				Entities: []protocol.Entity{
+					{
+						ObjectClassName: "entity",
+						Handle:          "JOE-1",
+						VCardArray:      []any{"vcard", []any{[]any{"fn", map[string]any{}, "text", "Joe User"}}},
+					},
+				},
+			},
+		},
+		{
+			description:    "it should answer an empty result set",
+			target:         "/entities?handle=UNKNOWN*",
+			expectedStatus: http.StatusOK,
+			expected:       &protocol.EntitySearchResults{Entities: []protocol.Entity{}},
+		},
+		{
+			description:    "it should reject malformed searches",
+			target:         "/nameservers?ip=x",
+			expectedStatus: http.StatusBadRequest,
+			expected: &protocol.Error{
+				ErrorCode:   http.StatusBadRequest,
+				Title:       "Bad Request",
+				Description: []string{"malformed query “/nameservers”: invalid IP address “x”"},
+			},
+		},
+		{
+			description:    "it should reject unsupported patterns",
+			target:         "/domains?name=ex*le.br",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expected: &protocol.Error{
+				ErrorCode:   http.StatusUnprocessableEntity,
+				Title:       "Unprocessable Entity",
+				Description: []string{"unsupported search pattern “ex*le.br”: the asterisk must be at the end of a label"},
+			},
+		},
+	}
+
+	for i, item := range data {
+		r := httptest.NewRequest(http.MethodGet, item.target, nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != item.expectedStatus {
+			t.Errorf("[%d] %s: expected status %d, got %d", i, item.description, item.expectedStatus, w.Code)
+		}
+
+		object := reflect.New(reflect.TypeOf(item.expected).Elem()).Interface()
+		if err := json.Unmarshal(w.Body.Bytes(), object); err != nil {
+			t.Errorf("[%d] %s: invalid body “%s”: %s", i, item.description, w.Body, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(item.expected, object) {
+			t.Errorf("[%d] %s: expected object “%#v”, got “%#v”", i, item.description, item.expected, object)
+		}
+	}
+}
+
+func TestHandlerSearchTruncatedByBackend(t *testing.T) {
+	backend, err := NewMemoryBackend()
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := &Handler{Backend: truncatingBackend{backend}}
+
+	r := httptest.NewRequest(http.MethodGet, "/entities?handle=A*", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	var results protocol.EntitySearchResults
+	if err := json.Unmarshal(w.Body.Bytes(), &results); err != nil {
+		t.Fatalf("invalid body “%s”: %s", w.Body, err)
+	}
+
+	if len(results.Remarks) != 1 || results.Remarks[0].Type != string(protocol.RemarkTypeResultTruncatedAuthorization) {
+		t.Errorf("unexpected remarks “%#v”", results.Remarks)
+	}
+}
+
+// truncatingBackend limits the entity search results due to authorization
+type truncatingBackend struct {
+	*MemoryBackend
+}
+
+func (truncatingBackend) SearchEntities(ctx context.Context, query SearchQuery) (SearchResults[protocol.Entity], error) {
+	return SearchResults[protocol.Entity]{
+		Items:     []protocol.Entity{{ObjectClassName: "entity", Handle: "A-1"}},
+		Truncated: protocol.RemarkTypeResultTruncatedAuthorization,
+	}, nil
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/registrobr/rdap/protocol"
+)
+
+// fieldSets are the field sets supported by the handler (RFC 8982, section 4)
+var fieldSets = []protocol.FieldSet{
+	protocol.FieldSetID,
+	protocol.FieldSetBrief,
+	protocol.FieldSetFull,
+}
+
+// fieldSetDescriptions are informed to the clients in the subsetting metadata
+var fieldSetDescriptions = map[protocol.FieldSet]string{
+	protocol.FieldSetID:    "Only the identifiers and the self link of the objects.",
+	protocol.FieldSetBrief: "The identifiers, status and name of the objects.",
+	protocol.FieldSetFull:  "All the members of the objects.",
+}
+
+// parseFieldSet returns the field set of the fieldSet parameter (RFC 8982,
+// section 2). When the handler doesn't have a default field set the
+// subsetting is disabled and the parameter is ignored
+func (h *Handler) parseFieldSet(path string, values url.Values) (protocol.FieldSet, error) {
+	if h.DefaultFieldSet == "" {
+		return "", nil
+	}
+
+	value := values.Get("fieldSet")
+	if value == "" {
+		return h.DefaultFieldSet, nil
+	}
+
+	fieldSet := protocol.FieldSet(value)
+	if !slices.Contains(fieldSets, fieldSet) {
+		return "", &QueryError{
+			Path:   path,
+			Reason: fmt.Sprintf("unsupported field set “%s”", value),
+		}
+	}
+
+	return fieldSet, nil
+}
+
+// subsettingMetadata lists the field sets supported by the handler. When the
+// request is a search, each field set has a link to the same search in that
+// field set
+func (h *Handler) subsettingMetadata(r *http.Request, searchType SearchType, current protocol.FieldSet) *protocol.SubsettingMetadata {
+	if h.DefaultFieldSet == "" {
+		return nil
+	}
+
+	metadata := &protocol.SubsettingMetadata{
+		CurrentFieldSet: current,
+	}
+
+	for _, fieldSet := range fieldSets {
+		available := protocol.AvailableFieldSet{
+			Name:        fieldSet,
+			Default:     fieldSet == h.DefaultFieldSet,
+			Description: fieldSetDescriptions[fieldSet],
+		}
+
+		if searchType != "" {
+			base, value := searchURLs(h, r, searchType)
+
+			values := r.URL.Query()
+			values.Set("fieldSet", string(fieldSet))
+
+			available.Links = []protocol.Link{
+				{
+					Value: value,
+					Rel:   "alternate",
+					Href:  base + "?" + values.Encode(),
+					Type:  "application/rdap+json",
+				},
+			}
+		}
+
+		metadata.AvailableFieldSets = append(metadata.AvailableFieldSets, available)
+	}
+
+	return metadata
+}
+
+// subset returns the subsets of the items in the field set. The items are
+// copied to a new slice, as the backing array may be shared by the backend
+func subset[T any](items []T, fieldSet protocol.FieldSet, subset func(*T, protocol.FieldSet) *T) []T {
+	if fieldSet == "" || fieldSet == protocol.FieldSetFull {
+		return items
+	}
+
+	subsets := make([]T, len(items))
+	for i := range items {
+		subsets[i] = *subset(&items[i], fieldSet)
+	}
+	return subsets
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/registrobr/rdap/protocol"
+)
+
+func TestHandlerSubsetting(t *testing.T) {
+	backend, err := NewMemoryBackend(
+		&protocol.Domain{
+			ObjectClassName: "domain",
+			Handle:          "EXAMPLE-1",
+			LDHName:         "example.br",
+			Status:          []protocol.Status{protocol.StatusActive},
+			Entities:        []protocol.Entity{{ObjectClassName: "entity", Handle: "XXX"}},
+		},
+		&protocol.Entity{
+			ObjectClassName: "entity",
+			Handle:          "XXX",
+			VCardArray: []any{
+				"vcard",
+				[]any{
+					[]any{"version", map[string]any{}, "text", "4.0"},
+					[]any{"fn", map[string]any{}, "text", "Joe User"},
+					[]any{"email", map[string]any{}, "text", "joe@example.br"},
+				},
+			},
+		},
+		&protocol.Help{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := NewHandler(backend)
+
+	get := func(target string, object any) int {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), object); err != nil {
+				t.Fatalf("invalid body “%s”: %s", w.Body, err)
+			}
+		}
+		return w.Code
+	}
+
+	var domains protocol.DomainSearchResults
+	if status := get("/domains?name=example*&fieldSet=id", &domains); status != http.StatusOK {
+		t.Fatalf("unexpected status %d", status)
+	}
+
+	expectedDomains := []protocol.Domain{
+		{
+			ObjectClassName: "domain",
+			LDHName:         "example.br",
+			Links: []protocol.Link{
+				{
+					Value: "http://example.com/domain/example.br",
+					Rel:   "self",
+					Href:  "http://example.com/domain/example.br",
+					Type:  "application/rdap+json",
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(expectedDomains, domains.Domains) {
+		t.Errorf("expected domains “%#v”, got “%#v”", expectedDomains, domains.Domains)
+	}
+
+	metadata := domains.SubsettingMetadata
+	if metadata == nil || metadata.CurrentFieldSet != protocol.FieldSetID || len(metadata.AvailableFieldSets) != 3 {
+		t.Fatalf("unexpected subsetting metadata “%#v”", metadata)
+	}
+
+	brief := metadata.AvailableFieldSets[1]
+	if brief.Name != protocol.FieldSetBrief || brief.Default || len(brief.Links) != 1 {
+		t.Fatalf("unexpected brief field set “%#v”", brief)
+	}
+
+	link, err := url.Parse(brief.Links[0].Href)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fieldSet := link.Query().Get("fieldSet"); fieldSet != "brief" {
+		t.Errorf("unexpected field set “%s” in the alternate link", fieldSet)
+	}
+
+	if !metadata.AvailableFieldSets[2].Default {
+		t.Errorf("expected the full field set as default")
+	}
+
+	expectedLevels := []string{"rdap_level_0", "sorting", "subsetting"}
+	if !reflect.DeepEqual(expectedLevels, domains.Levels) {
+		t.Errorf("expected conformance “%v”, got “%v”", expectedLevels, domains.Levels)
+	}
+
+	var entities protocol.EntitySearchResults
+	get("/entities?handle=XXX&fieldSet=brief", &entities)
+	if len(entities.Entities) != 1 || entities.Entities[0].FullName() != "Joe User" || len(entities.Entities[0].Emails()) > 0 {
+		t.Errorf("unexpected brief entities “%#v”", entities.Entities)
+	}
+
+	// the default field set returns the full objects
+	domains = protocol.DomainSearchResults{}
+	get("/domains?name=example*", &domains)
+	if len(domains.Domains) != 1 || len(domains.Domains[0].Entities) != 1 || domains.SubsettingMetadata.CurrentFieldSet != protocol.FieldSetFull {
+		t.Errorf("unexpected full domains “%#v”", domains)
+	}
+
+	if status := get("/domains?name=example*&fieldSet=other", &domains); status != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an unsupported field set, got %d", status)
+	}
+
+	var help protocol.Help
+	get("/help", &help)
+	if help.SubsettingMetadata == nil || len(help.SubsettingMetadata.AvailableFieldSets) != 3 ||
+		help.SubsettingMetadata.AvailableFieldSets[0].Links != nil {
+		t.Errorf("unexpected help subsetting metadata “%#v”", help.SubsettingMetadata)
+	}
+
+	// without default field set the subsetting is disabled
+	handler.DefaultFieldSet = ""
+	domains = protocol.DomainSearchResults{}
+	get("/domains?name=example*&fieldSet=other", &domains)
+	if len(domains.Domains) != 1 || len(domains.Domains[0].Entities) != 1 || domains.SubsettingMetadata != nil {
+		t.Errorf("unexpected domains without subsetting “%#v”", domains)
+	}
+
+	help = protocol.Help{}
+	get("/help", &help)
+	if help.SubsettingMetadata != nil || !reflect.DeepEqual([]string{"rdap_level_0"}, help.Levels) {
+		t.Errorf("unexpected help without subsetting “%#v”", help)
+	}
+}
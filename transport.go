@@ -1,6 +1,7 @@
 package rdap
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,6 +11,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/registrobr/rdap/protocol"
 )
@@ -40,6 +42,20 @@ const (
 	QueryTypeEntity QueryType = "entity"
 )
 
+// List of resource type path segments for searches as described in RFC 9082,
+// section 3.2. The search parameters are sent in the query string
+const (
+	// QueryTypeDomains used to search domains by name, nameserver name or
+	// nameserver IP address
+	QueryTypeDomains QueryType = "domains"
+
+	// QueryTypeNameservers used to search nameservers by name or IP address
+	QueryTypeNameservers QueryType = "nameservers"
+
+	// QueryTypeEntities used to search entities by full name or handle
+	QueryTypeEntities QueryType = "entities"
+)
+
 // QueryType stores the query type when sending a query to an RDAP server
 type QueryType string
 
@@ -183,7 +199,12 @@ func (d *defaultFetcher) fetchURI(uri string, queryType QueryType, queryValue st
 	}
 
 	uri = strings.TrimRight(uri, "/")
-	uri = fmt.Sprintf("%s/%s/%s", uri, queryType, queryValue)
+	if queryValue == "" {
+		// searches don't have a value in the path
+		uri = fmt.Sprintf("%s/%s", uri, queryType)
+	} else {
+		uri = fmt.Sprintf("%s/%s/%s", uri, queryType, queryValue)
+	}
 
 	if q := queryString.Encode(); len(q) > 0 {
 		uri += "?" + q
@@ -201,7 +222,13 @@ func (d *defaultFetcher) fetchURI(uri string, queryType QueryType, queryValue st
 	req.Header.Set("Accept", "application/rdap+json")
 	req.Header.Set("User-Agent", "registrobr-rdap")
 
-	resp, err := d.httpClient.Do(req)
+	return fetchRequest(d.httpClient, req)
+}
+
+// fetchRequest sends the RDAP request and checks the response status and
+// content type. Error responses are decoded into a protocol.Error
+func fetchRequest(httpClient httpClient, req *http.Request) (*http.Response, error) {
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -228,6 +255,7 @@ func (d *defaultFetcher) fetchURI(uri string, queryType QueryType, queryValue st
 		if err := json.NewDecoder(resp.Body).Decode(&responseErr); err != nil {
 			return nil, err
 		}
+		responseErr.RetryAfter = retryAfter(resp.Header)
 
 		return nil, responseErr
 	}
@@ -235,15 +263,98 @@ func (d *defaultFetcher) fetchURI(uri string, queryType QueryType, queryValue st
 	return resp, nil
 }
 
+// retryAfter parses the Retry-After HTTP header, that can be in seconds or an
+// HTTP date (RFC 9110, section 10.2.3)
+func retryAfter(header http.Header) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+
+	return 0
+}
+
 // NewBootstrapFetcher returns a transport layer that tries to find the
 // resource in a bootstrap strategy to detect the RDAP servers that can contain
 // the information. After finding the RDAP servers, it will send the requests to
 // retrieve the desired information
 func NewBootstrapFetcher(httpClient httpClient, bootstrapURI string, cacheDetector CacheDetector) Fetcher {
-	return decorate(
-		NewDefaultFetcher(httpClient),
-		bootstrap(bootstrapURI, httpClient, cacheDetector),
-	)
+	direct := NewDefaultFetcher(httpClient)
+
+	return &bootstrapFetcher{
+		Fetcher:       decorate(direct, bootstrap(bootstrapURI, httpClient, cacheDetector)),
+		direct:        direct,
+		httpClient:    httpClient,
+		bootstrapURI:  bootstrapURI,
+		cacheDetector: cacheDetector,
+	}
+}
+
+// bootstrapFetcher keeps the transport layer without bootstrap, so URLs that
+// must not be bootstrapped, like the links of the objects, can be retrieved
+// directly
+type bootstrapFetcher struct {
+	Fetcher
+	direct        Fetcher
+	httpClient    httpClient
+	bootstrapURI  string
+	cacheDetector CacheDetector
+}
+
+// ContextFetcher is implemented by the transport layers that can bind their
+// requests, including the bootstrap ones, to a context, so they are
+// interrupted when the context is canceled or its deadline expires
+type ContextFetcher interface {
+	Fetcher
+
+	// WithContext returns a copy of the transport layer with the context
+	WithContext(ctx context.Context) Fetcher
+}
+
+// WithContext implements the ContextFetcher interface
+func (d *defaultFetcher) WithContext(ctx context.Context) Fetcher {
+	return NewDefaultFetcher(contextHTTPClient{ctx: ctx, httpClient: d.httpClient})
+}
+
+// WithContext implements the ContextFetcher interface
+func (b *bootstrapFetcher) WithContext(ctx context.Context) Fetcher {
+	return NewBootstrapFetcher(contextHTTPClient{ctx: ctx, httpClient: b.httpClient}, b.bootstrapURI, b.cacheDetector)
+}
+
+// fetcherWithContext binds the transport layer to the context when it is
+// supported. Other transport layers are returned as they are
+func fetcherWithContext(f Fetcher, ctx context.Context) Fetcher {
+	if contextFetcher, ok := f.(ContextFetcher); ok {
+		return contextFetcher.WithContext(ctx)
+	}
+	return f
+}
+
+// directFetcher returns the transport layer that sends the requests to the
+// informed URIs, skipping the bootstrap of this package
+func directFetcher(f Fetcher) Fetcher {
+	if bootstrapFetcher, ok := f.(*bootstrapFetcher); ok {
+		return bootstrapFetcher.direct
+	}
+	return f
+}
+
+// contextHTTPClient sends all the requests with the context
+type contextHTTPClient struct {
+	ctx        context.Context
+	httpClient httpClient
+}
+
+func (c contextHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	return c.httpClient.Do(req.WithContext(c.ctx))
 }
 
 func bootstrap(bootstrapURI string, httpClient httpClient, cacheDetector CacheDetector) decorator {
@@ -241,6 +241,42 @@ func TestDefaultFetcherFetch(t *testing.T) {
 	}
 }
 
+func TestRetryAfter(t *testing.T) {
+	data := []struct {
+		description string
+		header      http.Header
+		expected    time.Duration
+	}{
+		{
+			description: "it should parse the delay in seconds",
+			header:      http.Header{"Retry-After": []string{"120"}},
+			expected:    2 * time.Minute,
+		},
+		{
+			description: "it should ignore dates in the past",
+			header:      http.Header{"Retry-After": []string{"Wed, 21 Oct 2015 07:28:00 GMT"}},
+		},
+		{
+			description: "it should ignore invalid values",
+			header:      http.Header{"Retry-After": []string{"soon"}},
+		},
+		{
+			description: "it should ignore a missing header",
+		},
+	}
+
+	for i, item := range data {
+		if delay := retryAfter(item.header); delay != item.expected {
+			t.Errorf("[%d] %s: expected “%s”, got “%s”", i, item.description, item.expected, delay)
+		}
+	}
+
+	date := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	if delay := retryAfter(http.Header{"Retry-After": []string{date}}); delay <= 58*time.Minute || delay > time.Hour {
+		t.Errorf("unexpected delay “%s” for date “%s”", delay, date)
+	}
+}
+
 func TestBootstrap(t *testing.T) {
 	data := []struct {
 		description   string